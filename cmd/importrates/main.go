@@ -0,0 +1,60 @@
+// Command importrates loads exchange rates from a CSV file with the columns
+// date, base, quote and rate.
+//
+//	go run ./cmd/importrates rates.csv [source]
+package main
+
+import (
+	"balanca/internal/config"
+	"balanca/internal/database"
+	"balanca/internal/repositories"
+	"balanca/internal/services"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("Usage: importrates <file.csv> [source]")
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load configuration:", err)
+	}
+
+	if err := database.Connect(&cfg.Database); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+
+	path := os.Args[1]
+	source := filepath.Base(path)
+	if len(os.Args) > 2 {
+		source = os.Args[2]
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Failed to open rates file:", err)
+	}
+	defer file.Close()
+
+	exchangeRateService := services.NewExchangeRateService(repositories.NewExchangeRateRepository(database.GetDB()))
+
+	result, err := exchangeRateService.ImportCSV(file, source)
+	if err != nil {
+		log.Fatal("Failed to import exchange rates:", err)
+	}
+
+	for _, message := range result.Errors {
+		log.Println(message)
+	}
+	log.Printf("Imported %d exchange rates, skipped %d rows", result.Imported, result.Skipped)
+}
@@ -1,16 +1,24 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Logging  LoggingConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Logging   LoggingConfig
+	Storage   StorageConfig
+	Money     MoneyConfig
+	Reminders ReminderConfig
 }
 
 type ServerConfig struct {
@@ -39,12 +47,45 @@ type LoggingConfig struct {
 	Level string
 }
 
+type MoneyConfig struct {
+	DefaultCurrency  string
+	DefaultLocale    string
+	RatesImportToken string // empty disables the exchange rate import endpoint
+}
+
+// ReminderConfig schedules due date reminders for planned expenses, in days
+// before and after the due date. Overdue group expenses are escalated to the
+// group managers once EscalationDays have passed.
+type ReminderConfig struct {
+	DaysBefore     []int
+	DaysAfter      []int
+	EscalationDays int
+}
+
+type StorageConfig struct {
+	Path          string
+	MaxUploadSize int64
+	URLSecret     string
+	URLExpiration time.Duration
+}
+
 func Load() (*Config, error) {
 	port := getEnv("SERVER_PORT", "8080")
 	host := getEnv("SERVER_HOST", "0.0.0.0")
 
 	jwtExp, _ := time.ParseDuration(getEnv("JWT_EXPIRATION", "24h"))
 	refreshExp, _ := time.ParseDuration(getEnv("REFRESH_TOKEN_EXPIRATION", "168h"))
+	urlExp, _ := time.ParseDuration(getEnv("SIGNED_URL_EXPIRATION", "15m"))
+	jwtSecret := getEnv("JWT_SECRET", "your-secret-key")
+
+	urlSecret := getEnv("SIGNED_URL_SECRET", "")
+	if urlSecret == "" {
+		derived, err := deriveSecret(jwtSecret, "balanca signed urls")
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive signed URL secret: %w", err)
+		}
+		urlSecret = derived
+	}
 
 	return &Config{
 		Server: ServerConfig{
@@ -62,16 +103,42 @@ func Load() (*Config, error) {
 			DBURL:    getEnv("DBURL", ""),
 		},
 		JWT: JWTConfig{
-			Secret:                 getEnv("JWT_SECRET", "your-secret-key"),
+			Secret:                 jwtSecret,
 			Expiration:             jwtExp,
 			RefreshTokenExpiration: refreshExp,
 		},
 		Logging: LoggingConfig{
 			Level: getEnv("LOG_LEVEL", "debug"),
 		},
+		Storage: StorageConfig{
+			Path:          getEnv("STORAGE_PATH", "./uploads"),
+			MaxUploadSize: int64(getEnvAsInt("MAX_UPLOAD_SIZE", 10<<20)),
+			URLSecret:     urlSecret,
+			URLExpiration: urlExp,
+		},
+		Money: MoneyConfig{
+			DefaultCurrency:  getEnv("DEFAULT_CURRENCY", "USD"),
+			DefaultLocale:    getEnv("DEFAULT_LOCALE", "en-US"),
+			RatesImportToken: getEnv("RATES_IMPORT_TOKEN", ""),
+		},
+		Reminders: ReminderConfig{
+			DaysBefore:     getEnvAsIntList("REMINDER_DAYS_BEFORE", []int{3, 1}),
+			DaysAfter:      getEnvAsIntList("REMINDER_DAYS_AFTER", []int{1, 3}),
+			EscalationDays: getEnvAsInt("REMINDER_ESCALATION_DAYS", 7),
+		},
 	}, nil
 }
 
+// deriveSecret derives a key for one purpose from a shared secret with HKDF,
+// so a key leaked from one use cannot be replayed against another.
+func deriveSecret(secret, purpose string) (string, error) {
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, purpose, 32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value, exists := os.LookupEnv(key); exists {
 		return value
@@ -85,4 +152,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvAsIntList reads a comma separated list of non-negative integers.
+func getEnvAsIntList(key string, defaultValue []int) []int {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(valueStr, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 0 {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
@@ -50,15 +50,52 @@ func AutoMigrate() error {
 		&md.Group{},
 		&md.UserGroup{},
 		&md.Transaction{},
+		&md.TransactionLineItem{},
 		&md.PlannedExpense{},
 		&md.AuditLog{},
 		&md.Notification{},
+		&md.Tag{},
+		&md.Category{},
+		&md.Attachment{},
+		&md.ExchangeRate{},
+		&md.ImportProfile{},
+		&md.ImportBatch{},
+		&md.ImportRow{},
+		&md.CategorizationRule{},
+		&md.Budget{},
+		&md.SpendingAnomaly{},
+		&md.ExpenseRecurrence{},
+		&md.ApprovalPolicy{},
+		&md.ExpenseApproval{},
+		&md.ExpenseProposal{},
+		&md.ProposalVote{},
+		&md.ExpensePayment{},
+		&md.Reimbursement{},
+		&md.ExpenseSplit{},
+		&md.ExpenseSplitShare{},
+		&md.Settlement{},
+		&md.ShoppingList{},
+		&md.ShoppingListItem{},
+		&md.ExpenseReminder{},
 	}
 
 	if err := DB.AutoMigrate(models...); err != nil {
 		return fmt.Errorf("failed to auto migrate: %w", err)
 	}
 
+	// Keyset pagination orders by (time, id) within one owner; these back it.
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS idx_transactions_user_keyset ON transactions (user_id, created_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_transactions_group_keyset ON transactions (group_id, created_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_performer_keyset ON audit_logs (performed_by, performed_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_audit_logs_group_keyset ON audit_logs (group_id, performed_at DESC, id DESC)",
+	}
+	for _, index := range indexes {
+		if err := DB.Exec(index).Error; err != nil {
+			return fmt.Errorf("failed to create index: %w", err)
+		}
+	}
+
 	return nil
 }
 
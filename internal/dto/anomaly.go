@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// AnomalyResponse describes a DEBIT far above the owner's usual spending in
+// its category. Score is a robust z-score: how many (scaled) median absolute
+// deviations the amount lies above the median.
+type AnomalyResponse struct {
+	ID              uuid.UUID `json:"id"`
+	TransactionID   uuid.UUID `json:"transaction_id"`
+	OwnerType       string    `json:"owner_type"`
+	OwnerID         uuid.UUID `json:"owner_id"`
+	Category        string    `json:"category"`
+	Description     string    `json:"description,omitempty"`
+	Amount          int64     `json:"amount"`
+	Currency        string    `json:"currency"`
+	FormattedAmount string    `json:"formatted_amount"`
+	Median          int64     `json:"median"`
+	FormattedMedian string    `json:"formatted_median"`
+	MAD             int64     `json:"mad"`
+	Score           float64   `json:"score"`
+	SampleSize      int       `json:"sample_size"`
+	WindowDays      int       `json:"window_days"`
+	RecordedBy      uuid.UUID `json:"recorded_by"`
+	CreatedAt       string    `json:"created_at"`
+}
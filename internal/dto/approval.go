@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPolicyRequest sets when a group's planned expenses need approval.
+// RequiredApprovals applies to the managers mode and defaults to 1.
+type ApprovalPolicyRequest struct {
+	Threshold         int64  `json:"threshold" binding:"min=0"`
+	Mode              string `json:"mode" binding:"required,oneof=managers majority"`
+	RequiredApprovals int    `json:"required_approvals" binding:"omitempty,min=1,max=20"`
+}
+
+type ApprovalPolicyResponse struct {
+	GroupID            uuid.UUID `json:"group_id"`
+	Threshold          int64     `json:"threshold"`
+	FormattedThreshold string    `json:"formatted_threshold"`
+	Currency           string    `json:"currency"`
+	Mode               string    `json:"mode"`
+	RequiredApprovals  int       `json:"required_approvals,omitempty"`
+	UpdatedBy          uuid.UUID `json:"updated_by"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// ApprovalDecisionRequest carries the reason for a decision. Rejections
+// must give one.
+type ApprovalDecisionRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type ApprovalDecisionResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Decision  string    `json:"decision"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExpenseApprovalResponse is the state of an expense's approval. Required is
+// the number of approvals it needs, counting the requester in majority mode.
+type ExpenseApprovalResponse struct {
+	ExpenseID  uuid.UUID                  `json:"expense_id"`
+	Status     string                     `json:"status"`
+	Mode       string                     `json:"mode,omitempty"`
+	Approvals  int                        `json:"approvals"`
+	Rejections int                        `json:"rejections"`
+	Required   int                        `json:"required"`
+	Decisions  []ApprovalDecisionResponse `json:"decisions"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/google/uuid"
+
+type AttachmentResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	TransactionID    *uuid.UUID `json:"transaction_id,omitempty"`
+	PlannedExpenseID *uuid.UUID `json:"planned_expense_id,omitempty"`
+	FileName         string     `json:"file_name"`
+	ContentType      string     `json:"content_type"`
+	Size             int64      `json:"size"`
+	Checksum         string     `json:"checksum"`
+	UploadedBy       uuid.UUID  `json:"uploaded_by"`
+	CreatedAt        string     `json:"created_at"`
+}
+
+type AttachmentURLResponse struct {
+	URL       string `json:"url"`
+	ExpiresAt string `json:"expires_at"`
+}
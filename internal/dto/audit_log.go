@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditLogResponse struct {
+	ID            uuid.UUID              `json:"id"`
+	Entity        string                 `json:"entity"`
+	EntityID      uuid.UUID              `json:"entity_id"`
+	Action        string                 `json:"action"`
+	Changes       map[string]interface{} `json:"changes"`
+	PerformedBy   uuid.UUID              `json:"performed_by"`
+	PerformerName string                 `json:"performer_name"`
+	PerformedAt   time.Time              `json:"performed_at"`
+	GroupID       *uuid.UUID             `json:"group_id,omitempty"`
+}
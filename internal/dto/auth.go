@@ -6,6 +6,7 @@ type RegisterRequest struct {
 	FirstName   string `json:"first_name" binding:"required"`
 	LastName    string `json:"last_name" binding:"required"`
 	Password    string `json:"password" binding:"required,min=6"`
+	Currency    string `json:"currency" binding:"omitempty,iso4217"` // defaults to the server's default currency
 }
 
 type LoginRequest struct {
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// BudgetRequest creates a budget or replaces all its fields. StartDate
+// (YYYY-MM-DD) picks the first period and defaults to the current one;
+// rollover counts from there.
+type BudgetRequest struct {
+	Category  string `json:"category" binding:"required"`
+	Period    string `json:"period" binding:"required,oneof=weekly monthly yearly"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"`
+	Rollover  bool   `json:"rollover"`
+	StartDate string `json:"start_date"`
+}
+
+type BudgetResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	UserID          *uuid.UUID `json:"user_id,omitempty"`
+	GroupID         *uuid.UUID `json:"group_id,omitempty"`
+	Category        string     `json:"category"`
+	Period          string     `json:"period"`
+	Amount          int64      `json:"amount"`
+	FormattedAmount string     `json:"formatted_amount"`
+	Currency        string     `json:"currency"`
+	Rollover        bool       `json:"rollover"`
+	StartDate       string     `json:"start_date"`
+	CreatedBy       uuid.UUID  `json:"created_by"`
+	CreatedAt       string     `json:"created_at"`
+}
+
+// BudgetReportFilter picks the day whose periods are reported, as
+// YYYY-MM-DD. It defaults to today.
+type BudgetReportFilter struct {
+	Date string `form:"date"`
+}
+
+// BudgetReportResponse compares each budget with the spending recorded in
+// its category during the period that contains Date.
+type BudgetReportResponse struct {
+	Date           string         `json:"date"`
+	Currency       string         `json:"currency"`
+	TotalBudgeted  int64          `json:"total_budgeted"`
+	TotalSpent     int64          `json:"total_spent"`
+	TotalRemaining int64          `json:"total_remaining"`
+	Budgets        []BudgetActual `json:"budgets"`
+}
+
+type BudgetActual struct {
+	BudgetID    uuid.UUID `json:"budget_id"`
+	Category    string    `json:"category"`
+	Period      string    `json:"period"`
+	PeriodStart string    `json:"period_start"`
+	PeriodEnd   string    `json:"period_end"`
+	Amount      int64     `json:"amount"`
+	Carryover   int64     `json:"carryover"` // from earlier periods when rolling over; negative after overspending
+	Available   int64     `json:"available"` // Amount + Carryover
+	Spent       int64     `json:"spent"`
+	Remaining   int64     `json:"remaining"` // negative when over budget
+	PercentUsed float64   `json:"percent_used"`
+	OverBudget  bool      `json:"over_budget"`
+}
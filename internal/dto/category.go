@@ -0,0 +1,49 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateCategoryRequest struct {
+	Name     string     `json:"name" binding:"required,max=50"`
+	Kind     string     `json:"kind" binding:"required,oneof=category source"`
+	Icon     string     `json:"icon" binding:"max=50"`
+	Color    string     `json:"color" binding:"omitempty,hexcolor"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdateCategoryRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Icon  *string `json:"icon" binding:"omitempty,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor"`
+
+	// Set ParentID to move the entry; set ClearParent to make it top-level
+	ParentID    *uuid.UUID `json:"parent_id"`
+	ClearParent bool       `json:"clear_parent"`
+}
+
+// MergeCategoriesRequest folds catalog entries and/or free-form names that
+// were used before the catalog existed into the target entry. When the target
+// is a system entry, GroupID selects the group whose history is rewritten;
+// otherwise the caller's personal history is.
+type MergeCategoriesRequest struct {
+	SourceIDs []uuid.UUID `json:"source_ids"`
+	Names     []string    `json:"names" binding:"omitempty,dive,max=100"`
+	GroupID   *uuid.UUID  `json:"group_id"`
+}
+
+// CategoryFilter holds the optional query parameters of catalog listings.
+type CategoryFilter struct {
+	Kind string `form:"kind" binding:"omitempty,oneof=category source"`
+}
+
+type CategoryResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Kind      string     `json:"kind"`
+	Icon      string     `json:"icon"`
+	Color     string     `json:"color"`
+	ParentID  *uuid.UUID `json:"parent_id,omitempty"`
+	IsSystem  bool       `json:"is_system"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	GroupID   *uuid.UUID `json:"group_id,omitempty"`
+	CreatedAt string     `json:"created_at"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+type ExchangeRateFilter struct {
+	Base  string `form:"base" binding:"required,iso4217"`
+	Quote string `form:"quote" binding:"required,iso4217"`
+	Date  string `form:"date"` // YYYY-MM-DD, defaults to today
+}
+
+type ExchangeRateResponse struct {
+	BaseCurrency  string `json:"base_currency"`
+	QuoteCurrency string `json:"quote_currency"`
+	Rate          string `json:"rate"`
+	EffectiveDate string `json:"effective_date"`
+	Inverse       bool   `json:"inverse"` // derived from the quote/base rate
+}
+
+type ImportExchangeRatesResponse struct {
+	Imported int      `json:"imported"`
+	Skipped  int      `json:"skipped"`
+	Errors   []string `json:"errors,omitempty"`
+}
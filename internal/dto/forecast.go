@@ -0,0 +1,63 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ForecastFilter shapes a balance forecast. Days defaults to 30 and Months,
+// the income history averaged, to 3. Amount, with an optional Date
+// (YYYY-MM-DD, defaults to today) and Item, adds a purchase being considered
+// to check whether it is affordable.
+type ForecastFilter struct {
+	Days   int    `form:"days" binding:"omitempty,min=1,max=365"`
+	Months int    `form:"months" binding:"omitempty,min=1,max=12"`
+	Amount int64  `form:"amount" binding:"omitempty,min=1"`
+	Date   string `form:"date"`
+	Item   string `form:"item" binding:"omitempty,max=255"`
+}
+
+// ForecastResponse projects the balance day by day from today, adding the
+// average daily income and taking off open planned expenses on their due
+// date. Affordable is false when the balance goes negative in the window.
+type ForecastResponse struct {
+	Currency          string            `json:"currency"`
+	StartDate         string            `json:"start_date"`
+	EndDate           string            `json:"end_date"`
+	StartingBalance   int64             `json:"starting_balance"`
+	EndingBalance     int64             `json:"ending_balance"`
+	LowestBalance     int64             `json:"lowest_balance"`
+	LowestBalanceDate string            `json:"lowest_balance_date"`
+	FirstNegativeDate *string           `json:"first_negative_date"`
+	Affordable        bool              `json:"affordable"`
+	IncomeMonths      int               `json:"income_months"`
+	Income            []ForecastIncome  `json:"income"`
+	Expenses          []ForecastExpense `json:"expenses"`
+	Unscheduled       []ForecastExpense `json:"unscheduled"` // open expenses without a due date, not projected
+	Days              []ForecastDay     `json:"days"`
+}
+
+type ForecastIncome struct {
+	Source         string `json:"source"`
+	Total          int64  `json:"total"` // over the income history
+	MonthlyAverage int64  `json:"monthly_average"`
+}
+
+type ForecastExpense struct {
+	ExpenseID      *uuid.UUID `json:"expense_id"` // nil for the purchase being considered
+	Item           string     `json:"item"`
+	Priority       string     `json:"priority"`
+	Status         string     `json:"status"`
+	DueDate        string     `json:"due_date"`
+	Date           string     `json:"date"` // projected payment day; overdue expenses fall on the first day
+	Amount         int64      `json:"amount"`
+	BalanceAfter   int64      `json:"balance_after"`
+	Overdue        bool       `json:"overdue"`
+	Hypothetical   bool       `json:"hypothetical"`
+	PushesNegative bool       `json:"pushes_negative"` // takes the balance from zero or above to below zero
+}
+
+type ForecastDay struct {
+	Date     string `json:"date"`
+	Income   int64  `json:"income"`
+	Expenses int64  `json:"expenses"`
+	Balance  int64  `json:"balance"`
+	Negative bool   `json:"negative"`
+}
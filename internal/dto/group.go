@@ -5,6 +5,7 @@ import "github.com/google/uuid"
 type CreateGroupRequest struct {
 	Name        string `json:"name" binding:"required"`
 	Description string `json:"description"`
+	Currency    string `json:"currency" binding:"omitempty,iso4217"` // defaults to the server's default currency
 }
 
 type GroupResponse struct {
@@ -12,6 +13,7 @@ type GroupResponse struct {
 	Name        string           `json:"name"`
 	Description string           `json:"description"`
 	Balance     int64            `json:"balance"`
+	Currency    string           `json:"currency"`
 	CreatedBy   uuid.UUID        `json:"created_by"`
 	IsActive    bool             `json:"is_active"`
 	CreatedAt   string           `json:"created_at"`
@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateImportProfileRequest struct {
+	Name              string `json:"name" binding:"required,max=100"`
+	Delimiter         string `json:"delimiter" binding:"omitempty,len=1"` // defaults to ","
+	HasHeader         *bool  `json:"has_header"`                          // defaults to true
+	DateColumn        string `json:"date_column" binding:"required"`
+	DateFormat        string `json:"date_format"` // e.g. DD/MM/YYYY, defaults to YYYY-MM-DD
+	AmountColumn      string `json:"amount_column" binding:"required_unless=SignConvention split"`
+	DebitColumn       string `json:"debit_column" binding:"required_if=SignConvention split"`
+	CreditColumn      string `json:"credit_column" binding:"required_if=SignConvention split"`
+	DescriptionColumn string `json:"description_column"`
+	CategoryColumn    string `json:"category_column"`
+	SignConvention    string `json:"sign_convention" binding:"omitempty,oneof=signed inverted split"`
+	Locale            string `json:"locale"` // e.g. fr-FR; empty guesses separators
+	DefaultCategory   string `json:"default_category"`
+	DefaultSource     string `json:"default_source"`
+}
+
+type ImportProfileResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	Name              string     `json:"name"`
+	UserID            *uuid.UUID `json:"user_id,omitempty"`
+	GroupID           *uuid.UUID `json:"group_id,omitempty"`
+	Delimiter         string     `json:"delimiter"`
+	HasHeader         bool       `json:"has_header"`
+	DateColumn        string     `json:"date_column"`
+	DateFormat        string     `json:"date_format"`
+	AmountColumn      string     `json:"amount_column,omitempty"`
+	DebitColumn       string     `json:"debit_column,omitempty"`
+	CreditColumn      string     `json:"credit_column,omitempty"`
+	DescriptionColumn string     `json:"description_column,omitempty"`
+	CategoryColumn    string     `json:"category_column,omitempty"`
+	SignConvention    string     `json:"sign_convention"`
+	Locale            string     `json:"locale,omitempty"`
+	DefaultCategory   string     `json:"default_category"`
+	DefaultSource     string     `json:"default_source"`
+	CreatedBy         uuid.UUID  `json:"created_by"`
+	CreatedAt         string     `json:"created_at"`
+}
+
+// CommitImportRequest selects the rows to post. Without row IDs every
+// pending row is posted; duplicates are only posted when selected.
+type CommitImportRequest struct {
+	RowIDs []uuid.UUID `json:"row_ids"`
+}
+
+type ImportRowResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	RowNumber     int        `json:"row_number"`
+	Date          *time.Time `json:"date,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	Amount        int64      `json:"amount"`
+	Description   string     `json:"description"`
+	Category      string     `json:"category,omitempty"`
+	Reference     string     `json:"reference,omitempty"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	DuplicateOf   *uuid.UUID `json:"duplicate_of,omitempty"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+}
+
+type ImportBatchResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	GroupID       *uuid.UUID          `json:"group_id,omitempty"`
+	ProfileID     *uuid.UUID          `json:"profile_id,omitempty"`
+	Format        string              `json:"format"`
+	FileName      string              `json:"file_name"`
+	Currency      string              `json:"currency"`
+	Status        string              `json:"status"`
+	TotalRows     int                 `json:"total_rows"`
+	PendingRows   int                 `json:"pending_rows"`
+	DuplicateRows int                 `json:"duplicate_rows"`
+	InvalidRows   int                 `json:"invalid_rows"`
+	ImportedRows  int                 `json:"imported_rows"`
+	CreatedBy     uuid.UUID           `json:"created_by"`
+	CreatedAt     string              `json:"created_at"`
+	Rows          []ImportRowResponse `json:"rows"`
+}
+
+// PreviewImportRequest holds the form fields sent with a statement upload.
+// Format defaults from the file extension; a profile is required for CSV
+// and optional for OFX and QIF, where it supplies the date order, locale
+// and default category.
+type PreviewImportRequest struct {
+	ProfileID string `form:"profile_id" binding:"omitempty,uuid"`
+	Format    string `form:"format" binding:"omitempty,oneof=csv ofx qif"`
+}
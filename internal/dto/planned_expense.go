@@ -17,6 +17,36 @@ type CreatePlannedExpenseRequest struct {
 	GroupID       *uuid.UUID `json:"group_id"`
 	
 	DueDate       *time.Time `json:"due_date"`
+
+	TagIDs []uuid.UUID `json:"tag_ids"`
+
+	// Makes the expense the first occurrence of a series; requires DueDate
+	Recurrence *RecurrenceRequest `json:"recurrence"`
+}
+
+// RecurrenceRequest schedules a planned expense every Interval weeks, months
+// or years, optionally until EndDate.
+type RecurrenceRequest struct {
+	Frequency string     `json:"frequency" binding:"required,oneof=weekly monthly yearly"`
+	Interval  int        `json:"interval" binding:"omitempty,min=1,max=365"` // defaults to 1
+	EndDate   *time.Time `json:"end_date"`
+}
+
+type RecurrenceResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Frequency   string     `json:"frequency"`
+	Interval    int        `json:"interval"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date,omitempty"`
+	IsActive    bool       `json:"is_active"`
+	Occurrences int        `json:"occurrences"`
+	NextDueDate *time.Time `json:"next_due_date,omitempty"`
+}
+
+// PlannedExpenseFilter holds the optional query parameters of expense listings.
+type PlannedExpenseFilter struct {
+	Status string `form:"status"`
+	TagID  string `form:"tag_id"`
 }
 
 type PlannedExpenseResponse struct {
@@ -25,6 +55,8 @@ type PlannedExpenseResponse struct {
 	Description   string     `json:"description"`
 	EstimatedPrice int64     `json:"estimated_price"`
 	ActualPrice   *int64     `json:"actual_price"`
+	PaidAmount    int64      `json:"paid_amount"`
+	RemainingAmount int64    `json:"remaining_amount"`
 	Category      string     `json:"category"`
 	Status        string     `json:"status"`
 	Priority      string     `json:"priority"`
@@ -36,6 +68,7 @@ type PlannedExpenseResponse struct {
 	PaidAt        *time.Time `json:"paid_at,omitempty"`
 	
 	DueDate       *time.Time `json:"due_date,omitempty"`
+	Recurrence    *RecurrenceResponse `json:"recurrence,omitempty"`
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	
@@ -43,6 +76,18 @@ type PlannedExpenseResponse struct {
 	Group         *GroupResponse     `json:"group,omitempty"`
 	Payer         *UserResponse      `json:"payer,omitempty"`
 	Transaction   *TransactionResponse `json:"transaction,omitempty"`
+	Tags          []TagResponse        `json:"tags,omitempty"`
+	Payments      []ExpensePaymentResponse `json:"payments"`
+}
+
+type ExpensePaymentResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	Amount        int64      `json:"amount"`
+	PaidBy        uuid.UUID  `json:"paid_by"`
+	PayerName     string     `json:"payer_name"`
+	PaidAt        time.Time  `json:"paid_at"`
+	TransactionID *uuid.UUID `json:"transaction_id,omitempty"`
+	Description   string     `json:"description,omitempty"`
 }
 
 type UpdatePlannedExpenseRequest struct {
@@ -52,8 +97,21 @@ type UpdatePlannedExpenseRequest struct {
 	Category      *string    `json:"category"`
 	Priority      *string    `json:"priority"`
 	DueDate       *time.Time `json:"due_date"`
+	TagIDs        *[]uuid.UUID `json:"tag_ids"`
+
+	// For occurrences of a recurring expense: "this" (default) changes only
+	// this occurrence; "series" also changes the template of the occurrences
+	// to come and the other planned ones. A new due date or recurrence in
+	// series scope restarts the schedule from this occurrence.
+	Scope      string             `json:"scope" binding:"omitempty,oneof=this series"`
+	Recurrence *RecurrenceRequest `json:"recurrence"`
 }
 
+// MarkAsBoughtRequest records a payment towards a personal expense.
+// ActualPrice is the amount paid now and settles the expense, unless Partial
+// says it is an installment: the expense is then bought once the payments
+// reach its estimated price.
 type MarkAsBoughtRequest struct {
 	ActualPrice int64 `json:"actual_price" binding:"required,gt=0"`
+	Partial     bool  `json:"partial"`
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateProposalRequest puts a planned group expense to a vote. Quorum is the
+// percentage of active members who must vote and defaults to 50.
+type CreateProposalRequest struct {
+	Description string    `json:"description" binding:"max=500"`
+	Deadline    time.Time `json:"deadline" binding:"required"`
+	Quorum      int       `json:"quorum" binding:"omitempty,min=1,max=100"`
+}
+
+type CastVoteRequest struct {
+	Vote string `json:"vote" binding:"required,oneof=yes no abstain"`
+}
+
+type ProposalFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=open passed failed cancelled"`
+}
+
+// ProposalTally counts the votes of the group's current active members.
+// QuorumVotes is how many of them must vote for the result to stand.
+type ProposalTally struct {
+	Yes         int  `json:"yes"`
+	No          int  `json:"no"`
+	Abstain     int  `json:"abstain"`
+	Cast        int  `json:"cast"`
+	Eligible    int  `json:"eligible"`
+	QuorumVotes int  `json:"quorum_votes"`
+	QuorumMet   bool `json:"quorum_met"`
+	Passing     bool `json:"passing"`
+}
+
+type ProposalVoteResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Vote      string    `json:"vote"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProposalResponse reports Expired for an open proposal past its deadline
+// that has not been closed yet; no more votes are accepted on it.
+type ProposalResponse struct {
+	ID             uuid.UUID              `json:"id"`
+	ExpenseID      uuid.UUID              `json:"expense_id"`
+	Item           string                 `json:"item"`
+	EstimatedPrice int64                  `json:"estimated_price"`
+	GroupID        uuid.UUID              `json:"group_id"`
+	ProposedBy     uuid.UUID              `json:"proposed_by"`
+	ProposerName   string                 `json:"proposer_name"`
+	Description    string                 `json:"description,omitempty"`
+	Quorum         int                    `json:"quorum"`
+	Deadline       time.Time              `json:"deadline"`
+	Status         string                 `json:"status"`
+	Expired        bool                   `json:"expired"`
+	ClosedAt       *time.Time             `json:"closed_at,omitempty"`
+	Tally          ProposalTally          `json:"tally"`
+	Votes          []ProposalVoteResponse `json:"votes"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
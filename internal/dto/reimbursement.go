@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PayPersonallyRequest records a member paying for a group expense with their
+// own money. ActualPrice is in the group currency. The payment is debited from
+// the member's personal balance, converted at ExchangeRate when the currencies
+// differ, unless External says it was paid outside the app. Partial leaves the
+// expense open like for PayGroupExpenseRequest.
+type PayPersonallyRequest struct {
+	PlannedExpenseID uuid.UUID `json:"planned_expense_id" binding:"required"`
+	ActualPrice      int64     `json:"actual_price" binding:"required,gt=0"`
+	Description      string    `json:"description"`
+	Partial          bool      `json:"partial"`
+	External         bool      `json:"external"`
+	ExchangeRate     string    `json:"exchange_rate"`
+}
+
+// SettleReimbursementRequest needs an exchange rate, in units of the member's
+// currency per unit of the group currency, when the two differ.
+type SettleReimbursementRequest struct {
+	ExchangeRate string `json:"exchange_rate"`
+}
+
+type ReimbursementFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=outstanding settled split"`
+}
+
+type ReimbursementResponse struct {
+	ID                      uuid.UUID  `json:"id"`
+	GroupID                 uuid.UUID  `json:"group_id"`
+	UserID                  uuid.UUID  `json:"user_id"`
+	MemberName              string     `json:"member_name"`
+	ExpenseID               uuid.UUID  `json:"expense_id"`
+	Item                    string     `json:"item"`
+	PaymentID               uuid.UUID  `json:"payment_id"`
+	PaymentTransactionID    *uuid.UUID `json:"payment_transaction_id,omitempty"`
+	Amount                  int64      `json:"amount"`
+	FormattedAmount         string     `json:"formatted_amount"`
+	Currency                string     `json:"currency"`
+	FundingSource           string     `json:"funding_source"`
+	Status                  string     `json:"status"`
+	SettledBy               *uuid.UUID `json:"settled_by,omitempty"`
+	SettledAt               *time.Time `json:"settled_at,omitempty"`
+	SettlementTransactionID *uuid.UUID `json:"settlement_transaction_id,omitempty"`
+	CreatedAt               time.Time  `json:"created_at"`
+}
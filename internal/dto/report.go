@@ -19,6 +19,7 @@ type MonthlyReportRequest struct {
 type MonthlyReportResponse struct {
 	Month           string                `json:"month"`
 	Year            int                   `json:"year"`
+	Currency        string                `json:"currency"`
 	TotalIncome     int64                 `json:"total_income"`
 	TotalExpenses   int64                 `json:"total_expenses"`
 	NetBalance      int64                 `json:"net_balance"`
@@ -27,6 +28,9 @@ type MonthlyReportResponse struct {
 	Transactions    []TransactionResponse `json:"transactions"`
 	Categories      []CategorySummary     `json:"categories"`
 	Sources         []SourceSummary       `json:"sources"`
+	Tags            []TagSummary          `json:"tags"`
+
+	ForeignCurrencies []ForeignCurrencySummary `json:"foreign_currencies,omitempty"`
 }
 
 type CategorySummary struct {
@@ -43,9 +47,28 @@ type SourceSummary struct {
 	Percentage float64 `json:"percentage"`
 }
 
+// TagSummary reports spending per tag. A transaction can carry several tags,
+// so percentages across tags may add up to more than 100.
+type TagSummary struct {
+	Tag        string  `json:"tag"`
+	Amount     int64   `json:"amount"`
+	Percentage float64 `json:"percentage"`
+}
+
+// ForeignCurrencySummary totals the transactions of one type entered in a
+// foreign currency. Amount is the converted total in the report currency.
+type ForeignCurrencySummary struct {
+	Currency       string `json:"currency"`
+	Type           string `json:"type"`
+	OriginalAmount int64  `json:"original_amount"`
+	Amount         int64  `json:"amount"`
+	Count          int    `json:"count"`
+}
+
 type GroupReportResponse struct {
 	GroupID         uuid.UUID              `json:"group_id"`
 	GroupName       string                 `json:"group_name"`
+	Currency        string                 `json:"currency"`
 	Period          string                 `json:"period"`
 	TotalIncome     int64                  `json:"total_income"`
 	TotalExpenses   int64                  `json:"total_expenses"`
@@ -55,6 +78,10 @@ type GroupReportResponse struct {
 	Members         []MemberContribution   `json:"members"`
 	ExternalSources []ExternalContribution `json:"external_sources"`
 	Expenses        []GroupExpenseSummary  `json:"expenses"`
+	Tags            []TagSummary           `json:"tags"`
+	Settlements     []SettlementSummary    `json:"settlements"`
+
+	ForeignCurrencies []ForeignCurrencySummary `json:"foreign_currencies,omitempty"`
 }
 
 type MemberContribution struct {
@@ -78,3 +105,27 @@ type GroupExpenseSummary struct {
 	PaidBy    uuid.UUID `json:"paid_by"`
 	PayerName string    `json:"payer_name"`
 }
+
+// SettlementSummary totals the confirmed settle-up payments from one member
+// to another in the period.
+type SettlementSummary struct {
+	FromUserID uuid.UUID `json:"from_user_id"`
+	FromName   string    `json:"from_name"`
+	ToUserID   uuid.UUID `json:"to_user_id"`
+	ToName     string    `json:"to_name"`
+	Amount     int64     `json:"amount"`
+	Count      int       `json:"count"`
+}
+
+// MonthlyStatement is a personal monthly report as printed on a statement.
+type MonthlyStatement struct {
+	AccountName string
+	Report      MonthlyReportResponse
+}
+
+// GroupStatement is a group monthly report as printed on a statement, with
+// the transactions of the period.
+type GroupStatement struct {
+	Report       GroupReportResponse
+	Transactions []TransactionResponse
+}
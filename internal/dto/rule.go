@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RuleRequest creates a categorization rule or replaces all its fields. At
+// least one condition is required.
+type RuleRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Priority *int   `json:"priority" binding:"omitempty,min=0"` // lower runs first, defaults to 100
+	IsActive *bool  `json:"is_active"`                          // defaults to true
+
+	DescriptionContains string `json:"description_contains" binding:"max=200"`
+	Type                string `json:"type" binding:"omitempty,oneof=CREDIT DEBIT"`
+	MinAmount           *int64 `json:"min_amount" binding:"omitempty,min=0"`
+	MaxAmount           *int64 `json:"max_amount" binding:"omitempty,min=0"`
+
+	Category string     `json:"category" binding:"required"`
+	TagID    *uuid.UUID `json:"tag_id"`
+}
+
+type RuleResponse struct {
+	ID                  uuid.UUID    `json:"id"`
+	Name                string       `json:"name"`
+	UserID              *uuid.UUID   `json:"user_id,omitempty"`
+	GroupID             *uuid.UUID   `json:"group_id,omitempty"`
+	Priority            int          `json:"priority"`
+	IsActive            bool         `json:"is_active"`
+	DescriptionContains string       `json:"description_contains,omitempty"`
+	Type                string       `json:"type,omitempty"`
+	MinAmount           *int64       `json:"min_amount,omitempty"`
+	MaxAmount           *int64       `json:"max_amount,omitempty"`
+	Category            string       `json:"category"`
+	Tag                 *TagResponse `json:"tag,omitempty"`
+	CreatedBy           uuid.UUID    `json:"created_by"`
+	CreatedAt           string       `json:"created_at"`
+}
+
+// RuleRunRequest limits a dry run or re-apply to transactions created in a
+// window. Without dates the whole history is checked.
+type RuleRunRequest struct {
+	StartDate *time.Time `json:"start_date"`
+	EndDate   *time.Time `json:"end_date"`
+}
+
+// RuleRunResponse lists the transactions whose category or tags a rule run
+// changes, or would change when Applied is false.
+type RuleRunResponse struct {
+	Checked int                  `json:"checked"`
+	Changed int                  `json:"changed"`
+	Applied bool                 `json:"applied"`
+	Changes []RuleChangeResponse `json:"changes"`
+}
+
+type RuleChangeResponse struct {
+	TransactionID   uuid.UUID `json:"transaction_id"`
+	RuleID          uuid.UUID `json:"rule_id"`
+	Description     string    `json:"description"`
+	Type            string    `json:"type"`
+	Amount          int64     `json:"amount"`
+	FormattedAmount string    `json:"formatted_amount"`
+	CreatedAt       string    `json:"created_at"`
+	OldCategory     string    `json:"old_category"`
+	NewCategory     string    `json:"new_category"`
+	AddedTag        string    `json:"added_tag,omitempty"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecordSettlementRequest records that the caller paid ToUserID back. With
+// MoveBalances the amount also moves between the two personal balances once
+// the receiver confirms; ExchangeRate, in units of the payer's currency per
+// unit of the group currency, is needed when the two currencies differ.
+type RecordSettlementRequest struct {
+	ToUserID     uuid.UUID `json:"to_user_id" binding:"required"`
+	Amount       int64     `json:"amount" binding:"required,gt=0"`
+	Method       string    `json:"method" binding:"required,oneof=cash mobile_money bank_transfer other"`
+	Reference    string    `json:"reference" binding:"max=100"`
+	Note         string    `json:"note" binding:"max=500"`
+	MoveBalances bool      `json:"move_balances"`
+	ExchangeRate string    `json:"exchange_rate"`
+}
+
+// ConfirmSettlementRequest needs an exchange rate, in units of the receiver's
+// currency per unit of the group currency, when the settlement moves balances
+// and the two currencies differ.
+type ConfirmSettlementRequest struct {
+	ExchangeRate string `json:"exchange_rate"`
+}
+
+type RejectSettlementRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type SettlementFilter struct {
+	Status   string `form:"status" binding:"omitempty,oneof=pending confirmed rejected cancelled"`
+	MemberID string `form:"member_id" binding:"omitempty,uuid"`
+}
+
+type SettlementResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	GroupID           uuid.UUID  `json:"group_id"`
+	FromUserID        uuid.UUID  `json:"from_user_id"`
+	FromName          string     `json:"from_name"`
+	ToUserID          uuid.UUID  `json:"to_user_id"`
+	ToName            string     `json:"to_name"`
+	Amount            int64      `json:"amount"`
+	FormattedAmount   string     `json:"formatted_amount"`
+	Currency          string     `json:"currency"`
+	Method            string     `json:"method"`
+	Reference         string     `json:"reference"`
+	Note              string     `json:"note"`
+	MoveBalances      bool       `json:"move_balances"`
+	Status            string     `json:"status"`
+	RejectionReason   string     `json:"rejection_reason,omitempty"`
+	ConfirmedAt       *time.Time `json:"confirmed_at,omitempty"`
+	FromTransactionID *uuid.UUID `json:"from_transaction_id,omitempty"`
+	ToTransactionID   *uuid.UUID `json:"to_transaction_id,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
@@ -0,0 +1,89 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShoppingListItemRequest adds an item to a list. Items added from a planned
+// expense take its name, category and remaining price unless given, and mark
+// the expense bought when the list is checked out.
+type ShoppingListItemRequest struct {
+	Name             string     `json:"name" binding:"required_without=PlannedExpenseID,max=100"`
+	Quantity         int        `json:"quantity" binding:"omitempty,gt=0"`
+	UnitPrice        int64      `json:"unit_price" binding:"omitempty,gte=0"`
+	Category         string     `json:"category"`
+	PlannedExpenseID *uuid.UUID `json:"planned_expense_id"`
+}
+
+type CreateShoppingListRequest struct {
+	Name  string                    `json:"name" binding:"required,max=100"`
+	Items []ShoppingListItemRequest `json:"items" binding:"omitempty,dive"`
+}
+
+type UpdateShoppingListRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+type UpdateShoppingListItemRequest struct {
+	Name      *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Quantity  *int    `json:"quantity" binding:"omitempty,gt=0"`
+	UnitPrice *int64  `json:"unit_price" binding:"omitempty,gte=0"`
+	Category  *string `json:"category"`
+}
+
+// CheckItemRequest checks an item off, or back on with Checked false. The
+// price on the shelf and the quantity actually taken can be noted as well.
+type CheckItemRequest struct {
+	Checked         bool   `json:"checked"`
+	ActualUnitPrice *int64 `json:"actual_unit_price" binding:"omitempty,gte=0"`
+	Quantity        *int   `json:"quantity" binding:"omitempty,gt=0"`
+}
+
+type CheckoutShoppingListRequest struct {
+	Description string `json:"description" binding:"max=255"`
+}
+
+type ShoppingListFilter struct {
+	Status string `form:"status" binding:"omitempty,oneof=open checked_out"`
+}
+
+type ShoppingListItemResponse struct {
+	ID               uuid.UUID  `json:"id"`
+	Name             string     `json:"name"`
+	Quantity         int        `json:"quantity"`
+	UnitPrice        int64      `json:"unit_price"`
+	ActualUnitPrice  *int64     `json:"actual_unit_price,omitempty"`
+	Total            int64      `json:"total"`
+	FormattedTotal   string     `json:"formatted_total"`
+	Category         string     `json:"category"`
+	Checked          bool       `json:"checked"`
+	CheckedBy        *uuid.UUID `json:"checked_by,omitempty"`
+	CheckedAt        *time.Time `json:"checked_at,omitempty"`
+	Status           string     `json:"status"`
+	PlannedExpenseID *uuid.UUID `json:"planned_expense_id,omitempty"`
+}
+
+// ShoppingListResponse totals the list at the expected prices and the checked
+// items at the prices noted while shopping.
+type ShoppingListResponse struct {
+	ID                    uuid.UUID                  `json:"id"`
+	Name                  string                     `json:"name"`
+	UserID                uuid.UUID                  `json:"user_id"`
+	CreatorName           string                     `json:"creator_name"`
+	GroupID               *uuid.UUID                 `json:"group_id,omitempty"`
+	Currency              string                     `json:"currency"`
+	Status                string                     `json:"status"`
+	Items                 []ShoppingListItemResponse `json:"items"`
+	ItemCount             int                        `json:"item_count"`
+	CheckedCount          int                        `json:"checked_count"`
+	EstimatedTotal        int64                      `json:"estimated_total"`
+	CheckedTotal          int64                      `json:"checked_total"`
+	FormattedCheckedTotal string                     `json:"formatted_checked_total"`
+	TransactionID         *uuid.UUID                 `json:"transaction_id,omitempty"`
+	CheckedOutBy          *uuid.UUID                 `json:"checked_out_by,omitempty"`
+	CheckedOutAt          *time.Time                 `json:"checked_out_at,omitempty"`
+	CreatedAt             time.Time                  `json:"created_at"`
+	UpdatedAt             time.Time                  `json:"updated_at"`
+}
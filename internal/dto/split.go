@@ -0,0 +1,69 @@
+package dto
+
+import "github.com/google/uuid"
+
+// SplitShareRequest is one member's part of a split. Value is an amount in
+// cents for exact splits, a percentage for percentage splits and a number of
+// shares for share splits; equal splits ignore it.
+type SplitShareRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Value  float64   `json:"value" binding:"omitempty,gt=0"`
+}
+
+// SetSplitRequest divides a group expense between members. An equal split
+// without shares covers every active member.
+type SetSplitRequest struct {
+	Method string              `json:"method" binding:"required,oneof=equal exact percentage shares"`
+	Shares []SplitShareRequest `json:"shares" binding:"omitempty,dive"`
+}
+
+type SplitShareResponse struct {
+	UserID    uuid.UUID `json:"user_id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Weight    int64     `json:"weight"`
+	Amount    int64     `json:"amount"`
+}
+
+// SplitResponse shows how Total, the expense's actual or else estimated
+// price, divides between the members.
+type SplitResponse struct {
+	ExpenseID uuid.UUID            `json:"expense_id"`
+	GroupID   uuid.UUID            `json:"group_id"`
+	Method    string               `json:"method"`
+	Total     int64                `json:"total"`
+	Currency  string               `json:"currency"`
+	Shares    []SplitShareResponse `json:"shares"`
+}
+
+// MemberBalance is a member's net position in the group: what they paid for
+// split expenses less their shares, plus what they settled with other members
+// less what they received. A positive net is owed to the member.
+type MemberBalance struct {
+	UserID       uuid.UUID `json:"user_id"`
+	FirstName    string    `json:"first_name"`
+	LastName     string    `json:"last_name"`
+	Paid         int64     `json:"paid"`
+	Owed         int64     `json:"owed"`
+	Sent         int64     `json:"sent"`
+	Received     int64     `json:"received"`
+	Net          int64     `json:"net"`
+	FormattedNet string    `json:"formatted_net"`
+}
+
+// SettleTransfer is a payment that would settle part of the group's debts.
+type SettleTransfer struct {
+	FromUserID      uuid.UUID `json:"from_user_id"`
+	FromName        string    `json:"from_name"`
+	ToUserID        uuid.UUID `json:"to_user_id"`
+	ToName          string    `json:"to_name"`
+	Amount          int64     `json:"amount"`
+	FormattedAmount string    `json:"formatted_amount"`
+}
+
+type GroupBalancesResponse struct {
+	GroupID   uuid.UUID        `json:"group_id"`
+	Currency  string           `json:"currency"`
+	Members   []MemberBalance  `json:"members"`
+	Transfers []SettleTransfer `json:"transfers"`
+}
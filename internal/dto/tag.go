@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateTagRequest struct {
+	Name  string `json:"name" binding:"required,max=50"`
+	Color string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type UpdateTagRequest struct {
+	Name  *string `json:"name" binding:"omitempty,min=1,max=50"`
+	Color *string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+type SetTagsRequest struct {
+	TagIDs []uuid.UUID `json:"tag_ids"`
+}
+
+type TagResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Color     string     `json:"color"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	GroupID   *uuid.UUID `json:"group_id,omitempty"`
+	CreatedBy uuid.UUID  `json:"created_by"`
+	CreatedAt string     `json:"created_at"`
+}
@@ -1,20 +1,64 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type CreateTransactionRequest struct {
 	Type        string `json:"type" binding:"required,oneof=CREDIT DEBIT"`
-	Amount      int64  `json:"amount" binding:"required,gt=0"`
+	Amount      int64  `json:"amount" binding:"required_without=OriginalAmount,omitempty,gt=0"`
+	Currency    string `json:"currency" binding:"omitempty,iso4217"` // defaults to the owner's currency
 	Category    string `json:"category" binding:"required"`
 	Source      string `json:"source" binding:"required"`
 	Description string `json:"description"`
 
+	// Amount as spent in a foreign currency. When Amount is omitted it is
+	// converted from these at the rate effective on RateDate (default today).
+	OriginalAmount   int64      `json:"original_amount" binding:"omitempty,gt=0"`
+	OriginalCurrency string     `json:"original_currency" binding:"required_with=OriginalAmount,omitempty,iso4217"`
+	RateDate         *time.Time `json:"rate_date"`
+
 	// For group transactions
 	GroupID *uuid.UUID `json:"group_id"`
 	PaidBy  *uuid.UUID `json:"paid_by"`
 
 	// For linking to planned expense
 	PlannedExpenseID *uuid.UUID `json:"planned_expense_id"`
+
+	// Optional split across categories; amounts must sum to Amount
+	LineItems []TransactionLineItemRequest `json:"line_items" binding:"omitempty,dive"`
+
+	TagIDs []uuid.UUID `json:"tag_ids"`
+
+	// Keep the given category even when a categorization rule matches
+	SkipRules bool `json:"skip_rules"`
+
+	// Extra metadata set by internal callers such as statement imports
+	Metadata map[string]interface{} `json:"-"`
+
+	// When the transaction took place, for internal callers posting past
+	// entries such as statement imports; defaults to now
+	Date *time.Time `json:"-"`
+}
+
+// TransactionFilter holds the optional query parameters of transaction listings.
+type TransactionFilter struct {
+	TagID string `form:"tag_id"`
+}
+
+type TransactionLineItemRequest struct {
+	Category string `json:"category" binding:"required"`
+	Amount   int64  `json:"amount" binding:"required,gt=0"`
+	Note     string `json:"note"`
+}
+
+type TransactionLineItemResponse struct {
+	ID       uuid.UUID `json:"id"`
+	Category string    `json:"category"`
+	Amount   int64     `json:"amount"`
+	Note     string    `json:"note"`
 }
 
 type TransactionResponse struct {
@@ -23,28 +67,53 @@ type TransactionResponse struct {
 	OwnerID     uuid.UUID `json:"owner_id"`
 	Type        string    `json:"type"`
 	Amount      int64     `json:"amount"`
+	Currency    string    `json:"currency"`
 	Balance     int64     `json:"balance"`
 	Category    string    `json:"category"`
 	Source      string    `json:"source"`
 	Description string    `json:"description"`
 	CreatedAt   string    `json:"created_at"`
 
+	// Amounts rendered for display, e.g. "$1,234.56"
+	FormattedAmount  string `json:"formatted_amount"`
+	FormattedBalance string `json:"formatted_balance"`
+
+	OriginalAmount          *int64 `json:"original_amount,omitempty"`
+	OriginalCurrency        string `json:"original_currency,omitempty"`
+	FormattedOriginalAmount string `json:"formatted_original_amount,omitempty"`
+	ExchangeRate            string `json:"exchange_rate,omitempty"`
+
 	GroupID          *uuid.UUID `json:"group_id,omitempty"`
 	PaidBy           *uuid.UUID `json:"paid_by,omitempty"`
 	PlannedExpenseID *uuid.UUID `json:"planned_expense_id,omitempty"`
 
+	LineItems []TransactionLineItemResponse `json:"line_items,omitempty"`
+	Tags      []TagResponse                 `json:"tags,omitempty"`
+
 	Group *GroupResponse `json:"group,omitempty"`
 	Payer *UserResponse  `json:"payer,omitempty"`
+
+	// Set when a new DEBIT is flagged as unusually large
+	Anomaly *AnomalyResponse `json:"anomaly,omitempty"`
 }
 
 type TransferToGroupRequest struct {
 	GroupID     uuid.UUID `json:"group_id" binding:"required"`
 	Amount      int64     `json:"amount" binding:"required,gt=0"`
 	Description string    `json:"description"`
+
+	// Required when the user's and the group's currencies differ: units of the
+	// group currency per unit of the user currency, as a decimal string
+	ExchangeRate string `json:"exchange_rate"`
 }
 
+// PayGroupExpenseRequest pays for a group expense, in full or in
+// installments: ActualPrice is the amount paid now and settles the expense,
+// unless Partial is set, in which case the expense is bought once the
+// payments reach its estimated price.
 type PayGroupExpenseRequest struct {
 	PlannedExpenseID uuid.UUID `json:"planned_expense_id" binding:"required"`
 	ActualPrice      int64     `json:"actual_price" binding:"required,gt=0"`
 	Description      string    `json:"description"`
+	Partial          bool      `json:"partial"`
 }
@@ -9,6 +9,7 @@ type UserResponse struct {
 	FirstName   string    `json:"first_name"`
 	LastName    string    `json:"last_name"`
 	Balance     int64     `json:"balance"`
+	Currency    string    `json:"currency"`
 	IsActive    bool      `json:"is_active"`
 	CreatedAt   string    `json:"created_at"`
 }
@@ -17,6 +18,7 @@ type UpdateUserRequest struct {
 	Email     string `json:"email" binding:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Currency  string `json:"currency" binding:"omitempty,iso4217"`
 }
 
 type ChangePasswordRequest struct {
@@ -0,0 +1,295 @@
+package exporters
+
+import (
+	"balanca/internal/dto"
+	"balanca/pkg/money"
+	"balanca/pkg/pdf"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	pageMargin = 40.0
+	rowHeight  = 15.0
+)
+
+// column is one column of a statement table. A zero width takes the space
+// left over by the other columns.
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+// layout places the parts of a statement top to bottom, starting new
+// pages as it runs out of room.
+type layout struct {
+	doc      *pdf.Document
+	title    string
+	currency string
+	y        float64
+}
+
+func newLayout(title, currency string) *layout {
+	s := &layout{doc: pdf.New(), title: title, currency: currency}
+	s.newPage()
+	return s
+}
+
+func (s *layout) newPage() {
+	s.doc.AddPage()
+	s.doc.SetFont(pdf.Helvetica, 8)
+	s.doc.Text(pageMargin, s.doc.Height-25, s.title)
+	s.doc.TextRight(s.doc.Width-pageMargin, s.doc.Height-25, fmt.Sprintf("Page %d", s.doc.PageCount()))
+	s.y = pageMargin
+}
+
+// ensure starts a new page unless height points still fit on this one.
+func (s *layout) ensure(height float64) bool {
+	if s.y+height <= s.doc.Height-pageMargin-10 {
+		return false
+	}
+	s.newPage()
+	return true
+}
+
+func (s *layout) header(title string, lines ...string) {
+	s.doc.SetFont(pdf.HelveticaBold, 18)
+	s.y += 18
+	s.doc.Text(pageMargin, s.y, title)
+	s.y += 8
+
+	s.doc.SetFont(pdf.Helvetica, 10)
+	for _, line := range lines {
+		s.y += 14
+		s.doc.Text(pageMargin, s.y, line)
+	}
+	s.y += 10
+	s.doc.Line(pageMargin, s.y, s.doc.Width-pageMargin, s.y, 0.5)
+	s.y += 10
+}
+
+// summary prints label/amount pairs in a box, e.g. opening and closing
+// balances.
+func (s *layout) summary(labels []string, amounts []int64) {
+	s.ensure(float64(len(labels))*rowHeight + 10)
+	top := s.y
+	s.doc.FillRect(pageMargin, top, 260, float64(len(labels))*rowHeight+8, 0.94)
+
+	s.y += 4
+	for i, label := range labels {
+		s.y += rowHeight - 3
+		s.doc.SetFont(pdf.Helvetica, 10)
+		s.doc.Text(pageMargin+8, s.y, label)
+		s.doc.SetFont(pdf.HelveticaBold, 10)
+		s.doc.TextRight(pageMargin+252, s.y, s.amount(amounts[i]))
+		s.y += 3
+	}
+	s.y = top + float64(len(labels))*rowHeight + 20
+}
+
+func (s *layout) section(title string) {
+	s.ensure(3 * rowHeight)
+	s.y += 8
+	s.doc.SetFont(pdf.HelveticaBold, 12)
+	s.doc.Text(pageMargin, s.y, title)
+	s.y += 8
+}
+
+// table prints rows under a shaded header, repeating the header on each new
+// page.
+func (s *layout) table(columns []column, rows [][]string) {
+	widths := make([]float64, len(columns))
+	flexible, used := -1, 0.0
+	for i, col := range columns {
+		if col.width == 0 {
+			flexible = i
+		}
+		widths[i] = col.width
+		used += col.width
+	}
+	if flexible >= 0 {
+		widths[flexible] = s.doc.Width - 2*pageMargin - used
+	}
+
+	drawHeader := func() {
+		s.doc.FillRect(pageMargin, s.y, s.doc.Width-2*pageMargin, rowHeight, 0.88)
+		s.doc.SetFont(pdf.HelveticaBold, 9)
+		s.drawRow(columns, widths, titles(columns))
+		s.y += rowHeight
+	}
+
+	s.ensure(2 * rowHeight)
+	drawHeader()
+
+	if len(rows) == 0 {
+		s.doc.SetFont(pdf.Helvetica, 9)
+		s.doc.Text(pageMargin+4, s.y+rowHeight-4, "Nothing to show for this period.")
+		s.y += rowHeight
+	}
+
+	s.doc.SetFont(pdf.Helvetica, 9)
+	for _, row := range rows {
+		if s.ensure(rowHeight) {
+			drawHeader()
+			s.doc.SetFont(pdf.Helvetica, 9)
+		}
+		s.drawRow(columns, widths, row)
+		s.y += rowHeight
+		s.doc.Line(pageMargin, s.y, s.doc.Width-pageMargin, s.y, 0.2)
+	}
+	s.y += 6
+}
+
+func (s *layout) drawRow(columns []column, widths []float64, values []string) {
+	x := pageMargin
+	baseline := s.y + rowHeight - 4
+	for i, col := range columns {
+		text := s.doc.Truncate(values[i], widths[i]-8)
+		if col.right {
+			s.doc.TextRight(x+widths[i]-4, baseline, text)
+		} else {
+			s.doc.Text(x+4, baseline, text)
+		}
+		x += widths[i]
+	}
+}
+
+func (s *layout) amount(amount int64) string {
+	return money.Money{Amount: amount, Currency: s.currency}.FormatNumber(money.DefaultLocale)
+}
+
+func (s *layout) write(w io.Writer) error {
+	return s.doc.Write(w)
+}
+
+func titles(columns []column) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = col.title
+	}
+	return values
+}
+
+var transactionColumns = []column{
+	{title: "Date", width: 62},
+	{title: "Description"},
+	{title: "Category", width: 95},
+	{title: "Amount", width: 80, right: true},
+	{title: "Balance", width: 80, right: true},
+}
+
+func (s *layout) transactionRows(transactions []dto.TransactionResponse) [][]string {
+	rows := make([][]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		date := transaction.CreatedAt
+		if t, err := time.Parse(time.RFC3339, transaction.CreatedAt); err == nil {
+			date = t.Format("2006-01-02")
+		}
+		amount := transaction.Amount
+		if transaction.Type == "DEBIT" {
+			amount = -amount
+		}
+		rows = append(rows, []string{
+			date,
+			transaction.Description,
+			transaction.Category,
+			s.amount(amount),
+			s.amount(transaction.Balance),
+		})
+	}
+	return rows
+}
+
+func percent(value float64) string {
+	return fmt.Sprintf("%.1f%%", value)
+}
+
+// WriteMonthlyStatementPDF renders a personal monthly report as a printable
+// statement.
+func WriteMonthlyStatementPDF(w io.Writer, statement *dto.MonthlyStatement) error {
+	report := &statement.Report
+	period := fmt.Sprintf("%s %d", report.Month, report.Year)
+
+	s := newLayout("Statement - "+period, report.Currency)
+	s.header("Monthly statement",
+		statement.AccountName,
+		"Period: "+period,
+		"Amounts in "+report.Currency,
+		"Generated "+time.Now().UTC().Format("2006-01-02 15:04 UTC"))
+
+	s.summary(
+		[]string{"Opening balance", "Money in", "Money out", "Net change", "Closing balance"},
+		[]int64{report.StartingBalance, report.TotalIncome, -report.TotalExpenses, report.NetBalance, report.EndingBalance})
+
+	s.section("Transactions")
+	s.table(transactionColumns, s.transactionRows(report.Transactions))
+
+	var rows [][]string
+	for _, category := range report.Categories {
+		rows = append(rows, []string{category.Category, percent(category.Percentage), s.amount(category.Amount)})
+	}
+	s.section("Spending by category")
+	s.table([]column{{title: "Category"}, {title: "Share", width: 70, right: true}, {title: "Amount", width: 100, right: true}}, rows)
+
+	rows = nil
+	for _, source := range report.Sources {
+		rows = append(rows, []string{source.Source, percent(source.Percentage), s.amount(source.Amount)})
+	}
+	s.section("Income by source")
+	s.table([]column{{title: "Source"}, {title: "Share", width: 70, right: true}, {title: "Amount", width: 100, right: true}}, rows)
+
+	return s.write(w)
+}
+
+// WriteGroupStatementPDF renders a group monthly report, with the period's
+// transactions, each member's contributions and the settlements between
+// members, as a printable statement.
+func WriteGroupStatementPDF(w io.Writer, statement *dto.GroupStatement) error {
+	report := &statement.Report
+
+	s := newLayout(report.GroupName+" - "+report.Period, report.Currency)
+	s.header("Group statement",
+		report.GroupName,
+		"Period: "+report.Period,
+		"Amounts in "+report.Currency,
+		"Generated "+time.Now().UTC().Format("2006-01-02 15:04 UTC"))
+
+	s.summary(
+		[]string{"Opening balance", "Money in", "Money out", "Net change", "Closing balance"},
+		[]int64{report.StartingBalance, report.TotalIncome, -report.TotalExpenses, report.NetBalance, report.EndingBalance})
+
+	s.section("Transactions")
+	s.table(transactionColumns, s.transactionRows(statement.Transactions))
+
+	var rows [][]string
+	for _, member := range report.Members {
+		name := strings.TrimSpace(member.FirstName + " " + member.LastName)
+		rows = append(rows, []string{name, percent(member.Percentage), s.amount(member.Amount)})
+	}
+	for _, source := range report.ExternalSources {
+		rows = append(rows, []string{source.Source + " (external)", percent(source.Percentage), s.amount(source.Amount)})
+	}
+	s.section("Contributions")
+	s.table([]column{{title: "Contributor"}, {title: "Share", width: 70, right: true}, {title: "Amount", width: 100, right: true}}, rows)
+
+	rows = nil
+	for _, expense := range report.Expenses {
+		rows = append(rows, []string{expense.Category, expense.PayerName, fmt.Sprint(expense.Count), s.amount(expense.Amount)})
+	}
+	s.section("Spending by category")
+	s.table([]column{{title: "Category"}, {title: "Paid by", width: 140}, {title: "Count", width: 50, right: true}, {title: "Amount", width: 100, right: true}}, rows)
+
+	if len(report.Settlements) > 0 {
+		rows = nil
+		for _, settlement := range report.Settlements {
+			rows = append(rows, []string{settlement.FromName, settlement.ToName, fmt.Sprint(settlement.Count), s.amount(settlement.Amount)})
+		}
+		s.section("Settlements between members")
+		s.table([]column{{title: "Paid by"}, {title: "Paid to", width: 140}, {title: "Count", width: 50, right: true}, {title: "Amount", width: 100, right: true}}, rows)
+	}
+
+	return s.write(w)
+}
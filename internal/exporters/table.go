@@ -0,0 +1,58 @@
+// Package exporters writes ledger rows as spreadsheet files. Writers emit rows
+// as they are given so exports never hold the whole ledger in memory.
+package exporters
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Number is a decimal value such as "-12.50". Spreadsheet formats store it
+// as a number rather than text.
+type Number string
+
+// TableWriter writes rows of strings and Numbers. Close must be called to
+// complete the file.
+type TableWriter interface {
+	WriteRow(values []interface{}) error
+	Close() error
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func NewCSVWriter(w io.Writer) TableWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case Number:
+			record[i] = string(v)
+		case string:
+			record[i] = escapeFormula(v)
+		default:
+			record[i] = escapeFormula(fmt.Sprint(v))
+		}
+	}
+	return w.writer.Write(record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// escapeFormula stops spreadsheet applications from running text that looks
+// like a formula, such as a description starting with "=".
+func escapeFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package exporters
+
+import (
+	"balanca/internal/dto"
+	"balanca/pkg/money"
+	"strings"
+)
+
+// TransactionHeader names the columns written by TransactionRow.
+var TransactionHeader = []interface{}{
+	"id", "date", "type", "amount", "currency", "balance", "category", "source",
+	"description", "tags", "original_amount", "original_currency", "exchange_rate",
+	"group_id", "paid_by", "planned_expense_id", "recorded_by",
+}
+
+// TransactionRow flattens a transaction into spreadsheet cells. Amounts are
+// decimals in the transaction currency and debits are negative.
+func TransactionRow(transaction *dto.TransactionResponse) []interface{} {
+	amount := transaction.Amount
+	if transaction.Type == "DEBIT" {
+		amount = -amount
+	}
+
+	var originalAmount, originalCurrency string
+	if transaction.OriginalAmount != nil {
+		original := money.Money{Amount: *transaction.OriginalAmount, Currency: transaction.OriginalCurrency}
+		if transaction.Type == "DEBIT" {
+			original.Amount = -original.Amount
+		}
+		originalAmount = original.Decimal()
+		originalCurrency = transaction.OriginalCurrency
+	}
+
+	var tags []string
+	for _, tag := range transaction.Tags {
+		tags = append(tags, tag.Name)
+	}
+
+	var groupID, paidBy, plannedExpenseID, recordedBy string
+	if transaction.GroupID != nil {
+		groupID = transaction.GroupID.String()
+	}
+	if transaction.PaidBy != nil {
+		paidBy = transaction.PaidBy.String()
+	}
+	if transaction.PlannedExpenseID != nil {
+		plannedExpenseID = transaction.PlannedExpenseID.String()
+	}
+	if transaction.Payer != nil {
+		recordedBy = strings.TrimSpace(transaction.Payer.FirstName + " " + transaction.Payer.LastName)
+	}
+
+	return []interface{}{
+		transaction.ID.String(),
+		transaction.CreatedAt,
+		transaction.Type,
+		Number(money.Money{Amount: amount, Currency: transaction.Currency}.Decimal()),
+		transaction.Currency,
+		Number(money.Money{Amount: transaction.Balance, Currency: transaction.Currency}.Decimal()),
+		transaction.Category,
+		transaction.Source,
+		transaction.Description,
+		strings.Join(tags, "; "),
+		Number(originalAmount),
+		originalCurrency,
+		Number(transaction.ExchangeRate),
+		groupID,
+		paidBy,
+		plannedExpenseID,
+		recordedBy,
+	}
+}
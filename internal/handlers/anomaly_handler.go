@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"balanca/internal/services"
+	"balanca/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnomalyHandler struct {
+	anomalyService services.AnomalyService
+}
+
+func NewAnomalyHandler(anomalyService services.AnomalyService) *AnomalyHandler {
+	return &AnomalyHandler{anomalyService: anomalyService}
+}
+
+func (h *AnomalyHandler) GetPersonalAnomalies(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	page, limit := anomalyPage(c)
+
+	anomalies, total, err := h.anomalyService.GetPersonalAnomalies(userUUID, page, limit)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+func (h *AnomalyHandler) GetGroupAnomalies(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	page, limit := anomalyPage(c)
+
+	anomalies, total, err := h.anomalyService.GetGroupAnomalies(userUUID, groupID, page, limit)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"total":     total,
+		"page":      page,
+		"limit":     limit,
+	})
+}
+
+func anomalyPage(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
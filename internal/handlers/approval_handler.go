@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/services"
+	"balanca/pkg/errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApprovalHandler struct {
+	approvalService services.ApprovalService
+}
+
+func NewApprovalHandler(approvalService services.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{approvalService: approvalService}
+}
+
+func (h *ApprovalHandler) SetPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var req dto.ApprovalPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	policy, err := h.approvalService.SetPolicy(userUUID, groupID, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *ApprovalHandler) GetPolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	policy, err := h.approvalService.GetPolicy(userUUID, groupID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *ApprovalHandler) DeletePolicy(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	if err := h.approvalService.DeletePolicy(userUUID, groupID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Approval policy deleted successfully"})
+}
+
+func (h *ApprovalHandler) ApproveExpense(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expenseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	// The body is optional; an approval needs no reason
+	var req dto.ApprovalDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	approval, err := h.approvalService.ApproveExpense(userUUID, expenseID, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, approval)
+}
+
+func (h *ApprovalHandler) RejectExpense(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expenseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	var req dto.ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	approval, err := h.approvalService.RejectExpense(userUUID, expenseID, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, approval)
+}
+
+func (h *ApprovalHandler) GetApprovals(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expenseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	approval, err := h.approvalService.GetApprovals(userUUID, expenseID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, approval)
+}
@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"balanca/internal/services"
+	"balanca/pkg/errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AttachmentHandler struct {
+	attachmentService services.AttachmentService
+	maxUploadSize     int64
+}
+
+func NewAttachmentHandler(attachmentService services.AttachmentService, maxUploadSize int64) *AttachmentHandler {
+	return &AttachmentHandler{attachmentService: attachmentService, maxUploadSize: maxUploadSize}
+}
+
+func (h *AttachmentHandler) UploadTransactionAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	// Cap the request body so oversized uploads fail before being buffered
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing file"})
+		return
+	}
+
+	if fileHeader.Size > h.maxUploadSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is too large", "code": "FILE_TOO_LARGE"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadTransactionAttachment(userUUID, transactionID, fileHeader.Filename, file)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) UploadExpenseAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expenseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	// Cap the request body so oversized uploads fail before being buffered
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.maxUploadSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing file"})
+		return
+	}
+
+	if fileHeader.Size > h.maxUploadSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is too large", "code": "FILE_TOO_LARGE"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadExpenseAttachment(userUUID, expenseID, fileHeader.Filename, file)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, attachment)
+}
+
+func (h *AttachmentHandler) GetTransactionAttachments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	attachments, err := h.attachmentService.GetTransactionAttachments(userUUID, transactionID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) GetExpenseAttachments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	expenseID, err := uuid.Parse(c.Param("expenseId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	attachments, err := h.attachmentService.GetExpenseAttachments(userUUID, expenseID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, attachments)
+}
+
+func (h *AttachmentHandler) GetDownloadURL(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	signedURL, err := h.attachmentService.GetDownloadURL(userUUID, attachmentID)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, signedURL)
+}
+
+// Download serves a file through a signed URL; it is registered as a public
+// route because the signature replaces the bearer token.
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	attachment, content, err := h.attachmentService.OpenSignedDownload(attachmentID, c.Query("user_id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("inline; filename=%q", attachment.FileName),
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=60",
+	})
+}
+
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	attachmentID, err := uuid.Parse(c.Param("attachmentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid attachment ID"})
+		return
+	}
+
+	if err := h.attachmentService.DeleteAttachment(userUUID, attachmentID); err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Attachment deleted successfully"})
+}
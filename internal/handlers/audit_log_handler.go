@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"balanca/internal/services"
+	"balanca/pkg/errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditLogHandler struct {
+	auditLogService services.AuditLogService
+}
+
+func NewAuditLogHandler(auditLogService services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{auditLogService: auditLogService}
+}
+
+func (h *AuditLogHandler) GetPersonalAuditLogs(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Keyset pagination is opt-in: passing a cursor (empty for the first page)
+	// returns next_cursor instead of page/total.
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		logs, nextCursor, err := h.auditLogService.GetPersonalAuditLogsAfter(userUUID, cursor, limit)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"audit_logs":  logs,
+			"next_cursor": nextCursor,
+			"limit":       limit,
+		})
+		return
+	}
+
+	logs, total, err := h.auditLogService.GetPersonalAuditLogs(userUUID, page, limit)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit_logs": logs,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+	})
+}
+
+func (h *AuditLogHandler) GetGroupAuditLogs(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	// Keyset pagination is opt-in: passing a cursor (empty for the first page)
+	// returns next_cursor instead of page/total.
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		logs, nextCursor, err := h.auditLogService.GetGroupAuditLogsAfter(userUUID, groupID, cursor, limit)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"audit_logs":  logs,
+			"next_cursor": nextCursor,
+			"limit":       limit,
+		})
+		return
+	}
+
+	logs, total, err := h.auditLogService.GetGroupAuditLogs(userUUID, groupID, page, limit)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"audit_logs": logs,
+		"total":      total,
+		"page":       page,
+		"limit":      limit,
+	})
+}
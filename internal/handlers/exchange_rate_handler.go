@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/services"
+	"balanca/pkg/errors"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRatesFileSize caps the size of an uploaded exchange rate file.
+const maxRatesFileSize = 5 << 20
+
+type ExchangeRateHandler struct {
+	exchangeRateService services.ExchangeRateService
+	importToken         string
+}
+
+func NewExchangeRateHandler(exchangeRateService services.ExchangeRateService, importToken string) *ExchangeRateHandler {
+	return &ExchangeRateHandler{exchangeRateService: exchangeRateService, importToken: importToken}
+}
+
+func (h *ExchangeRateHandler) ImportRates(c *gin.Context) {
+	// Rates are shared by every user, so imports need the operator token
+	token := c.GetHeader("X-Import-Token")
+	if h.importToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.importToken)) != 1 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Exchange rate import is not allowed"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxRatesFileSize+1<<20)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing file"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	source := c.DefaultPostForm("source", fileHeader.Filename)
+
+	result, err := h.exchangeRateService.ImportCSV(file, source)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *ExchangeRateHandler) GetRate(c *gin.Context) {
+	var filter dto.ExchangeRateFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rate, err := h.exchangeRateService.GetRate(filter)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, rate)
+}
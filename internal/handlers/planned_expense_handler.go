@@ -108,7 +108,12 @@ func (h *PlannedExpenseHandler) GetPersonalExpenses(c *gin.Context) {
 		return
 	}
 
-	status := c.Query("status")
+	var filter dto.PlannedExpenseFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -119,7 +124,7 @@ func (h *PlannedExpenseHandler) GetPersonalExpenses(c *gin.Context) {
 		limit = 20
 	}
 
-	expenses, total, err := h.expenseService.GetPersonalExpenses(userUUID, status, page, limit)
+	expenses, total, err := h.expenseService.GetPersonalExpenses(userUUID, filter, page, limit)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
@@ -156,7 +161,12 @@ func (h *PlannedExpenseHandler) GetGroupExpenses(c *gin.Context) {
 		return
 	}
 
-	status := c.Query("status")
+	var filter dto.PlannedExpenseFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -167,7 +177,7 @@ func (h *PlannedExpenseHandler) GetGroupExpenses(c *gin.Context) {
 		limit = 20
 	}
 
-	expenses, total, err := h.expenseService.GetGroupExpenses(userUUID, groupID, status, page, limit)
+	expenses, total, err := h.expenseService.GetGroupExpenses(userUUID, groupID, filter, page, limit)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"balanca/internal/dto"
+	"balanca/internal/exporters"
 	"balanca/internal/services"
 	"balanca/pkg/errors"
+	"bytes"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -291,3 +294,153 @@ func (h *ReportHandler) GetSourceBreakdown(c *gin.Context) {
 
 	c.JSON(http.StatusOK, breakdown)
 }
+
+func (h *ReportHandler) GetTagBreakdown(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req dto.DateRangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate date range
+	if req.StartDate.After(req.EndDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date must be before end date"})
+		return
+	}
+
+	breakdown, err := h.reportService.GetTagBreakdown(userUUID, req.StartDate, req.EndDate)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+func (h *ReportHandler) GetPersonalMonthlyStatementPDF(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	year, month, ok := reportMonth(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.reportService.GetPersonalMonthlyStatement(userUUID, year, month)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := exporters.WriteMonthlyStatementPDF(&buf, statement); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("statement-%04d-%02d.pdf", year, month), buf.Bytes())
+}
+
+func (h *ReportHandler) GetGroupMonthlyStatementPDF(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	year, month, ok := reportMonth(c)
+	if !ok {
+		return
+	}
+
+	statement, err := h.reportService.GetGroupMonthlyStatement(userUUID, groupID, year, month)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := exporters.WriteGroupStatementPDF(&buf, statement); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	sendPDF(c, fmt.Sprintf("group-statement-%04d-%02d.pdf", year, month), buf.Bytes())
+}
+
+// reportMonth reads the year and month query parameters, defaulting to the
+// current month. It writes the error response itself when they are invalid.
+func reportMonth(c *gin.Context) (int, int, bool) {
+	yearStr := c.Query("year")
+	monthStr := c.Query("month")
+
+	if yearStr == "" || monthStr == "" {
+		now := time.Now()
+		yearStr = strconv.Itoa(now.Year())
+		monthStr = strconv.Itoa(int(now.Month()))
+	}
+
+	year, err := strconv.Atoi(yearStr)
+	if err != nil || year < 2000 || year > 2100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+		return 0, 0, false
+	}
+
+	month, err := strconv.Atoi(monthStr)
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month"})
+		return 0, 0, false
+	}
+
+	return year, month, true
+}
+
+func sendPDF(c *gin.Context, fileName string, content []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", content)
+}
@@ -2,8 +2,11 @@ package handlers
 
 import (
 	"balanca/internal/dto"
+	"balanca/internal/exporters"
 	"balanca/internal/services"
 	"balanca/pkg/errors"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
 
@@ -178,6 +181,12 @@ func (h *TransactionHandler) GetPersonalTransactions(c *gin.Context) {
 		return
 	}
 
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -188,7 +197,28 @@ func (h *TransactionHandler) GetPersonalTransactions(c *gin.Context) {
 		limit = 20
 	}
 
-	transactions, total, err := h.transactionService.GetPersonalTransactions(userUUID, page, limit)
+	// Keyset pagination is opt-in: passing a cursor (empty for the first page)
+	// returns next_cursor instead of page/total.
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		transactions, nextCursor, err := h.transactionService.GetPersonalTransactionsAfter(userUUID, filter, cursor, limit)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"transactions": transactions,
+			"next_cursor":  nextCursor,
+			"limit":        limit,
+		})
+		return
+	}
+
+	transactions, total, err := h.transactionService.GetPersonalTransactions(userUUID, filter, page, limit)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
@@ -225,6 +255,12 @@ func (h *TransactionHandler) GetGroupTransactions(c *gin.Context) {
 		return
 	}
 
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 
@@ -235,7 +271,28 @@ func (h *TransactionHandler) GetGroupTransactions(c *gin.Context) {
 		limit = 20
 	}
 
-	transactions, total, err := h.transactionService.GetGroupTransactions(userUUID, groupID, page, limit)
+	// Keyset pagination is opt-in: passing a cursor (empty for the first page)
+	// returns next_cursor instead of page/total.
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		transactions, nextCursor, err := h.transactionService.GetGroupTransactionsAfter(userUUID, groupID, filter, cursor, limit)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"transactions": transactions,
+			"next_cursor":  nextCursor,
+			"limit":        limit,
+		})
+		return
+	}
+
+	transactions, total, err := h.transactionService.GetGroupTransactions(userUUID, groupID, filter, page, limit)
 	if err != nil {
 		if appErr, ok := err.(*errors.AppError); ok {
 			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
@@ -253,6 +310,141 @@ func (h *TransactionHandler) GetGroupTransactions(c *gin.Context) {
 	})
 }
 
+func (h *TransactionHandler) ExportPersonalTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	streamTransactions(c, "transactions", func(write func(*dto.TransactionResponse) error) error {
+		return h.transactionService.ExportPersonalTransactions(userUUID, filter, write)
+	})
+}
+
+func (h *TransactionHandler) ExportGroupTransactions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	groupID, err := uuid.Parse(c.Param("groupId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid group ID"})
+		return
+	}
+
+	var filter dto.TransactionFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	streamTransactions(c, "group-transactions", func(write func(*dto.TransactionResponse) error) error {
+		return h.transactionService.ExportGroupTransactions(userUUID, groupID, filter, write)
+	})
+}
+
+// streamTransactions writes the transactions handed over by export as CSV,
+// newline-delimited JSON or XLSX, per the format query parameter. The
+// response starts with the first row, so errors raised before it, such as a
+// failed membership check, still get a regular JSON error.
+func streamTransactions(c *gin.Context, fileName string, export func(write func(*dto.TransactionResponse) error) error) {
+	format := c.DefaultQuery("format", "csv")
+	var contentType string
+	switch format {
+	case "csv":
+		contentType = "text/csv; charset=utf-8"
+	case "ndjson":
+		contentType = "application/x-ndjson"
+	case "xlsx":
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported export format"})
+		return
+	}
+
+	var table exporters.TableWriter
+	var encoder *json.Encoder
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", contentType)
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName+"."+format))
+		c.Status(http.StatusOK)
+
+		var err error
+		switch format {
+		case "ndjson":
+			encoder = json.NewEncoder(c.Writer)
+			return nil
+		case "xlsx":
+			table, err = exporters.NewXLSXWriter(c.Writer, "Transactions")
+			if err != nil {
+				return err
+			}
+		default:
+			table = exporters.NewCSVWriter(c.Writer)
+		}
+		return table.WriteRow(exporters.TransactionHeader)
+	}
+
+	err := export(func(transaction *dto.TransactionResponse) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if encoder != nil {
+			return encoder.Encode(transaction)
+		}
+		return table.WriteRow(exporters.TransactionRow(transaction))
+	})
+	if err == nil && !started {
+		// Nothing matched; still send an empty file
+		err = start()
+	}
+	if err != nil {
+		if started {
+			// The status is already sent; cut the download short
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	if table != nil {
+		if err := table.Close(); err != nil {
+			c.Error(err)
+		}
+	}
+}
+
 func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 	userID, exists := c.Get("user_id")
 	if !exists {
@@ -284,3 +476,41 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 
 	c.JSON(http.StatusOK, transaction)
 }
+
+func (h *TransactionHandler) SetTransactionTags(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	transactionID, err := uuid.Parse(c.Param("transactionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid transaction ID"})
+		return
+	}
+
+	var req dto.SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transaction, err := h.transactionService.SetTransactionTags(userUUID, transactionID, req)
+	if err != nil {
+		if appErr, ok := err.(*errors.AppError); ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Message, "code": appErr.Code})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
@@ -0,0 +1,214 @@
+package importers
+
+import (
+	"balanca/pkg/money"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sign conventions of the amount column.
+const (
+	SignSigned   = "signed"   // positive amounts are money in
+	SignInverted = "inverted" // positive amounts are money out, as on card statements
+	SignSplit    = "split"    // separate debit and credit columns
+)
+
+// CSVMapping says where the fields of a transaction are in a CSV file.
+// Columns are header names, matched case-insensitively, or 1-based
+// positions.
+type CSVMapping struct {
+	Delimiter         rune
+	HasHeader         bool
+	DateColumn        string
+	DateFormat        string // e.g. YYYY-MM-DD or DD/MM/YYYY
+	AmountColumn      string
+	DebitColumn       string
+	CreditColumn      string
+	DescriptionColumn string
+	CategoryColumn    string
+	SignConvention    string
+	Locale            *money.Locale // nil guesses the separators
+	Currency          string
+}
+
+// ParseCSV reads a statement with the mapping. Lines that cannot be read are
+// returned with Err set so they can be shown to the user; an error is only
+// returned when the file itself is unusable.
+func ParseCSV(content io.Reader, mapping CSVMapping) ([]Entry, error) {
+	reader := csv.NewReader(content)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+	if mapping.Delimiter != 0 {
+		reader.Comma = mapping.Delimiter
+	}
+
+	layout, err := DateLayout(mapping.DateFormat)
+	if err != nil {
+		return nil, err
+	}
+
+	var header []string
+	line := 0
+	if mapping.HasHeader {
+		header, err = reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("missing header: %w", err)
+		}
+		line++
+		if len(header) > 0 {
+			header[0] = strings.TrimPrefix(header[0], "\ufeff")
+		}
+	}
+
+	columns := make(map[string]int)
+	for _, name := range []string{mapping.DateColumn, mapping.AmountColumn, mapping.DebitColumn,
+		mapping.CreditColumn, mapping.DescriptionColumn, mapping.CategoryColumn} {
+		if name == "" {
+			continue
+		}
+		index, err := columnIndex(header, name)
+		if err != nil {
+			return nil, err
+		}
+		columns[name] = index
+	}
+
+	var entries []Entry
+	for {
+		record, err := reader.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if len(entries) >= maxEntries {
+			return nil, ErrTooManyRows
+		}
+		if err != nil {
+			entries = append(entries, Entry{Line: line, Err: err})
+			continue
+		}
+		// Quoted fields can span lines
+		line, _ = reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+
+		field := func(name string) string {
+			if name == "" {
+				return ""
+			}
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := Entry{
+			Line:        line,
+			Description: field(mapping.DescriptionColumn),
+			Category:    field(mapping.CategoryColumn),
+		}
+
+		entry.Date, err = time.Parse(layout, field(mapping.DateColumn))
+		if err != nil {
+			entry.Err = fmt.Errorf("invalid date %q", field(mapping.DateColumn))
+			entries = append(entries, entry)
+			continue
+		}
+
+		entry.Amount, err = readAmount(mapping, field)
+		if err != nil {
+			entry.Err = err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func readAmount(mapping CSVMapping, field func(string) string) (int64, error) {
+	parse := func(value string) (int64, error) {
+		amount, err := money.Parse(value, mapping.Currency, mapping.Locale)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q", value)
+		}
+		return amount.Amount, nil
+	}
+
+	switch mapping.SignConvention {
+	case SignSplit:
+		debit, credit := field(mapping.DebitColumn), field(mapping.CreditColumn)
+		switch {
+		case debit != "" && credit != "":
+			return 0, errors.New("both debit and credit are set")
+		case debit != "":
+			amount, err := parse(debit)
+			if amount < 0 {
+				amount = -amount
+			}
+			return -amount, err
+		case credit != "":
+			amount, err := parse(credit)
+			if amount < 0 {
+				amount = -amount
+			}
+			return amount, err
+		}
+		return 0, errors.New("missing amount")
+	case SignInverted:
+		amount, err := parse(field(mapping.AmountColumn))
+		return -amount, err
+	default:
+		return parse(field(mapping.AmountColumn))
+	}
+}
+
+// columnIndex finds a column by header name or 1-based position.
+func columnIndex(header []string, name string) (int, error) {
+	for i, column := range header {
+		if strings.EqualFold(strings.TrimSpace(column), strings.TrimSpace(name)) {
+			return i, nil
+		}
+	}
+	if position, err := strconv.Atoi(name); err == nil && position > 0 {
+		return position - 1, nil
+	}
+	return 0, fmt.Errorf("column %q not found", name)
+}
+
+// DateLayout converts a date format such as DD/MM/YYYY into a Go layout.
+func DateLayout(format string) (string, error) {
+	if format == "" {
+		format = "YYYY-MM-DD"
+	}
+
+	layout := format
+	for _, token := range []struct{ from, to string }{
+		{"YYYY", "2006"},
+		{"YY", "06"},
+		{"MM", "01"},
+		{"DD", "02"},
+	} {
+		layout = strings.ReplaceAll(layout, token.from, token.to)
+	}
+
+	if !strings.Contains(layout, "01") || !strings.Contains(layout, "02") || !strings.Contains(layout, "06") {
+		return "", fmt.Errorf("unsupported date format %q", format)
+	}
+	return layout, nil
+}
+
+func isBlank(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
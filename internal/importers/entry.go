@@ -0,0 +1,46 @@
+// Package importers turns bank statement files into ledger entries.
+package importers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxEntries bounds the size of a single statement import.
+const maxEntries = 10000
+
+var ErrTooManyRows = errors.New("statement has too many rows")
+
+// Entry is one statement line. Amount is in minor units of the statement
+// currency and signed from the account holder's point of view: positive for
+// money coming in, negative for money going out. Err is set when the line
+// could not be read; the other fields are then best effort.
+type Entry struct {
+	Line        int
+	Date        time.Time
+	Amount      int64
+	Description string
+	Category    string
+	Reference   string // bank-provided transaction ID, when the format has one
+	Account     string // account the reference belongs to, when known
+	Err         error
+}
+
+// Fingerprint identifies the entry for duplicate detection. Entries with a
+// bank reference (such as an OFX FITID) are identified by it and their
+// account; others by date, amount and description.
+func (e Entry) Fingerprint() string {
+	var key string
+	if e.Reference != "" {
+		key = "ref|" + e.Account + "|" + e.Reference
+	} else {
+		description := strings.Join(strings.Fields(strings.ToLower(e.Description)), " ")
+		key = fmt.Sprintf("%s|%d|%s", e.Date.Format("2006-01-02"), e.Amount, description)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
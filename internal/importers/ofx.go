@@ -0,0 +1,194 @@
+package importers
+
+import (
+	"balanca/pkg/money"
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var ErrNotOFX = errors.New("file is not an OFX statement")
+
+// ofxAmountLocale reads TRNAMT values, which always use '.' for decimals
+// except from a few banks that use ','.
+var ofxAmountLocale = money.Locale{Decimal: '.'}
+
+type ofxTag struct {
+	name    string
+	closing bool
+	value   string
+	line    int
+}
+
+// ParseOFX reads the transactions of every bank and card statement in an
+// OFX file, either OFX 1.x (SGML, leaf elements without closing tags) or
+// OFX 2.x (XML). Statements in another currency than the given one are
+// rejected since their amounts would be posted unconverted.
+func ParseOFX(content io.Reader, currency string) ([]Entry, error) {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.Valid(data) {
+		// OFX 1.x files are usually CHARSET:1252
+		data = []byte(decodeCP1252(data))
+	}
+
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, ErrNotOFX
+	}
+	tags := scanOFXTags(string(data), start)
+
+	var entries []Entry
+	var current *Entry
+	var fields map[string]string
+	account := ""
+
+	for _, tag := range tags {
+		switch {
+		case tag.name == "STMTTRN" && !tag.closing:
+			current = &Entry{Line: tag.line, Account: account}
+			fields = make(map[string]string)
+		case tag.name == "STMTTRN" && tag.closing:
+			if current == nil {
+				continue
+			}
+			if len(entries) >= maxEntries {
+				return nil, ErrTooManyRows
+			}
+			entries = append(entries, buildOFXEntry(*current, fields, currency))
+			current = nil
+		case tag.closing || tag.value == "":
+			continue
+		case current != nil:
+			fields[tag.name] = tag.value
+		case tag.name == "ACCTID":
+			account = tag.value
+		case tag.name == "CURDEF":
+			if currency != "" && !strings.EqualFold(tag.value, currency) {
+				return nil, fmt.Errorf("statement is in %s but the account uses %s", strings.ToUpper(tag.value), currency)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+func buildOFXEntry(entry Entry, fields map[string]string, currency string) Entry {
+	entry.Reference = fields["FITID"]
+	entry.Description = fields["NAME"]
+	if memo := fields["MEMO"]; memo != "" && !strings.EqualFold(memo, entry.Description) {
+		if entry.Description == "" {
+			entry.Description = memo
+		} else {
+			entry.Description += " - " + memo
+		}
+	}
+	if entry.Description == "" {
+		entry.Description = fields["TRNTYPE"]
+	}
+
+	date, err := parseOFXDate(fields["DTPOSTED"])
+	if err != nil {
+		entry.Err = fmt.Errorf("invalid date %q", fields["DTPOSTED"])
+		return entry
+	}
+	entry.Date = date
+
+	value := fields["TRNAMT"]
+	if !strings.Contains(value, ".") {
+		value = strings.Replace(value, ",", ".", 1)
+	}
+	amount, err := money.Parse(value, currency, &ofxAmountLocale)
+	if err != nil {
+		entry.Err = fmt.Errorf("invalid amount %q", fields["TRNAMT"])
+		return entry
+	}
+	entry.Amount = amount.Amount
+
+	return entry
+}
+
+// scanOFXTags lists the tags of the document body with the text that
+// follows each one, which is the value of leaf elements.
+func scanOFXTags(data string, start int) []ofxTag {
+	var tags []ofxTag
+	line := 1 + strings.Count(data[:start], "\n")
+	pos := start
+
+	for {
+		open := strings.IndexByte(data[pos:], '<')
+		if open < 0 {
+			break
+		}
+		line += strings.Count(data[pos:pos+open], "\n")
+		open += pos
+
+		end := strings.IndexByte(data[open:], '>')
+		if end < 0 {
+			break
+		}
+		end += open
+
+		name := strings.TrimSpace(data[open+1 : end])
+		next := strings.IndexByte(data[end+1:], '<')
+		if next < 0 {
+			next = len(data) - end - 1
+		}
+		value := data[end+1 : end+1+next]
+		pos = end + 1
+
+		if strings.HasPrefix(name, "?") || strings.HasPrefix(name, "!") {
+			continue
+		}
+
+		tag := ofxTag{line: line}
+		if strings.HasPrefix(name, "/") {
+			tag.closing = true
+			name = name[1:]
+		}
+		// Drop XML attributes and self-closing markers
+		if i := strings.IndexAny(name, " \t\r\n/"); i >= 0 {
+			name = name[:i]
+		}
+		tag.name = strings.ToUpper(name)
+		tag.value = html.UnescapeString(strings.TrimSpace(value))
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// parseOFXDate reads the date part of an OFX datetime such as
+// 20260103, 20260103120000 or 20260103120000.000[-5:EST].
+func parseOFXDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return time.Parse("20060102", value[:8])
+}
+
+// cp1252 maps the bytes 0x80-0x9F, where Windows-1252 differs from
+// Latin-1; the five unassigned ones are kept as is.
+var cp1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8d, 'Ž', 0x8f,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9d, 'ž', 'Ÿ',
+}
+
+func decodeCP1252(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		if b >= 0x80 && b < 0xa0 {
+			runes[i] = cp1252[b-0x80]
+		} else {
+			runes[i] = rune(b)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,185 @@
+package importers
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func openFixture(t *testing.T, name string) *os.File {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func date(value string) time.Time {
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
+
+// wantEntry lists the fields checked for each parsed entry; Err is the
+// expected error text, if any.
+type wantEntry struct {
+	Date        string
+	Amount      int64
+	Description string
+	Category    string
+	Reference   string
+	Account     string
+	Err         string
+}
+
+func checkEntries(t *testing.T, got []Entry, want []wantEntry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		e := got[i]
+		if w.Err != "" {
+			if e.Err == nil || !strings.Contains(e.Err.Error(), w.Err) {
+				t.Errorf("entry %d: error = %v, want %q", i, e.Err, w.Err)
+			}
+			continue
+		}
+		if e.Err != nil {
+			t.Errorf("entry %d: unexpected error %v", i, e.Err)
+			continue
+		}
+		if !e.Date.Equal(date(w.Date)) {
+			t.Errorf("entry %d: date = %s, want %s", i, e.Date.Format("2006-01-02"), w.Date)
+		}
+		if e.Amount != w.Amount {
+			t.Errorf("entry %d: amount = %d, want %d", i, e.Amount, w.Amount)
+		}
+		if e.Description != w.Description {
+			t.Errorf("entry %d: description = %q, want %q", i, e.Description, w.Description)
+		}
+		if e.Category != w.Category {
+			t.Errorf("entry %d: category = %q, want %q", i, e.Category, w.Category)
+		}
+		if e.Reference != w.Reference {
+			t.Errorf("entry %d: reference = %q, want %q", i, e.Reference, w.Reference)
+		}
+		if e.Account != w.Account {
+			t.Errorf("entry %d: account = %q, want %q", i, e.Account, w.Account)
+		}
+	}
+}
+
+func TestParseOFX(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		currency string
+		want     []wantEntry
+		wantErr  string
+	}{
+		{
+			name:     "OFX 1.x SGML bank statement",
+			fixture:  "bank_v1.ofx",
+			currency: "USD",
+			want: []wantEntry{
+				{Date: "2026-01-02", Amount: 250000, Description: "ACME PAYROLL - January salary", Reference: "JAN-0001", Account: "000123456"},
+				{Date: "2026-01-15", Amount: -4567, Description: "CITY MARKET", Reference: "JAN-0002", Account: "000123456"},
+				{Date: "2026-01-31", Amount: -500, Description: "FEE", Reference: "JAN-0003", Account: "000123456"},
+			},
+		},
+		{
+			name:     "OFX 2.x XML card statement",
+			fixture:  "card_v2.ofx",
+			currency: "USD",
+			want: []wantEntry{
+				{Date: "2026-03-04", Amount: -1250, Description: "Smith & Sons Hardware", Reference: "CC-9001", Account: "4111222233334444"},
+				{Date: "2026-03-10", Amount: 10025, Description: "Refund - Returned order", Reference: "CC-9002", Account: "4111222233334444"},
+				{Err: `invalid date "2026-03"`},
+			},
+		},
+		{
+			name:     "CP1252 text",
+			fixture:  "cp1252.ofx",
+			currency: "EUR",
+			want: []wantEntry{
+				{Date: "2026-04-05", Amount: -840, Description: "Café de l’Église - Menu € 8,40", Reference: "CP-1", Account: "FR7612345"},
+			},
+		},
+		{
+			name:     "currency taken from the statement",
+			fixture:  "cp1252.ofx",
+			currency: "",
+			want: []wantEntry{
+				{Date: "2026-04-05", Amount: -840, Description: "Café de l’Église - Menu € 8,40", Reference: "CP-1", Account: "FR7612345"},
+			},
+		},
+		{
+			name:     "CURDEF mismatch",
+			fixture:  "curdef_mismatch.ofx",
+			currency: "USD",
+			wantErr:  "statement is in EUR but the account uses USD",
+		},
+		{
+			name:     "repeated FITID across statements",
+			fixture:  "repeated_fitid.ofx",
+			currency: "USD",
+			want: []wantEntry{
+				{Date: "2026-01-02", Amount: 250000, Description: "ACME PAYROLL - January salary", Reference: "JAN-0001", Account: "000123456"},
+				{Date: "2026-01-15", Amount: -4567, Description: "CITY MARKET", Reference: "JAN-0002", Account: "000123456"},
+				{Date: "2026-01-31", Amount: -500, Description: "FEE", Reference: "JAN-0003", Account: "000123456"},
+				{Date: "2026-01-15", Amount: -4567, Description: "CITY MARKET", Reference: "JAN-0002", Account: "000123456"},
+				{Date: "2026-02-02", Amount: 250000, Description: "ACME PAYROLL", Reference: "FEB-0001", Account: "000123456"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOFX(openFixture(t, tt.fixture), tt.currency)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("ParseOFX() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseOFX() unexpected error: %v", err)
+			}
+			checkEntries(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseOFXRejectsOtherFiles(t *testing.T) {
+	_, err := ParseOFX(strings.NewReader("Date,Amount\n2026-01-01,5.00\n"), "USD")
+	if err != ErrNotOFX {
+		t.Fatalf("ParseOFX() error = %v, want %v", err, ErrNotOFX)
+	}
+}
+
+func TestOFXFingerprintAcrossStatements(t *testing.T) {
+	january, err := ParseOFX(openFixture(t, "bank_v1.ofx"), "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	overlap, err := ParseOFX(openFixture(t, "bank_overlap_v1.ofx"), "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The transaction in both downloads is identified by its FITID even
+	// though its memo differs between them
+	if january[1].Fingerprint() != overlap[0].Fingerprint() {
+		t.Errorf("FITID JAN-0002 has different fingerprints in overlapping statements")
+	}
+	if january[0].Fingerprint() == overlap[1].Fingerprint() {
+		t.Errorf("different FITIDs share a fingerprint")
+	}
+}
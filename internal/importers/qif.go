@@ -0,0 +1,184 @@
+package importers
+
+import (
+	"balanca/pkg/money"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrNotQIF = errors.New("file is not a QIF statement")
+
+// QIFOptions says how to read the parts of a QIF file the format leaves
+// open. Quicken writes dates month first; many non-US banks write them day
+// first.
+type QIFOptions struct {
+	Currency string
+	DayFirst bool
+	Locale   *money.Locale // nil guesses the separators
+}
+
+// ParseQIF reads the transactions of a bank, cash or card QIF file. Records
+// are runs of lines prefixed with a field code and ended by "^". Investment
+// and list sections are not supported.
+func ParseQIF(content io.Reader, options QIFOptions) ([]Entry, error) {
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var entries []Entry
+	fields := make(map[byte]string)
+	line, recordLine := 0, 0
+	sawHeader := false
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if line == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		if strings.HasPrefix(text, "!") {
+			header := strings.ToLower(strings.TrimSpace(text))
+			if strings.HasPrefix(header, "!type:") {
+				kind := strings.TrimPrefix(header, "!type:")
+				if kind != "bank" && kind != "cash" && kind != "ccard" && kind != "oth a" && kind != "oth l" {
+					return nil, fmt.Errorf("unsupported QIF section %q", strings.TrimSpace(text))
+				}
+				sawHeader = true
+			}
+			continue
+		}
+
+		if text[0] == '^' {
+			if len(fields) > 0 {
+				if len(entries) >= maxEntries {
+					return nil, ErrTooManyRows
+				}
+				entries = append(entries, buildQIFEntry(recordLine, fields, options))
+			}
+			fields = make(map[byte]string)
+			continue
+		}
+
+		if len(fields) == 0 {
+			recordLine = line
+		}
+		code, value := text[0], strings.TrimSpace(text[1:])
+		// Split lines (S, E, $) repeat per split; only the first is kept
+		if _, exists := fields[code]; !exists {
+			fields[code] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The last record may lack its terminator
+	if len(fields) > 0 {
+		entries = append(entries, buildQIFEntry(recordLine, fields, options))
+	}
+
+	if !sawHeader && len(entries) == 0 {
+		return nil, ErrNotQIF
+	}
+
+	return entries, nil
+}
+
+func buildQIFEntry(line int, fields map[byte]string, options QIFOptions) Entry {
+	entry := Entry{
+		Line:        line,
+		Description: fields['P'],
+		Category:    qifCategory(fields['L']),
+	}
+	if entry.Category == "" {
+		// Split records may only categorize their splits
+		entry.Category = qifCategory(fields['S'])
+	}
+	if memo := fields['M']; memo != "" && !strings.EqualFold(memo, entry.Description) {
+		if entry.Description == "" {
+			entry.Description = memo
+		} else {
+			entry.Description += " - " + memo
+		}
+	}
+
+	date, err := parseQIFDate(fields['D'], options.DayFirst)
+	if err != nil {
+		entry.Err = err
+		return entry
+	}
+	entry.Date = date
+
+	value := fields['T']
+	if value == "" {
+		value = fields['U']
+	}
+	amount, err := money.Parse(value, options.Currency, options.Locale)
+	if err != nil {
+		entry.Err = fmt.Errorf("invalid amount %q", value)
+		return entry
+	}
+	entry.Amount = amount.Amount
+
+	return entry
+}
+
+// qifCategory drops the transfer brackets and subcategory of an L field,
+// e.g. "Food:Groceries" becomes "Food".
+func qifCategory(value string) string {
+	if strings.HasPrefix(value, "[") {
+		// Transfers between Quicken accounts have no category
+		return ""
+	}
+	if i := strings.IndexAny(value, ":/"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// parseQIFDate reads dates such as 01/03/2026, 1/3/26, 1/ 3'26 or
+// 2026-01-03. Two-digit years, and years written after an apostrophe as
+// Quicken does for 2000 onwards, are taken as 20xx.
+func parseQIFDate(value string, dayFirst bool) (time.Time, error) {
+	normalized := strings.NewReplacer("'", "/", "-", "/", ".", "/", " ", "").Replace(value)
+	parts := strings.Split(normalized, "/")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q", value)
+		}
+		numbers[i] = n
+	}
+
+	var year, month, day int
+	switch {
+	case len(parts[0]) == 4:
+		year, month, day = numbers[0], numbers[1], numbers[2]
+	case dayFirst:
+		day, month, year = numbers[0], numbers[1], numbers[2]
+	default:
+		month, day, year = numbers[0], numbers[1], numbers[2]
+	}
+	if year < 100 {
+		year += 2000
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != time.Month(month) || date.Day() != day {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
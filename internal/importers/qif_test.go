@@ -0,0 +1,115 @@
+package importers
+
+import (
+	"balanca/pkg/money"
+	"strings"
+	"testing"
+)
+
+func TestParseQIF(t *testing.T) {
+	commaDecimal := money.Locale{Decimal: ',', Group: '.'}
+
+	tests := []struct {
+		name    string
+		fixture string
+		options QIFOptions
+		want    []wantEntry
+	}{
+		{
+			name:    "month first with apostrophe years",
+			fixture: "us_dates.qif",
+			options: QIFOptions{Currency: "USD"},
+			want: []wantEntry{
+				{Date: "2026-01-03", Amount: -123456, Description: "Landlord - January rent", Category: "Housing"},
+				{Date: "2026-01-05", Amount: 200000, Description: "ACME Payroll", Category: "Salary"},
+				{Date: "2025-12-31", Amount: -1500, Description: "Transfer to savings"},
+			},
+		},
+		{
+			name:    "day first with comma decimals",
+			fixture: "day_first.qif",
+			options: QIFOptions{Currency: "EUR", DayFirst: true, Locale: &commaDecimal},
+			want: []wantEntry{
+				{Date: "2026-01-03", Amount: -123456, Description: "Supermarché", Category: "Food"},
+				{Date: "2026-01-31", Amount: -999, Description: "Streaming"},
+				{Err: `invalid date "31/02/2026"`},
+			},
+		},
+		{
+			name:    "split records",
+			fixture: "splits.qif",
+			options: QIFOptions{Currency: "USD"},
+			want: []wantEntry{
+				{Date: "2026-02-14", Amount: -15000, Description: "Department Store", Category: "Household"},
+				{Date: "2026-02-15", Amount: -2000, Description: "Corner Shop", Category: "Food"},
+				{Date: "2026-02-16", Amount: 2500, Description: "Unterminated record"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQIF(openFixture(t, tt.fixture), tt.options)
+			if err != nil {
+				t.Fatalf("ParseQIF() unexpected error: %v", err)
+			}
+			checkEntries(t, got, tt.want)
+		})
+	}
+}
+
+func TestParseQIFErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty file", content: "\r\n\r\n", wantErr: ErrNotQIF.Error()},
+		{name: "investment section", content: "!Type:Invst\nD01/01/2026\n^\n", wantErr: "unsupported QIF section"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQIF(strings.NewReader(tt.content), QIFOptions{Currency: "USD"})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ParseQIF() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseQIFDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		dayFirst bool
+		want     string
+		wantErr  bool
+	}{
+		{value: "01/03/2026", want: "2026-01-03"},
+		{value: "01/03/2026", dayFirst: true, want: "2026-03-01"},
+		{value: "1/ 3'26", want: "2026-01-03"},
+		{value: "12/31'25", want: "2025-12-31"},
+		{value: "2026-01-03", dayFirst: true, want: "2026-01-03"},
+		{value: "31.01.26", dayFirst: true, want: "2026-01-31"},
+		{value: "13/01/2026", wantErr: true},
+		{value: "Jan 3 2026", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseQIFDate(tt.value, tt.dayFirst)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseQIFDate(%q) = %s, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseQIFDate(%q) unexpected error: %v", tt.value, err)
+			}
+			if !got.Equal(date(tt.want)) {
+				t.Errorf("parseQIFDate(%q) = %s, want %s", tt.value, got.Format("2006-01-02"), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SpendingAnomaly records a DEBIT that was far above what its owner usually
+// spends in the category. Median and MAD (median absolute deviation) describe
+// the trailing history the debit was compared with.
+type SpendingAnomaly struct {
+	BaseModel
+	OwnerType     string    `gorm:"not null;index:idx_anomaly_owner" json:"owner_type"` // USER, GROUP
+	OwnerID       uuid.UUID `gorm:"not null;index:idx_anomaly_owner" json:"owner_id"`
+	TransactionID uuid.UUID `gorm:"not null;uniqueIndex" json:"transaction_id"`
+	Category      string    `gorm:"not null" json:"category"`
+	Amount        int64     `gorm:"not null" json:"amount"` // in minor units of Currency
+	Currency      string    `gorm:"size:3;not null" json:"currency"`
+	Median        int64     `gorm:"not null" json:"median"`
+	MAD           int64     `gorm:"not null" json:"mad"`
+	Score         float64   `gorm:"not null" json:"score"` // robust z-score
+	SampleSize    int       `gorm:"not null" json:"sample_size"`
+	WindowDays    int       `gorm:"not null" json:"window_days"`
+	RecordedBy    uuid.UUID `gorm:"not null" json:"recorded_by"`
+
+	// Relationships
+	Transaction *Transaction `gorm:"foreignKey:TransactionID" json:"transaction,omitempty"`
+}
+
+func (a *SpendingAnomaly) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApprovalPolicy holds a group's planned expenses above Threshold in
+// pending_approval until they are approved. In "managers" mode
+// RequiredApprovals managers other than the requester must approve and a
+// single manager can reject; in "majority" mode more than half of the active
+// members must approve, the requester counting as one.
+type ApprovalPolicy struct {
+	BaseModel
+	GroupID           uuid.UUID `gorm:"not null;uniqueIndex" json:"group_id"`
+	Threshold         int64     `gorm:"not null" json:"threshold"` // in minor units of the group currency
+	Mode              string    `gorm:"not null" json:"mode"`      // managers, majority
+	RequiredApprovals int       `gorm:"not null" json:"required_approvals"`
+	UpdatedBy         uuid.UUID `gorm:"not null" json:"updated_by"`
+}
+
+// ExpenseApproval is one member's decision on a pending planned expense.
+type ExpenseApproval struct {
+	BaseModel
+	PlannedExpenseID uuid.UUID `gorm:"not null;uniqueIndex:idx_expense_approver" json:"planned_expense_id"`
+	UserID           uuid.UUID `gorm:"not null;uniqueIndex:idx_expense_approver" json:"user_id"`
+	Decision         string    `gorm:"not null" json:"decision"` // approve, reject
+	Reason           string    `json:"reason"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (p *ApprovalPolicy) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (a *ExpenseApproval) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
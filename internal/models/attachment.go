@@ -0,0 +1,30 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Attachment is a file (usually a receipt) linked to a transaction or a
+// planned expense. The contents live in the blob store under StorageKey,
+// which is the SHA-256 checksum so identical uploads share one blob.
+type Attachment struct {
+	BaseModel
+	TransactionID    *uuid.UUID `gorm:"index" json:"transaction_id"`
+	PlannedExpenseID *uuid.UUID `gorm:"index" json:"planned_expense_id"`
+	GroupID          *uuid.UUID `gorm:"index" json:"group_id"`
+
+	FileName    string    `gorm:"not null" json:"file_name"`
+	ContentType string    `gorm:"not null" json:"content_type"`
+	Size        int64     `gorm:"not null" json:"size"`
+	Checksum    string    `gorm:"not null;index" json:"checksum"`
+	StorageKey  string    `gorm:"not null" json:"-"`
+	UploadedBy  uuid.UUID `gorm:"not null" json:"uploaded_by"`
+}
+
+func (a *Attachment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
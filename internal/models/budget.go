@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Budget caps spending in one category per period, for a user's personal
+// ledger or for a group. With Rollover set, the unspent part of earlier
+// periods (or the overspent part) carries into the current one.
+type Budget struct {
+	BaseModel
+	UserID    *uuid.UUID `gorm:"index" json:"user_id"`  // personal budgets
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"` // group budgets
+	Category  string     `gorm:"not null" json:"category"`
+	Period    string     `gorm:"not null" json:"period"` // weekly, monthly, yearly
+	Amount    int64      `gorm:"not null" json:"amount"` // in minor units of the owner's currency
+	Rollover  bool       `gorm:"not null" json:"rollover"`
+	StartDate time.Time  `gorm:"not null" json:"start_date"` // start of the first period
+	CreatedBy uuid.UUID  `gorm:"not null" json:"created_by"`
+}
+
+func (b *Budget) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
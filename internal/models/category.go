@@ -0,0 +1,34 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category is an entry of the category/source catalog. Transactions and
+// planned expenses still store the name as a string; the catalog decides
+// which names are accepted and keeps their spelling consistent.
+type Category struct {
+	BaseModel
+	Name     string     `gorm:"not null" json:"name"`
+	Kind     string     `gorm:"not null;index" json:"kind"` // category, source
+	Icon     string     `json:"icon"`
+	Color    string     `json:"color"`
+	ParentID *uuid.UUID `gorm:"index" json:"parent_id"`
+
+	// System entries have neither a user nor a group and are visible to everyone
+	IsSystem  bool       `gorm:"not null;default:false" json:"is_system"`
+	UserID    *uuid.UUID `gorm:"index" json:"user_id"`  // personal entries
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"` // group entries
+	CreatedBy *uuid.UUID `json:"created_by"`
+
+	// Relationships
+	Parent *Category `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
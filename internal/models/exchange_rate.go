@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is the price of one unit of BaseCurrency in QuoteCurrency,
+// valid from EffectiveDate until a newer rate for the same pair exists.
+type ExchangeRate struct {
+	BaseModel
+	BaseCurrency  string    `gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair_date" json:"base_currency"`
+	QuoteCurrency string    `gorm:"size:3;not null;uniqueIndex:idx_exchange_rate_pair_date" json:"quote_currency"`
+	Rate          string    `gorm:"type:numeric(24,12);not null" json:"rate"` // decimal string, kept exact
+	EffectiveDate time.Time `gorm:"type:date;not null;uniqueIndex:idx_exchange_rate_pair_date" json:"effective_date"`
+	Source        string    `json:"source"` // csv file name, cli, etc.
+}
+
+func (er *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	if er.ID == uuid.Nil {
+		er.ID = uuid.New()
+	}
+	return nil
+}
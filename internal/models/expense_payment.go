@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpensePayment is one payment, possibly one installment of several, towards
+// a planned expense. Payments of group expenses are debited from the group
+// and link to the ledger transaction.
+type ExpensePayment struct {
+	BaseModel
+	PlannedExpenseID uuid.UUID  `gorm:"not null;index" json:"planned_expense_id"`
+	Amount           int64      `gorm:"not null" json:"amount"` // in cents
+	PaidBy           uuid.UUID  `gorm:"not null;index" json:"paid_by"`
+	PaidAt           time.Time  `gorm:"not null" json:"paid_at"`
+	TransactionID    *uuid.UUID `gorm:"index" json:"transaction_id"`
+	Description      string     `json:"description"`
+
+	// Relationships
+	Payer *User `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
+}
+
+func (p *ExpensePayment) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
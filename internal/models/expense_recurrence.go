@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpenseRecurrence turns a planned expense into a series. It keeps the
+// schedule and a template of the expense; each occurrence is an ordinary
+// PlannedExpense linked back through RecurrenceID. The next occurrence is
+// spawned when the latest one is bought or cancelled, or at the latest when
+// the next due date arrives.
+type ExpenseRecurrence struct {
+	BaseModel
+	UserID  uuid.UUID  `gorm:"not null;index" json:"user_id"`
+	GroupID *uuid.UUID `gorm:"index" json:"group_id"`
+
+	// Schedule: occurrence n (from 0) is due Interval*n periods after
+	// StartDate. Monthly dates past the end of a month fall on its last day.
+	Frequency string     `gorm:"not null" json:"frequency"` // weekly, monthly, yearly
+	Interval  int        `gorm:"not null" json:"interval"`
+	StartDate time.Time  `gorm:"not null" json:"start_date"`
+	EndDate   *time.Time `json:"end_date"` // no occurrence is due after it
+	IsActive  bool       `gorm:"not null;index" json:"is_active"`
+
+	// Progress: the number of occurrences spawned, the latest of them and
+	// the due date of the next one
+	Occurrences   int        `gorm:"not null" json:"occurrences"`
+	LastExpenseID *uuid.UUID `json:"last_expense_id"`
+	NextDueDate   time.Time  `gorm:"not null;index" json:"next_due_date"`
+
+	// Template of the occurrences still to come
+	Item           string `gorm:"not null" json:"item"`
+	Description    string `json:"description"`
+	EstimatedPrice int64  `gorm:"not null" json:"estimated_price"` // in cents
+	Category       string `json:"category"`
+	Priority       string `json:"priority"`
+	Tags           []Tag  `gorm:"many2many:expense_recurrence_tags;" json:"tags,omitempty"`
+}
+
+func (r *ExpenseRecurrence) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpenseReminder records a due date reminder sent for a planned expense so
+// each one goes out only once. Reminders are keyed by the due date they were
+// sent for: moving the due date schedules a fresh set.
+type ExpenseReminder struct {
+	BaseModel
+	PlannedExpenseID uuid.UUID `gorm:"not null;uniqueIndex:idx_expense_reminder" json:"planned_expense_id"`
+	DueDate          time.Time `gorm:"not null;uniqueIndex:idx_expense_reminder" json:"due_date"`
+	Kind             string    `gorm:"not null;uniqueIndex:idx_expense_reminder" json:"kind"` // upcoming, overdue, escalation
+	Days             int       `gorm:"not null;uniqueIndex:idx_expense_reminder" json:"days"` // before or after the due date
+}
+
+func (r *ExpenseReminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpenseSplit divides a group expense between selected members. What a
+// member pays for a split expense out of their own pocket is then owed to them
+// by the other members according to their shares, rather than by the group.
+type ExpenseSplit struct {
+	BaseModel
+	PlannedExpenseID uuid.UUID `gorm:"not null;uniqueIndex" json:"planned_expense_id"`
+	GroupID          uuid.UUID `gorm:"not null;index" json:"group_id"`
+	Method           string    `gorm:"not null" json:"method"` // equal, exact, percentage, shares
+	CreatedBy        uuid.UUID `gorm:"not null" json:"created_by"`
+
+	// Relationships
+	PlannedExpense *PlannedExpense     `gorm:"foreignKey:PlannedExpenseID" json:"planned_expense,omitempty"`
+	Shares         []ExpenseSplitShare `gorm:"foreignKey:SplitID;constraint:OnDelete:CASCADE" json:"shares"`
+}
+
+// ExpenseSplitShare is one member's part of a split. Weight is in cents for
+// exact splits, in hundredths of a percent for percentage splits and in
+// hundredths of a share for share splits; equal splits weigh every member 1.
+type ExpenseSplitShare struct {
+	BaseModel
+	SplitID uuid.UUID `gorm:"not null;uniqueIndex:idx_split_member" json:"split_id"`
+	UserID  uuid.UUID `gorm:"not null;uniqueIndex:idx_split_member" json:"user_id"`
+	Weight  int64     `gorm:"not null" json:"weight"`
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (s *ExpenseSplit) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+func (s *ExpenseSplitShare) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -11,7 +11,8 @@ type Group struct {
 	BaseModel
 	Name        string    `gorm:"not null" json:"name"`
 	Description string    `json:"description"`
-	Balance     int64     `gorm:"default:0" json:"balance"`
+	Balance     int64     `gorm:"default:0" json:"balance"` // in minor units of Currency
+	Currency    string    `gorm:"size:3;not null;default:'USD'" json:"currency"`
 	CreatedBy   uuid.UUID `gorm:"not null" json:"created_by"`
 	IsActive    bool      `gorm:"default:true" json:"is_active"`
 
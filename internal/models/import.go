@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImportProfile describes how the columns of a bank's CSV export map onto
+// transactions, so a statement from the same bank can be imported again
+// without re-entering the mapping. A profile belongs to a user or a group.
+type ImportProfile struct {
+	BaseModel
+	Name      string     `gorm:"not null" json:"name"`
+	UserID    *uuid.UUID `gorm:"index" json:"user_id"`
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"`
+	CreatedBy uuid.UUID  `gorm:"not null" json:"created_by"`
+
+	Delimiter  string `gorm:"size:1;not null;default:','" json:"delimiter"`
+	HasHeader  bool   `gorm:"not null;default:true" json:"has_header"`
+	DateColumn string `gorm:"not null" json:"date_column"`
+	DateFormat string `gorm:"not null;default:'YYYY-MM-DD'" json:"date_format"`
+	// Columns are header names, or 1-based positions for files without a header
+	AmountColumn      string `json:"amount_column"`
+	DebitColumn       string `json:"debit_column"`
+	CreditColumn      string `json:"credit_column"`
+	DescriptionColumn string `json:"description_column"`
+	CategoryColumn    string `json:"category_column"`
+	SignConvention    string `gorm:"not null;default:'signed'" json:"sign_convention"` // signed, inverted, split
+	Locale            string `json:"locale"`                                           // empty means guess separators
+
+	DefaultCategory string `json:"default_category"`
+	DefaultSource   string `json:"default_source"`
+}
+
+// ImportBatch is one uploaded statement. Its rows are kept so the upload can
+// be previewed before any of them is posted to the ledger.
+type ImportBatch struct {
+	BaseModel
+	UserID    uuid.UUID  `gorm:"not null;index" json:"user_id"` // uploader
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"`
+	ProfileID *uuid.UUID `json:"profile_id"`
+	Format    string     `gorm:"not null" json:"format"` // csv, ofx, qif
+	FileName  string     `json:"file_name"`
+	Currency  string     `gorm:"size:3;not null" json:"currency"`
+	Status    string     `gorm:"not null;default:'pending'" json:"status"` // pending, committing, completed
+
+	Rows []ImportRow `gorm:"foreignKey:BatchID" json:"rows,omitempty"`
+}
+
+// ImportRow is a parsed statement line. Amount is always positive; Type says
+// whether money came in or went out.
+type ImportRow struct {
+	BaseModel
+	BatchID       uuid.UUID  `gorm:"not null;index" json:"batch_id"`
+	RowNumber     int        `gorm:"not null" json:"row_number"`
+	Date          *time.Time `json:"date"`
+	Type          string     `json:"type"` // CREDIT, DEBIT
+	Amount        int64      `json:"amount"`
+	Description   string     `json:"description"`
+	Category      string     `json:"category"`
+	Reference     string     `json:"reference"`                    // bank-provided transaction ID, if any
+	Fingerprint   string     `gorm:"index" json:"fingerprint"`     // used to detect duplicates
+	Status        string     `gorm:"not null;index" json:"status"` // pending, duplicate, invalid, imported
+	Error         string     `json:"error"`                        // why the row is invalid or failed to import
+	DuplicateOf   *uuid.UUID `json:"duplicate_of"`                 // existing transaction
+	TransactionID *uuid.UUID `gorm:"index" json:"transaction_id"`  // created transaction
+}
+
+func (p *ImportProfile) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (b *ImportBatch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *ImportRow) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
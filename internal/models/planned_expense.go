@@ -11,11 +11,12 @@ type PlannedExpense struct {
 	BaseModel
 	Item           string `gorm:"not null" json:"item"`
 	Description    string `json:"description"`
-	EstimatedPrice int64  `gorm:"not null" json:"estimated_price"` // in cents
-	ActualPrice    *int64 `json:"actual_price"`                    // in cents
+	EstimatedPrice int64  `gorm:"not null" json:"estimated_price"`       // in cents
+	ActualPrice    *int64 `json:"actual_price"`                          // in cents
+	PaidAmount     int64  `gorm:"not null;default:0" json:"paid_amount"` // in cents, paid to date
 	Category       string `json:"category"`
-	Status         string `gorm:"not null;default:'planned'" json:"status"` // planned, bought, cancelled
-	Priority       string `gorm:"default:'medium'" json:"priority"`         // low, medium, high
+	Status         string `gorm:"not null;default:'planned';index:idx_expense_status_due" json:"status"` // planned, pending_approval, proposed, rejected, partially_paid, bought, cancelled
+	Priority       string `gorm:"default:'medium'" json:"priority"`                                      // low, medium, high
 
 	// For group expenses
 	GroupID *uuid.UUID `gorm:"index" json:"group_id"`
@@ -27,13 +28,19 @@ type PlannedExpense struct {
 	PaidBy *uuid.UUID `gorm:"index" json:"paid_by"`
 	PaidAt *time.Time `json:"paid_at"`
 
-	DueDate *time.Time `json:"due_date"`
+	DueDate *time.Time `gorm:"index:idx_expense_status_due" json:"due_date"`
+
+	// Set on occurrences of a recurring expense
+	RecurrenceID *uuid.UUID `gorm:"index" json:"recurrence_id"`
 
 	// Relationships
-	User        *User        `gorm:"foreignKey:UserID" json:"user"`
-	Group       *Group       `gorm:"foreignKey:GroupID" json:"group,omitempty"`
-	Payer       *User        `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
-	Transaction *Transaction `gorm:"foreignKey:PlannedExpenseID" json:"transaction,omitempty"`
+	User        *User              `gorm:"foreignKey:UserID" json:"user"`
+	Group       *Group             `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Payer       *User              `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
+	Payments    []ExpensePayment   `gorm:"foreignKey:PlannedExpenseID" json:"payments,omitempty"`
+	Transaction *Transaction       `gorm:"foreignKey:PlannedExpenseID" json:"transaction,omitempty"`
+	Tags        []Tag              `gorm:"many2many:planned_expense_tags;" json:"tags,omitempty"`
+	Recurrence  *ExpenseRecurrence `gorm:"foreignKey:RecurrenceID" json:"recurrence,omitempty"`
 }
 
 func (pe *PlannedExpense) BeforeCreate(tx *gorm.DB) error {
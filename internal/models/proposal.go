@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpenseProposal puts a group's planned expense to a vote of its active
+// members. While it is open the expense is "proposed" and cannot be paid.
+// The vote closes at the deadline, or as soon as every member has voted:
+// it passes when at least Quorum percent of the members voted (abstentions
+// included) and the yes votes outnumber the no votes.
+type ExpenseProposal struct {
+	BaseModel
+	PlannedExpenseID uuid.UUID  `gorm:"not null;index" json:"planned_expense_id"`
+	GroupID          uuid.UUID  `gorm:"not null;index" json:"group_id"`
+	ProposedBy       uuid.UUID  `gorm:"not null" json:"proposed_by"`
+	Description      string     `json:"description"`
+	Quorum           int        `gorm:"not null" json:"quorum"` // percent of active members
+	Deadline         time.Time  `gorm:"not null;index" json:"deadline"`
+	Status           string     `gorm:"not null;default:'open';index" json:"status"` // open, passed, failed, cancelled
+	ClosedAt         *time.Time `json:"closed_at"`
+
+	// Relationships
+	PlannedExpense PlannedExpense `gorm:"foreignKey:PlannedExpenseID" json:"planned_expense,omitempty"`
+	Proposer       User           `gorm:"foreignKey:ProposedBy" json:"proposer,omitempty"`
+	Votes          []ProposalVote `gorm:"foreignKey:ProposalID" json:"votes,omitempty"`
+}
+
+// ProposalVote is a member's vote on a proposal. Members can change their
+// vote while the proposal is open.
+type ProposalVote struct {
+	BaseModel
+	ProposalID uuid.UUID `gorm:"not null;uniqueIndex:idx_proposal_voter" json:"proposal_id"`
+	UserID     uuid.UUID `gorm:"not null;uniqueIndex:idx_proposal_voter" json:"user_id"`
+	Vote       string    `gorm:"not null" json:"vote"` // yes, no, abstain
+
+	// Relationships
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (p *ExpenseProposal) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+func (v *ProposalVote) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Reimbursement is money a group owes a member who paid for a group expense
+// out of their personal balance or outside the app. Settling it pays the
+// member back from the group balance. Payments towards a split expense are
+// kept with the "split" status instead: the other members owe their shares
+// and the group owes nothing.
+type Reimbursement struct {
+	BaseModel
+	GroupID          uuid.UUID  `gorm:"not null;index" json:"group_id"`
+	UserID           uuid.UUID  `gorm:"not null;index" json:"user_id"` // the member owed
+	PlannedExpenseID uuid.UUID  `gorm:"not null;index" json:"planned_expense_id"`
+	PaymentID        uuid.UUID  `gorm:"not null;uniqueIndex" json:"payment_id"`
+	Amount           int64      `gorm:"not null" json:"amount"` // in cents of the group currency
+	Currency         string     `gorm:"not null" json:"currency"`
+	FundingSource    string     `gorm:"not null" json:"funding_source"`                     // personal, external
+	Status           string     `gorm:"not null;default:'outstanding';index" json:"status"` // outstanding, settled, split
+	SettledBy        *uuid.UUID `json:"settled_by"`
+	SettledAt        *time.Time `json:"settled_at"`
+	TransactionID    *uuid.UUID `json:"transaction_id"` // the group debit that settled it
+
+	// Relationships
+	User           *User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	PlannedExpense *PlannedExpense `gorm:"foreignKey:PlannedExpenseID" json:"planned_expense,omitempty"`
+	Payment        *ExpensePayment `gorm:"foreignKey:PaymentID" json:"payment,omitempty"`
+}
+
+func (r *Reimbursement) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
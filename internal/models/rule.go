@@ -0,0 +1,40 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CategorizationRule sets the category of new transactions, and optionally
+// adds a tag, when their description and amount match. A rule belongs either
+// to a user (personal transactions) or to a group. Rules are tried in
+// ascending Priority order and the first match wins.
+type CategorizationRule struct {
+	BaseModel
+	Name      string     `gorm:"not null" json:"name"`
+	UserID    *uuid.UUID `gorm:"index" json:"user_id"`  // personal rules
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"` // group rules
+	Priority  int        `gorm:"not null" json:"priority"`
+	IsActive  bool       `gorm:"not null" json:"is_active"`
+	CreatedBy uuid.UUID  `gorm:"not null" json:"created_by"`
+
+	// Conditions; empty ones match everything
+	DescriptionContains string `json:"description_contains"` // case-insensitive
+	Type                string `json:"type"`                 // CREDIT, DEBIT
+	MinAmount           *int64 `json:"min_amount"`           // inclusive
+	MaxAmount           *int64 `json:"max_amount"`           // inclusive
+
+	// Actions
+	Category string     `gorm:"not null" json:"category"`
+	TagID    *uuid.UUID `gorm:"index" json:"tag_id"`
+
+	// Relationships
+	Tag *Tag `gorm:"foreignKey:TagID" json:"tag,omitempty"`
+}
+
+func (r *CategorizationRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Settlement records one group member paying another back, in cash, mobile
+// money or any other way outside the group balance. The payer records it and
+// the receiver confirms it; only confirmed settlements count towards the
+// group's who-owes-whom balances. When MoveBalances is set, confirming it also
+// debits the payer's personal balance and credits the receiver's.
+type Settlement struct {
+	BaseModel
+	GroupID           uuid.UUID  `gorm:"not null;index" json:"group_id"`
+	FromUserID        uuid.UUID  `gorm:"not null;index" json:"from_user_id"` // the member who paid
+	ToUserID          uuid.UUID  `gorm:"not null;index" json:"to_user_id"`   // the member who received
+	Amount            int64      `gorm:"not null" json:"amount"`             // in cents of the group currency
+	Currency          string     `gorm:"not null" json:"currency"`
+	Method            string     `gorm:"not null" json:"method"` // cash, mobile_money, bank_transfer, other
+	Reference         string     `json:"reference"`
+	Note              string     `json:"note"`
+	MoveBalances      bool       `gorm:"default:false" json:"move_balances"`
+	FromAmount        int64      `json:"from_amount"` // debited from the payer, in cents of their currency
+	FromExchangeRate  string     `json:"from_exchange_rate"`
+	Status            string     `gorm:"not null;default:'pending';index" json:"status"` // pending, confirmed, rejected, cancelled
+	RejectionReason   string     `json:"rejection_reason"`
+	ConfirmedAt       *time.Time `gorm:"index" json:"confirmed_at"`
+	FromTransactionID *uuid.UUID `json:"from_transaction_id"`
+	ToTransactionID   *uuid.UUID `json:"to_transaction_id"`
+
+	// Relationships
+	FromUser *User `gorm:"foreignKey:FromUserID" json:"from_user,omitempty"`
+	ToUser   *User `gorm:"foreignKey:ToUserID" json:"to_user,omitempty"`
+}
+
+func (s *Settlement) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShoppingList groups the items bought in one trip to the shops. A list
+// belongs to the user who created it or, when GroupID is set, to a group.
+// Checking it out posts a single debit with a line item per checked item.
+type ShoppingList struct {
+	BaseModel
+	Name          string     `gorm:"not null" json:"name"`
+	UserID        uuid.UUID  `gorm:"not null;index" json:"user_id"` // the member who created it
+	GroupID       *uuid.UUID `gorm:"index" json:"group_id"`
+	Status        string     `gorm:"not null;default:'open';index" json:"status"` // open, checked_out
+	TransactionID *uuid.UUID `json:"transaction_id"`                              // the checkout debit
+	CheckedOutBy  *uuid.UUID `json:"checked_out_by"`
+	CheckedOutAt  *time.Time `json:"checked_out_at"`
+
+	// Relationships
+	User  *User              `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Group *Group             `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Items []ShoppingListItem `gorm:"foreignKey:ListID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+// ShoppingListItem is one line of a shopping list. UnitPrice is the expected
+// price; ActualUnitPrice is the shelf price noted when the item is checked
+// off. Items added from a planned expense pay towards it at checkout and mark
+// it bought once it is paid in full.
+type ShoppingListItem struct {
+	BaseModel
+	ListID           uuid.UUID  `gorm:"not null;index" json:"list_id"`
+	Name             string     `gorm:"not null" json:"name"`
+	Quantity         int        `gorm:"not null;default:1" json:"quantity"`
+	UnitPrice        int64      `gorm:"not null;default:0" json:"unit_price"` // in cents
+	ActualUnitPrice  *int64     `json:"actual_unit_price"`                    // in cents
+	Category         string     `json:"category"`
+	Checked          bool       `gorm:"default:false" json:"checked"`
+	CheckedBy        *uuid.UUID `json:"checked_by"`
+	CheckedAt        *time.Time `json:"checked_at"`
+	Status           string     `gorm:"not null;default:'pending'" json:"status"` // pending, bought
+	PlannedExpenseID *uuid.UUID `gorm:"index" json:"planned_expense_id"`
+}
+
+func (sl *ShoppingList) BeforeCreate(tx *gorm.DB) error {
+	if sl.ID == uuid.Nil {
+		sl.ID = uuid.New()
+	}
+	return nil
+}
+
+func (si *ShoppingListItem) BeforeCreate(tx *gorm.DB) error {
+	if si.ID == uuid.Nil {
+		si.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Tag is a free-form label that can be attached to transactions and planned
+// expenses. A tag belongs either to a user (personal) or to a group.
+type Tag struct {
+	BaseModel
+	Name      string     `gorm:"not null" json:"name"`
+	Color     string     `json:"color"`
+	UserID    *uuid.UUID `gorm:"index" json:"user_id"`  // personal tags
+	GroupID   *uuid.UUID `gorm:"index" json:"group_id"` // group tags
+	CreatedBy uuid.UUID  `gorm:"not null" json:"created_by"`
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
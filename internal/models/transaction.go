@@ -9,14 +9,20 @@ type Transaction struct {
 	BaseModel
 	OwnerType   string                 `gorm:"not null;index" json:"owner_type"` // USER, GROUP
 	OwnerID     uuid.UUID              `gorm:"not null;index" json:"owner_id"`
-	Type        string                 `gorm:"not null" json:"type"`    // CREDIT, DEBIT
-	Amount      int64                  `gorm:"not null" json:"amount"`  // in cents
+	Type        string                 `gorm:"not null" json:"type"`   // CREDIT, DEBIT
+	Amount      int64                  `gorm:"not null" json:"amount"` // in minor units of Currency
+	Currency    string                 `gorm:"size:3;not null;default:'USD'" json:"currency"`
 	Balance     int64                  `gorm:"not null" json:"balance"` // balance after transaction
 	Category    string                 `json:"category"`                // food, transport, home, personal, etc.
 	Source      string                 `json:"source"`                  // salary, gig, gift, transfer, etc.
 	Description string                 `json:"description"`
 	Metadata    map[string]interface{} `gorm:"type:jsonb" json:"metadata"`
 
+	// For transactions entered in a foreign currency
+	OriginalAmount   *int64 `json:"original_amount"` // in minor units of OriginalCurrency
+	OriginalCurrency string `gorm:"size:3" json:"original_currency"`
+	ExchangeRate     string `gorm:"size:32" json:"exchange_rate"` // original -> Currency
+
 	// For group transactions
 	GroupID          *uuid.UUID `gorm:"index" json:"group_id"`
 	PaidBy           *uuid.UUID `gorm:"index" json:"paid_by"`
@@ -26,10 +32,22 @@ type Transaction struct {
 	UserID uuid.UUID `gorm:"not null;index" json:"user_id"`
 
 	// Relationships
-	User           User           `gorm:"foreignKey:UserID" json:"user"`
-	Group          Group          `gorm:"foreignKey:GroupID" json:"group,omitempty"`
-	Payer          User           `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
-	PlannedExpense PlannedExpense `gorm:"foreignKey:PlannedExpenseID" json:"planned_expense,omitempty"`
+	User           User                  `gorm:"foreignKey:UserID" json:"user"`
+	Group          Group                 `gorm:"foreignKey:GroupID" json:"group,omitempty"`
+	Payer          User                  `gorm:"foreignKey:PaidBy" json:"payer,omitempty"`
+	PlannedExpense PlannedExpense        `gorm:"foreignKey:PlannedExpenseID" json:"planned_expense,omitempty"`
+	LineItems      []TransactionLineItem `gorm:"foreignKey:TransactionID" json:"line_items,omitempty"`
+	Tags           []Tag                 `gorm:"many2many:transaction_tags;" json:"tags,omitempty"`
+}
+
+// TransactionLineItem splits a transaction across several categories. When a
+// transaction has line items their amounts always add up to its Amount.
+type TransactionLineItem struct {
+	BaseModel
+	TransactionID uuid.UUID `gorm:"not null;index" json:"transaction_id"`
+	Category      string    `gorm:"not null" json:"category"`
+	Amount        int64     `gorm:"not null" json:"amount"` // in minor units of the transaction's Currency
+	Note          string    `json:"note"`
 }
 
 func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
@@ -38,3 +56,10 @@ func (t *Transaction) BeforeCreate(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+func (li *TransactionLineItem) BeforeCreate(tx *gorm.DB) error {
+	if li.ID == uuid.Nil {
+		li.ID = uuid.New()
+	}
+	return nil
+}
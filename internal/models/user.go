@@ -20,7 +20,8 @@ type User struct {
 	FirstName    string `json:"first_name"`
 	LastName     string `json:"last_name"`
 	PasswordHash string `gorm:"not null" json:"-"`
-	Balance      int64  `gorm:"default:0" json:"balance"` // in cents
+	Balance      int64  `gorm:"default:0" json:"balance"` // in minor units of Currency
+	Currency     string `gorm:"size:3;not null;default:'USD'" json:"currency"`
 	IsActive     bool   `gorm:"default:true" json:"is_active"`
 
 	// Relationships
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AnomalyRepository interface {
+	Create(anomaly *models.SpendingAnomaly) error
+	FindByOwner(ownerType string, ownerID uuid.UUID, page, limit int) ([]models.SpendingAnomaly, int64, error)
+}
+
+type anomalyRepository struct {
+	db *gorm.DB
+}
+
+func NewAnomalyRepository(db *gorm.DB) AnomalyRepository {
+	return &anomalyRepository{db: db}
+}
+
+func (r *anomalyRepository) Create(anomaly *models.SpendingAnomaly) error {
+	return r.db.Create(anomaly).Error
+}
+
+func (r *anomalyRepository) FindByOwner(ownerType string, ownerID uuid.UUID, page, limit int) ([]models.SpendingAnomaly, int64, error) {
+	var anomalies []models.SpendingAnomaly
+	var total int64
+
+	query := r.db.Model(&models.SpendingAnomaly{}).Where("owner_type = ? AND owner_id = ?", ownerType, ownerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Transaction").Order("created_at DESC").
+		Offset((page - 1) * limit).Limit(limit).Find(&anomalies).Error
+	return anomalies, total, err
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApprovalRepository interface {
+	FindPolicy(groupID uuid.UUID) (*models.ApprovalPolicy, error)
+	SavePolicy(policy *models.ApprovalPolicy) error
+	DeletePolicy(groupID uuid.UUID) error
+	FindDecisions(expenseID uuid.UUID) ([]models.ExpenseApproval, error)
+	DeleteDecisions(expenseID uuid.UUID) error
+	FindPending(groupID uuid.UUID) ([]models.PlannedExpense, error)
+	Release(expenseID uuid.UUID) (bool, error)
+}
+
+type approvalRepository struct {
+	db *gorm.DB
+}
+
+func NewApprovalRepository(db *gorm.DB) ApprovalRepository {
+	return &approvalRepository{db: db}
+}
+
+// FindPolicy returns the group's approval policy, or nil when it has none.
+func (r *approvalRepository) FindPolicy(groupID uuid.UUID) (*models.ApprovalPolicy, error) {
+	var policy models.ApprovalPolicy
+	err := r.db.Where("group_id = ?", groupID).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+func (r *approvalRepository) SavePolicy(policy *models.ApprovalPolicy) error {
+	return r.db.Save(policy).Error
+}
+
+// DeletePolicy removes the policy for good so the group can set a new one.
+func (r *approvalRepository) DeletePolicy(groupID uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.ApprovalPolicy{}, "group_id = ?", groupID).Error
+}
+
+func (r *approvalRepository) FindDecisions(expenseID uuid.UUID) ([]models.ExpenseApproval, error) {
+	var decisions []models.ExpenseApproval
+	err := r.db.Preload("User").Where("planned_expense_id = ?", expenseID).
+		Order("created_at ASC").Find(&decisions).Error
+	return decisions, err
+}
+
+// DeleteDecisions clears the decisions on an expense whose approval starts
+// over, so members can decide again.
+func (r *approvalRepository) DeleteDecisions(expenseID uuid.UUID) error {
+	return r.db.Unscoped().Delete(&models.ExpenseApproval{}, "planned_expense_id = ?", expenseID).Error
+}
+
+// FindPending returns the group's expenses awaiting approval.
+func (r *approvalRepository) FindPending(groupID uuid.UUID) ([]models.PlannedExpense, error) {
+	var expenses []models.PlannedExpense
+	err := r.db.Where("group_id = ? AND status = 'pending_approval'", groupID).
+		Order("created_at ASC").Find(&expenses).Error
+	return expenses, err
+}
+
+// Release makes an expense awaiting approval payable, reporting false when it
+// was decided in the meantime.
+func (r *approvalRepository) Release(expenseID uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status = 'pending_approval'", expenseID).
+		Update("status", "planned")
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository interface {
+	Create(attachment *models.Attachment) error
+	FindByID(id uuid.UUID) (*models.Attachment, error)
+	FindByTransaction(transactionID uuid.UUID) ([]models.Attachment, error)
+	FindByPlannedExpense(expenseID uuid.UUID) ([]models.Attachment, error)
+	FindDuplicate(transactionID, expenseID *uuid.UUID, checksum string) (*models.Attachment, error)
+	CountByChecksum(checksum string) (int64, error)
+	Delete(id uuid.UUID) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *attachmentRepository) FindByID(id uuid.UUID) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := r.db.Where("id = ?", id).First(&attachment).Error
+	return &attachment, err
+}
+
+func (r *attachmentRepository) FindByTransaction(transactionID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("transaction_id = ?", transactionID).
+		Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+func (r *attachmentRepository) FindByPlannedExpense(expenseID uuid.UUID) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	err := r.db.Where("planned_expense_id = ?", expenseID).
+		Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
+
+// FindDuplicate returns the attachment with the same contents on the same
+// record, or nil when the file has not been attached there yet.
+func (r *attachmentRepository) FindDuplicate(transactionID, expenseID *uuid.UUID, checksum string) (*models.Attachment, error) {
+	var attachment models.Attachment
+	query := r.db.Where("checksum = ?", checksum)
+	if transactionID != nil {
+		query = query.Where("transaction_id = ?", *transactionID)
+	} else {
+		query = query.Where("planned_expense_id = ?", *expenseID)
+	}
+
+	err := query.First(&attachment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *attachmentRepository) CountByChecksum(checksum string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Attachment{}).Where("checksum = ?", checksum).Count(&count).Error
+	return count, err
+}
+
+func (r *attachmentRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Attachment{}, "id = ?", id).Error
+}
@@ -14,6 +14,8 @@ type AuditLogRepository interface {
 	FindByGroup(groupID uuid.UUID, page, limit int) ([]models.AuditLog, int64, error)
 	FindByUser(userID uuid.UUID, page, limit int) ([]models.AuditLog, int64, error)
 	FindByDateRange(startDate, endDate time.Time, page, limit int) ([]models.AuditLog, int64, error)
+	FindByGroupAfter(groupID uuid.UUID, cursor *Cursor, limit int) ([]models.AuditLog, *Cursor, error)
+	FindByUserAfter(userID uuid.UUID, cursor *Cursor, limit int) ([]models.AuditLog, *Cursor, error)
 }
 
 type auditLogRepository struct {
@@ -98,4 +100,35 @@ func (r *auditLogRepository) FindByDateRange(startDate, endDate time.Time, page,
 
 	err = query.Offset(offset).Limit(limit).Find(&logs).Error
 	return logs, total, err
-}
\ No newline at end of file
+}
+
+func (r *auditLogRepository) FindByGroupAfter(groupID uuid.UUID, cursor *Cursor, limit int) ([]models.AuditLog, *Cursor, error) {
+	query := r.db.Where("group_id = ?", groupID)
+	return r.findAfter(query, cursor, limit)
+}
+
+func (r *auditLogRepository) FindByUserAfter(userID uuid.UUID, cursor *Cursor, limit int) ([]models.AuditLog, *Cursor, error) {
+	query := r.db.Where("performed_by = ?", userID)
+	return r.findAfter(query, cursor, limit)
+}
+
+// findAfter loads one keyset page ordered by (performed_at, id).
+func (r *auditLogRepository) findAfter(query *gorm.DB, cursor *Cursor, limit int) ([]models.AuditLog, *Cursor, error) {
+	var logs []models.AuditLog
+
+	query = applyCursor(query.Preload("User").Preload("Group"),
+		"audit_logs.performed_at", "audit_logs.id", cursor)
+
+	if err := query.Limit(limit + 1).Find(&logs).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var next *Cursor
+	if len(logs) > limit {
+		logs = logs[:limit]
+		last := logs[limit-1]
+		next = &Cursor{Timestamp: last.PerformedAt, ID: last.ID}
+	}
+
+	return logs, next, nil
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BudgetRepository interface {
+	Create(budget *models.Budget) error
+	FindByID(id uuid.UUID) (*models.Budget, error)
+	FindByUser(userID uuid.UUID) ([]models.Budget, error)
+	FindByGroup(groupID uuid.UUID) ([]models.Budget, error)
+	Update(budget *models.Budget) error
+	Delete(id uuid.UUID) error
+}
+
+type budgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) BudgetRepository {
+	return &budgetRepository{db: db}
+}
+
+func (r *budgetRepository) Create(budget *models.Budget) error {
+	return r.db.Create(budget).Error
+}
+
+func (r *budgetRepository) FindByID(id uuid.UUID) (*models.Budget, error) {
+	var budget models.Budget
+	err := r.db.Where("id = ?", id).First(&budget).Error
+	return &budget, err
+}
+
+func (r *budgetRepository) FindByUser(userID uuid.UUID) ([]models.Budget, error) {
+	var budgets []models.Budget
+	err := r.db.Where("user_id = ? AND group_id IS NULL", userID).
+		Order("category ASC, period ASC").Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *budgetRepository) FindByGroup(groupID uuid.UUID) ([]models.Budget, error) {
+	var budgets []models.Budget
+	err := r.db.Where("group_id = ?", groupID).
+		Order("category ASC, period ASC").Find(&budgets).Error
+	return budgets, err
+}
+
+func (r *budgetRepository) Update(budget *models.Budget) error {
+	return r.db.Save(budget).Error
+}
+
+func (r *budgetRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.Budget{}, "id = ?", id).Error
+}
@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CategoryRepository interface {
+	Create(category *models.Category) error
+	FindByID(id uuid.UUID) (*models.Category, error)
+	FindVisible(kind string, userID, groupID *uuid.UUID) ([]models.Category, error)
+	FindByName(kind string, userID, groupID *uuid.UUID, name string) (*models.Category, error)
+	FindChildren(parentID uuid.UUID) ([]models.Category, error)
+	Update(category *models.Category) error
+	Rename(category *models.Category, oldName string) error
+	Merge(target *models.Category, userID, groupID *uuid.UUID, sources []models.Category, names []string) error
+	Delete(category *models.Category) error
+	EnsureSystem(categories []models.Category) error
+}
+
+type categoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+	return &categoryRepository{db: db}
+}
+
+func (r *categoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *categoryRepository) FindByID(id uuid.UUID) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("id = ?", id).First(&category).Error
+	return &category, err
+}
+
+// FindVisible returns the system entries together with the entries of a
+// personal (userID) or group (groupID) scope. An empty kind returns both kinds.
+func (r *categoryRepository) FindVisible(kind string, userID, groupID *uuid.UUID) ([]models.Category, error) {
+	var categories []models.Category
+	query := r.db
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	err := r.scoped(query, userID, groupID).Order("kind ASC").Order("is_system DESC").Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// FindByName looks an entry up case-insensitively among the system entries
+// and the given scope. It returns nil when nothing matches.
+func (r *categoryRepository) FindByName(kind string, userID, groupID *uuid.UUID, name string) (*models.Category, error) {
+	var category models.Category
+	query := r.db.Where("kind = ? AND LOWER(name) = ?", kind, normalizeName(name))
+	err := r.scoped(query, userID, groupID).Order("is_system ASC").First(&category).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+func (r *categoryRepository) FindChildren(parentID uuid.UUID) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Where("parent_id = ?", parentID).Find(&categories).Error
+	return categories, err
+}
+
+func (r *categoryRepository) Update(category *models.Category) error {
+	return r.db.Save(category).Error
+}
+
+// Rename saves the category and rewrites every record of its scope that still
+// uses the old name, so reports keep grouping them together.
+func (r *categoryRepository) Rename(category *models.Category, oldName string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(category).Error; err != nil {
+			return err
+		}
+		return rewriteHistory(tx, category, category.UserID, category.GroupID, []string{oldName})
+	})
+}
+
+// Merge folds the source entries and any free-form names into the target:
+// history of the given scope is rewritten to the target name, children of the
+// sources are moved under the target and the sources are deleted.
+func (r *categoryRepository) Merge(target *models.Category, userID, groupID *uuid.UUID, sources []models.Category, names []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sourceIDs []uuid.UUID
+		for _, source := range sources {
+			sourceIDs = append(sourceIDs, source.ID)
+			names = append(names, source.Name)
+		}
+
+		if err := rewriteHistory(tx, target, userID, groupID, names); err != nil {
+			return err
+		}
+
+		if len(sourceIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.Category{}).Where("parent_id IN ?", sourceIDs).
+			Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&models.Category{}, "id IN ?", sourceIDs).Error
+	})
+}
+
+// Delete removes the entry and moves its children up to its own parent.
+// Records that used the name keep it as plain text.
+func (r *categoryRepository) Delete(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Category{}).Where("parent_id = ?", category.ID).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, "id = ?", category.ID).Error
+	})
+}
+
+// EnsureSystem inserts the system entries that are not present yet. Existing
+// entries are left untouched so edits made in the database survive restarts.
+func (r *categoryRepository) EnsureSystem(categories []models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range categories {
+			var count int64
+			if err := tx.Model(&models.Category{}).
+				Where("is_system = ? AND kind = ? AND LOWER(name) = ?", true, categories[i].Kind, normalizeName(categories[i].Name)).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			categories[i].IsSystem = true
+			if err := tx.Create(&categories[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *categoryRepository) scoped(query *gorm.DB, userID, groupID *uuid.UUID) *gorm.DB {
+	if groupID != nil {
+		return query.Where("(is_system = ? OR group_id = ?)", true, *groupID)
+	}
+	if userID != nil {
+		return query.Where("(is_system = ? OR (user_id = ? AND group_id IS NULL))", true, *userID)
+	}
+	return query.Where("is_system = ?", true)
+}
+
+// rewriteHistory replaces the given names with the category's name in the
+// transactions, line items and planned expenses of a personal (userID) or
+// group (groupID) scope, and in the budgets, rules, recurring expenses,
+// import profiles and shopping lists that would otherwise keep using the old
+// names. Matching ignores case and surrounding spaces.
+func rewriteHistory(tx *gorm.DB, category *models.Category, userID, groupID *uuid.UUID, names []string) error {
+	var matches []string
+	for _, name := range names {
+		if normalized := normalizeName(name); normalized != "" {
+			matches = append(matches, normalized)
+		}
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+
+	ownerType, ownerID := "USER", userID
+	scope := "user_id = ? AND group_id IS NULL"
+	if groupID != nil {
+		ownerType, ownerID = "GROUP", groupID
+		scope = "group_id = ?"
+	}
+	if ownerID == nil {
+		return errors.New("no scope to rewrite history in")
+	}
+
+	if category.Kind == "source" {
+		if err := tx.Exec("UPDATE transactions SET source = ? WHERE owner_type = ? AND owner_id = ? AND LOWER(TRIM(source)) IN ?",
+			category.Name, ownerType, *ownerID, matches).Error; err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE import_profiles SET default_source = ? WHERE "+scope+" AND LOWER(TRIM(default_source)) IN ?",
+			category.Name, *ownerID, matches).Error
+	}
+
+	if err := tx.Exec("UPDATE transactions SET category = ? WHERE owner_type = ? AND owner_id = ? AND LOWER(TRIM(category)) IN ?",
+		category.Name, ownerType, *ownerID, matches).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Exec(`UPDATE transaction_line_items SET category = ?
+		WHERE LOWER(TRIM(category)) IN ?
+		AND transaction_id IN (SELECT id FROM transactions WHERE owner_type = ? AND owner_id = ?)`,
+		category.Name, matches, ownerType, *ownerID).Error; err != nil {
+		return err
+	}
+
+	columns := []struct{ table, column string }{
+		{"planned_expenses", "category"},
+		{"expense_recurrences", "category"},
+		{"categorization_rules", "category"},
+		{"import_profiles", "default_category"},
+	}
+	for _, c := range columns {
+		if err := tx.Exec("UPDATE "+c.table+" SET "+c.column+" = ? WHERE "+scope+" AND LOWER(TRIM("+c.column+")) IN ?",
+			category.Name, *ownerID, matches).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec(`UPDATE shopping_list_items SET category = ?
+		WHERE LOWER(TRIM(category)) IN ?
+		AND list_id IN (SELECT id FROM shopping_lists WHERE `+scope+`)`,
+		category.Name, matches, *ownerID).Error; err != nil {
+		return err
+	}
+
+	return rewriteBudgets(tx, category, scope, *ownerID, matches)
+}
+
+// rewriteBudgets moves the budgets of the matched names onto the category.
+// A budget whose period the category already has a budget for is folded
+// into it, adding up their amounts, since the category now covers the
+// spending of both.
+func rewriteBudgets(tx *gorm.DB, category *models.Category, scope string, ownerID uuid.UUID, matches []string) error {
+	name := normalizeName(category.Name)
+
+	var budgets []models.Budget
+	if err := tx.Where(scope, ownerID).
+		Where("LOWER(TRIM(category)) IN ?", append([]string{name}, matches...)).
+		Order("created_at ASC").
+		Find(&budgets).Error; err != nil {
+		return err
+	}
+
+	kept := make(map[string]*models.Budget)
+	for i := range budgets {
+		if normalizeName(budgets[i].Category) == name {
+			kept[budgets[i].Period] = &budgets[i]
+		}
+	}
+
+	for i := range budgets {
+		budget := &budgets[i]
+		if target, ok := kept[budget.Period]; ok && target != budget {
+			target.Amount += budget.Amount
+			if err := tx.Delete(budget).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(target).Update("amount", target.Amount).Error; err != nil {
+				return err
+			}
+			continue
+		}
+
+		kept[budget.Period] = budget
+		if budget.Category != category.Name {
+			if err := tx.Model(budget).Update("category", category.Name).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository interface {
+	Upsert(rates []models.ExchangeRate) error
+	FindLatest(base, quote string, date time.Time) (*models.ExchangeRate, error)
+	FindByPair(base, quote string, limit int) ([]models.ExchangeRate, error)
+}
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{db: db}
+}
+
+// Upsert stores the rates, replacing any existing rate for the same pair and
+// date so re-importing a corrected file fixes earlier values.
+func (r *exchangeRateRepository) Upsert(rates []models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+// FindLatest returns the most recent rate for the pair effective on or before
+// the date, or nil when there is none.
+func (r *exchangeRateRepository) FindLatest(base, quote string, date time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := r.db.Where("base_currency = ? AND quote_currency = ? AND effective_date <= ?", base, quote, date).
+		Order("effective_date DESC").First(&rate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *exchangeRateRepository) FindByPair(base, quote string, limit int) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+	err := r.db.Where("base_currency = ? AND quote_currency = ?", base, quote).
+		Order("effective_date DESC").Limit(limit).Find(&rates).Error
+	return rates, err
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImportRepository interface {
+	CreateProfile(profile *models.ImportProfile) error
+	FindProfileByID(id uuid.UUID) (*models.ImportProfile, error)
+	FindProfilesByUser(userID uuid.UUID) ([]models.ImportProfile, error)
+	FindProfilesByGroup(groupID uuid.UUID) ([]models.ImportProfile, error)
+	DeleteProfile(id uuid.UUID) error
+	CreateBatch(batch *models.ImportBatch) error
+	FindBatchByID(id uuid.UUID) (*models.ImportBatch, error)
+	UpdateBatch(batch *models.ImportBatch) error
+	ClaimBatch(id uuid.UUID, staleBefore time.Time) (bool, error)
+	UpdateRow(row *models.ImportRow) error
+}
+
+type importRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepository(db *gorm.DB) ImportRepository {
+	return &importRepository{db: db}
+}
+
+func (r *importRepository) CreateProfile(profile *models.ImportProfile) error {
+	return r.db.Create(profile).Error
+}
+
+func (r *importRepository) FindProfileByID(id uuid.UUID) (*models.ImportProfile, error) {
+	var profile models.ImportProfile
+	err := r.db.Where("id = ?", id).First(&profile).Error
+	return &profile, err
+}
+
+func (r *importRepository) FindProfilesByUser(userID uuid.UUID) ([]models.ImportProfile, error) {
+	var profiles []models.ImportProfile
+	err := r.db.Where("user_id = ? AND group_id IS NULL", userID).Order("name ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *importRepository) FindProfilesByGroup(groupID uuid.UUID) ([]models.ImportProfile, error) {
+	var profiles []models.ImportProfile
+	err := r.db.Where("group_id = ?", groupID).Order("name ASC").Find(&profiles).Error
+	return profiles, err
+}
+
+func (r *importRepository) DeleteProfile(id uuid.UUID) error {
+	return r.db.Delete(&models.ImportProfile{}, "id = ?", id).Error
+}
+
+// CreateBatch stores the batch together with its rows.
+func (r *importRepository) CreateBatch(batch *models.ImportBatch) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		rows := batch.Rows
+		batch.Rows = nil
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+
+		for i := range rows {
+			rows[i].BatchID = batch.ID
+		}
+		if len(rows) > 0 {
+			if err := tx.CreateInBatches(rows, 500).Error; err != nil {
+				return err
+			}
+		}
+		batch.Rows = rows
+		return nil
+	})
+}
+
+func (r *importRepository) FindBatchByID(id uuid.UUID) (*models.ImportBatch, error) {
+	var batch models.ImportBatch
+	err := r.db.Preload("Rows", func(db *gorm.DB) *gorm.DB {
+		return db.Order("row_number ASC")
+	}).Where("id = ?", id).First(&batch).Error
+	return &batch, err
+}
+
+func (r *importRepository) UpdateBatch(batch *models.ImportBatch) error {
+	return r.db.Omit("Rows").Save(batch).Error
+}
+
+// ClaimBatch marks the batch as being committed unless another commit
+// already holds it, reporting whether this call claimed it. A claim last
+// touched before staleBefore was left by a commit that died and is taken over.
+func (r *importRepository) ClaimBatch(id uuid.UUID, staleBefore time.Time) (bool, error) {
+	result := r.db.Model(&models.ImportBatch{}).
+		Where("id = ? AND (status IN ? OR (status = 'committing' AND updated_at < ?))",
+			id, []string{"pending", "completed"}, staleBefore).
+		Update("status", "committing")
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *importRepository) UpdateRow(row *models.ImportRow) error {
+	return r.db.Save(row).Error
+}
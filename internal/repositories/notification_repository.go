@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	CreateBatch(notifications []models.Notification) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateBatch(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
@@ -0,0 +1,63 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cursor marks the last row of a page for keyset pagination. Rows are ordered
+// by (timestamp, id) descending, so the next page starts strictly below it.
+type Cursor struct {
+	Timestamp time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque string handed out to API clients as next_cursor.
+func (c Cursor) Encode() string {
+	raw := c.Timestamp.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses a cursor produced by Encode. An empty string means
+// "start from the newest row" and yields a nil cursor.
+func DecodeCursor(value string) (*Cursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	return &Cursor{Timestamp: timestamp, ID: id}, nil
+}
+
+// applyCursor restricts the query to rows after the cursor and orders it so
+// the (timestamp, id) pair can be served from an index without OFFSET.
+func applyCursor(query *gorm.DB, timeColumn, idColumn string, cursor *Cursor) *gorm.DB {
+	if cursor != nil {
+		query = query.Where("("+timeColumn+", "+idColumn+") < (?, ?)", cursor.Timestamp, cursor.ID)
+	}
+	return query.Order(timeColumn + " DESC").Order(idColumn + " DESC")
+}
@@ -11,13 +11,25 @@ import (
 type PlannedExpenseRepository interface {
 	Create(expense *models.PlannedExpense) error
 	FindByID(id uuid.UUID) (*models.PlannedExpense, error)
-	FindByUser(userID uuid.UUID, status string, page, limit int) ([]models.PlannedExpense, int64, error)
-	FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.PlannedExpense, int64, error)
-	Update(expense *models.PlannedExpense) error
+	FindByUser(userID uuid.UUID, filter PlannedExpenseFilter, page, limit int) ([]models.PlannedExpense, int64, error)
+	FindByGroup(groupID uuid.UUID, filter PlannedExpenseFilter, page, limit int) ([]models.PlannedExpense, int64, error)
+	ReplaceTags(expense *models.PlannedExpense, tags []models.Tag) error
+	Update(id uuid.UUID, status string, paidAmount int64, fields map[string]interface{}) (bool, error)
 	Delete(id uuid.UUID) error
-	MarkAsBought(id uuid.UUID, actualPrice int64, paidBy uuid.UUID) error
-	MarkAsCancelled(id uuid.UUID) error
-	FindOverdue(days int) ([]models.PlannedExpense, error)
+	MarkAsCancelled(id uuid.UUID) (bool, error)
+	FindOverdueForUser(userID uuid.UUID, now time.Time) ([]models.PlannedExpense, error)
+	FindDueBetween(from, to time.Time) ([]models.PlannedExpense, error)
+	FindOpenByUser(userID uuid.UUID) ([]models.PlannedExpense, error)
+	FindOpenByGroup(groupID uuid.UUID) ([]models.PlannedExpense, error)
+	FindRecurrence(id uuid.UUID) (*models.ExpenseRecurrence, error)
+	FindDueRecurrences(now time.Time) ([]models.ExpenseRecurrence, error)
+	FindPlannedOccurrences(recurrenceID uuid.UUID) ([]models.PlannedExpense, error)
+}
+
+// PlannedExpenseFilter narrows planned expense listings. Zero values mean "no filter".
+type PlannedExpenseFilter struct {
+	Status string
+	TagID  *uuid.UUID
 }
 
 type plannedExpenseRepository struct {
@@ -34,21 +46,27 @@ func (r *plannedExpenseRepository) Create(expense *models.PlannedExpense) error
 
 func (r *plannedExpenseRepository) FindByID(id uuid.UUID) (*models.PlannedExpense, error) {
 	var expense models.PlannedExpense
-	err := r.db.Preload("User").Preload("Group").Preload("Payer").
+	err := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("Tags").Preload("Recurrence").
+		Preload("Payments", orderPayments).Preload("Payments.Payer").
 		Where("id = ?", id).First(&expense).Error
 	return &expense, err
 }
 
-func (r *plannedExpenseRepository) FindByUser(userID uuid.UUID, status string, page, limit int) ([]models.PlannedExpense, int64, error) {
+func (r *plannedExpenseRepository) FindByUser(userID uuid.UUID, filter PlannedExpenseFilter, page, limit int) ([]models.PlannedExpense, int64, error) {
 	var expenses []models.PlannedExpense
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("User").Preload("Group").Preload("Payer").
+	query := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("Tags").Preload("Recurrence").
+		Preload("Payments", orderPayments).Preload("Payments.Payer").
 		Where("user_id = ?", userID)
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.TagID != nil {
+		query = query.Where("planned_expenses.id IN (SELECT planned_expense_id FROM planned_expense_tags WHERE tag_id = ?)", *filter.TagID)
 	}
 
 	query = query.Order("created_at DESC")
@@ -62,16 +80,21 @@ func (r *plannedExpenseRepository) FindByUser(userID uuid.UUID, status string, p
 	return expenses, total, err
 }
 
-func (r *plannedExpenseRepository) FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.PlannedExpense, int64, error) {
+func (r *plannedExpenseRepository) FindByGroup(groupID uuid.UUID, filter PlannedExpenseFilter, page, limit int) ([]models.PlannedExpense, int64, error) {
 	var expenses []models.PlannedExpense
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("User").Preload("Group").Preload("Payer").
+	query := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("Tags").Preload("Recurrence").
+		Preload("Payments", orderPayments).Preload("Payments.Payer").
 		Where("group_id = ?", groupID)
 
-	if status != "" {
-		query = query.Where("status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if filter.TagID != nil {
+		query = query.Where("planned_expenses.id IN (SELECT planned_expense_id FROM planned_expense_tags WHERE tag_id = ?)", *filter.TagID)
 	}
 
 	query = query.Order("created_at DESC")
@@ -85,43 +108,118 @@ func (r *plannedExpenseRepository) FindByGroup(groupID uuid.UUID, status string,
 	return expenses, total, err
 }
 
-func (r *plannedExpenseRepository) Update(expense *models.PlannedExpense) error {
-	return r.db.Save(expense).Error
+func (r *plannedExpenseRepository) ReplaceTags(expense *models.PlannedExpense, tags []models.Tag) error {
+	return r.db.Model(expense).Association("Tags").Replace(tags)
+}
+
+// Update writes only the given columns, and only while the expense still has
+// the status and paid amount it was read with, so an edit cannot undo a
+// payment recorded in the meantime. It reports false when it lost that race.
+func (r *plannedExpenseRepository) Update(id uuid.UUID, status string, paidAmount int64, fields map[string]interface{}) (bool, error) {
+	fields["updated_at"] = time.Now()
+	result := r.db.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status = ? AND paid_amount = ?", id, status, paidAmount).
+		Updates(fields)
+	return result.RowsAffected > 0, result.Error
 }
 
 func (r *plannedExpenseRepository) Delete(id uuid.UUID) error {
 	return r.db.Delete(&models.PlannedExpense{}, "id = ?", id).Error
 }
 
-func (r *plannedExpenseRepository) MarkAsBought(id uuid.UUID, actualPrice int64, paidBy uuid.UUID) error {
-	now := time.Now()
-	return r.db.Model(&models.PlannedExpense{}).
-		Where("id = ?", id).
-		Updates(map[string]interface{}{
-			"status":       "bought",
-			"actual_price": actualPrice,
-			"paid_by":      paidBy,
-			"paid_at":      now,
-			"updated_at":   now,
-		}).Error
-}
+// cancellableStatuses are the statuses of expenses nothing was paid on yet.
+var cancellableStatuses = []string{"planned", "pending_approval", "proposed"}
 
-func (r *plannedExpenseRepository) MarkAsCancelled(id uuid.UUID) error {
-	return r.db.Model(&models.PlannedExpense{}).
-		Where("id = ?", id).
+// MarkAsCancelled cancels the expense and reports false when its status no
+// longer allows it.
+func (r *plannedExpenseRepository) MarkAsCancelled(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status IN ?", id, cancellableStatuses).
 		Updates(map[string]interface{}{
 			"status":     "cancelled",
 			"updated_at": time.Now(),
-		}).Error
+		})
+	return result.RowsAffected > 0, result.Error
 }
 
-func (r *plannedExpenseRepository) FindOverdue(days int) ([]models.PlannedExpense, error) {
+// unpaidStatuses are the statuses of expenses still waiting to be paid.
+var unpaidStatuses = []string{"planned", "partially_paid"}
+
+// openStatuses add the expenses still waiting on a decision to the unpaid ones.
+var openStatuses = []string{"planned", "partially_paid", "pending_approval", "proposed"}
+
+// FindOverdueForUser returns the unpaid expenses past their due date that the
+// user is responsible for: their personal ones and those of the groups they
+// are an active member of.
+func (r *plannedExpenseRepository) FindOverdueForUser(userID uuid.UUID, now time.Time) ([]models.PlannedExpense, error) {
+	var expenses []models.PlannedExpense
+	groupIDs := r.db.Model(&models.UserGroup{}).Select("group_id").
+		Where("user_id = ? AND status = 'active'", userID)
+
+	err := r.db.Preload("User").Preload("Group").Preload("Tags").Preload("Recurrence").
+		Preload("Payments", orderPayments).Preload("Payments.Payer").
+		Where("status IN ? AND due_date < ?", unpaidStatuses, now).
+		Where("(group_id IS NULL AND user_id = ?) OR group_id IN (?)", userID, groupIDs).
+		Order("due_date ASC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+// FindDueBetween returns the unpaid expenses due in [from, to), across all
+// users and groups.
+func (r *plannedExpenseRepository) FindDueBetween(from, to time.Time) ([]models.PlannedExpense, error) {
 	var expenses []models.PlannedExpense
-	cutoffDate := time.Now().AddDate(0, 0, -days)
-	
 	err := r.db.Preload("User").Preload("Group").
-		Where("status = 'planned' AND due_date < ?", cutoffDate).
+		Where("status IN ? AND due_date >= ? AND due_date < ?", unpaidStatuses, from, to).
+		Order("due_date ASC").
 		Find(&expenses).Error
-	
 	return expenses, err
-}
\ No newline at end of file
+}
+
+// FindOpenByUser returns the user's personal expenses that are still open,
+// in due date order with undated ones last.
+func (r *plannedExpenseRepository) FindOpenByUser(userID uuid.UUID) ([]models.PlannedExpense, error) {
+	var expenses []models.PlannedExpense
+	err := r.db.Where("user_id = ? AND group_id IS NULL AND status IN ?", userID, openStatuses).
+		Order("due_date ASC NULLS LAST").Order("created_at ASC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+// FindOpenByGroup returns the group's expenses that are still open, in due
+// date order with undated ones last.
+func (r *plannedExpenseRepository) FindOpenByGroup(groupID uuid.UUID) ([]models.PlannedExpense, error) {
+	var expenses []models.PlannedExpense
+	err := r.db.Where("group_id = ? AND status IN ?", groupID, openStatuses).
+		Order("due_date ASC NULLS LAST").Order("created_at ASC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+func (r *plannedExpenseRepository) FindRecurrence(id uuid.UUID) (*models.ExpenseRecurrence, error) {
+	var recurrence models.ExpenseRecurrence
+	err := r.db.Preload("Tags").Where("id = ?", id).First(&recurrence).Error
+	return &recurrence, err
+}
+
+// FindDueRecurrences returns the active series whose next occurrence is due
+// at or before now.
+func (r *plannedExpenseRepository) FindDueRecurrences(now time.Time) ([]models.ExpenseRecurrence, error) {
+	var recurrences []models.ExpenseRecurrence
+	err := r.db.Preload("Tags").Where("is_active = ? AND next_due_date <= ?", true, now).
+		Order("next_due_date ASC").
+		Find(&recurrences).Error
+	return recurrences, err
+}
+
+func (r *plannedExpenseRepository) FindPlannedOccurrences(recurrenceID uuid.UUID) ([]models.PlannedExpense, error) {
+	var expenses []models.PlannedExpense
+	err := r.db.Where("recurrence_id = ? AND status = 'planned'", recurrenceID).
+		Order("due_date ASC").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+func orderPayments(db *gorm.DB) *gorm.DB {
+	return db.Order("paid_at ASC")
+}
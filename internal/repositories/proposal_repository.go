@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ProposalRepository interface {
+	Create(proposal *models.ExpenseProposal) error
+	FindByID(id uuid.UUID) (*models.ExpenseProposal, error)
+	FindLatestByExpense(expenseID uuid.UUID) (*models.ExpenseProposal, error)
+	FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.ExpenseProposal, int64, error)
+	FindDue(now time.Time) ([]models.ExpenseProposal, error)
+}
+
+type proposalRepository struct {
+	db *gorm.DB
+}
+
+func NewProposalRepository(db *gorm.DB) ProposalRepository {
+	return &proposalRepository{db: db}
+}
+
+func (r *proposalRepository) Create(proposal *models.ExpenseProposal) error {
+	return r.db.Create(proposal).Error
+}
+
+func (r *proposalRepository) FindByID(id uuid.UUID) (*models.ExpenseProposal, error) {
+	var proposal models.ExpenseProposal
+	err := r.db.Preload("PlannedExpense").Preload("Proposer").Preload("Votes.User").
+		Where("id = ?", id).First(&proposal).Error
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// FindLatestByExpense returns the expense's most recent proposal, or nil when
+// it was never put to a vote.
+func (r *proposalRepository) FindLatestByExpense(expenseID uuid.UUID) (*models.ExpenseProposal, error) {
+	var proposal models.ExpenseProposal
+	err := r.db.Preload("PlannedExpense").Preload("Proposer").Preload("Votes.User").
+		Where("planned_expense_id = ?", expenseID).Order("created_at DESC").First(&proposal).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+func (r *proposalRepository) FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.ExpenseProposal, int64, error) {
+	var proposals []models.ExpenseProposal
+	var total int64
+
+	query := r.db.Model(&models.ExpenseProposal{}).Where("group_id = ?", groupID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("PlannedExpense").Preload("Proposer").Preload("Votes.User").
+		Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&proposals).Error
+	return proposals, total, err
+}
+
+// FindDue returns the open proposals whose deadline has passed.
+func (r *proposalRepository) FindDue(now time.Time) ([]models.ExpenseProposal, error) {
+	var proposals []models.ExpenseProposal
+	err := r.db.Preload("PlannedExpense").Preload("Votes").
+		Where("status = 'open' AND deadline <= ?", now).Find(&proposals).Error
+	return proposals, err
+}
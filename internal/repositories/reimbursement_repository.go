@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReimbursementRepository interface {
+	FindByID(id uuid.UUID) (*models.Reimbursement, error)
+	FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.Reimbursement, int64, error)
+	FindByExpense(expenseID uuid.UUID) ([]models.Reimbursement, error)
+	FindByGroupAndStatus(groupID uuid.UUID, status string) ([]models.Reimbursement, error)
+	SumOutstanding(groupID uuid.UUID) (int64, error)
+}
+
+type reimbursementRepository struct {
+	db *gorm.DB
+}
+
+func NewReimbursementRepository(db *gorm.DB) ReimbursementRepository {
+	return &reimbursementRepository{db: db}
+}
+
+func (r *reimbursementRepository) FindByID(id uuid.UUID) (*models.Reimbursement, error) {
+	var reimbursement models.Reimbursement
+	err := r.db.Preload("User").Preload("PlannedExpense").Preload("Payment").
+		Where("id = ?", id).First(&reimbursement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reimbursement, nil
+}
+
+func (r *reimbursementRepository) FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.Reimbursement, int64, error) {
+	var reimbursements []models.Reimbursement
+	var total int64
+
+	query := r.db.Model(&models.Reimbursement{}).Where("group_id = ?", groupID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("PlannedExpense").Preload("Payment").
+		Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&reimbursements).Error
+	return reimbursements, total, err
+}
+
+func (r *reimbursementRepository) FindByExpense(expenseID uuid.UUID) ([]models.Reimbursement, error) {
+	var reimbursements []models.Reimbursement
+	err := r.db.Where("planned_expense_id = ?", expenseID).Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+func (r *reimbursementRepository) FindByGroupAndStatus(groupID uuid.UUID, status string) ([]models.Reimbursement, error) {
+	var reimbursements []models.Reimbursement
+	err := r.db.Preload("User").Where("group_id = ? AND status = ?", groupID, status).Find(&reimbursements).Error
+	return reimbursements, err
+}
+
+// SumOutstanding returns what the group still owes its members.
+func (r *reimbursementRepository) SumOutstanding(groupID uuid.UUID) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Reimbursement{}).
+		Where("group_id = ? AND status = 'outstanding'", groupID).
+		Select("COALESCE(SUM(amount), 0)").Scan(&total).Error
+	return total, err
+}
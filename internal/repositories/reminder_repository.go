@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository interface {
+	FindByExpenses(expenseIDs []uuid.UUID) ([]models.ExpenseReminder, error)
+	Record(reminder *models.ExpenseReminder, notifications []models.Notification) (bool, error)
+}
+
+type reminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) FindByExpenses(expenseIDs []uuid.UUID) ([]models.ExpenseReminder, error) {
+	var reminders []models.ExpenseReminder
+	if len(expenseIDs) == 0 {
+		return reminders, nil
+	}
+	err := r.db.Where("planned_expense_id IN ?", expenseIDs).Find(&reminders).Error
+	return reminders, err
+}
+
+// Record stores the reminder with its notifications unless it was already
+// sent, reporting whether this call recorded it. Concurrent schedulers
+// therefore never send the same reminder twice, and a reminder is only marked
+// sent once its notifications exist.
+func (r *reminderRepository) Record(reminder *models.ExpenseReminder, notifications []models.Notification) (bool, error) {
+	recorded := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reminder)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		recorded = true
+
+		if len(notifications) == 0 {
+			return nil
+		}
+		return tx.Create(&notifications).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RuleRepository interface {
+	Create(rule *models.CategorizationRule) error
+	FindByID(id uuid.UUID) (*models.CategorizationRule, error)
+	FindByUser(userID uuid.UUID) ([]models.CategorizationRule, error)
+	FindByGroup(groupID uuid.UUID) ([]models.CategorizationRule, error)
+	FindActive(userID uuid.UUID, groupID *uuid.UUID) ([]models.CategorizationRule, error)
+	Update(rule *models.CategorizationRule) error
+	Delete(id uuid.UUID) error
+}
+
+type ruleRepository struct {
+	db *gorm.DB
+}
+
+func NewRuleRepository(db *gorm.DB) RuleRepository {
+	return &ruleRepository{db: db}
+}
+
+func (r *ruleRepository) Create(rule *models.CategorizationRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *ruleRepository) FindByID(id uuid.UUID) (*models.CategorizationRule, error) {
+	var rule models.CategorizationRule
+	err := r.db.Preload("Tag").Where("id = ?", id).First(&rule).Error
+	return &rule, err
+}
+
+func (r *ruleRepository) FindByUser(userID uuid.UUID) ([]models.CategorizationRule, error) {
+	var rules []models.CategorizationRule
+	err := r.db.Preload("Tag").Where("user_id = ? AND group_id IS NULL", userID).
+		Order("priority ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *ruleRepository) FindByGroup(groupID uuid.UUID) ([]models.CategorizationRule, error) {
+	var rules []models.CategorizationRule
+	err := r.db.Preload("Tag").Where("group_id = ?", groupID).
+		Order("priority ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+// FindActive returns the active rules of a group, or of the user's personal
+// transactions when groupID is nil, in evaluation order.
+func (r *ruleRepository) FindActive(userID uuid.UUID, groupID *uuid.UUID) ([]models.CategorizationRule, error) {
+	var rules []models.CategorizationRule
+	query := r.db.Preload("Tag").Where("is_active = ?", true)
+	if groupID != nil {
+		query = query.Where("group_id = ?", *groupID)
+	} else {
+		query = query.Where("user_id = ? AND group_id IS NULL", userID)
+	}
+
+	err := query.Order("priority ASC, created_at ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *ruleRepository) Update(rule *models.CategorizationRule) error {
+	return r.db.Omit("Tag").Save(rule).Error
+}
+
+func (r *ruleRepository) Delete(id uuid.UUID) error {
+	return r.db.Delete(&models.CategorizationRule{}, "id = ?", id).Error
+}
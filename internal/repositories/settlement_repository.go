@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SettlementRepository interface {
+	FindByID(id uuid.UUID) (*models.Settlement, error)
+	FindByGroup(groupID uuid.UUID, status string, memberID *uuid.UUID, page, limit int) ([]models.Settlement, int64, error)
+	FindConfirmedByGroup(groupID uuid.UUID) ([]models.Settlement, error)
+}
+
+type settlementRepository struct {
+	db *gorm.DB
+}
+
+func NewSettlementRepository(db *gorm.DB) SettlementRepository {
+	return &settlementRepository{db: db}
+}
+
+func (r *settlementRepository) FindByID(id uuid.UUID) (*models.Settlement, error) {
+	var settlement models.Settlement
+	err := r.db.Preload("FromUser").Preload("ToUser").Where("id = ?", id).First(&settlement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &settlement, nil
+}
+
+// FindByGroup lists the group's settlements, optionally only those a member
+// paid or received.
+func (r *settlementRepository) FindByGroup(groupID uuid.UUID, status string, memberID *uuid.UUID, page, limit int) ([]models.Settlement, int64, error) {
+	var settlements []models.Settlement
+	var total int64
+
+	query := r.db.Model(&models.Settlement{}).Where("group_id = ?", groupID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if memberID != nil {
+		query = query.Where("from_user_id = ? OR to_user_id = ?", *memberID, *memberID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("FromUser").Preload("ToUser").
+		Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&settlements).Error
+	return settlements, total, err
+}
+
+func (r *settlementRepository) FindConfirmedByGroup(groupID uuid.UUID) ([]models.Settlement, error) {
+	var settlements []models.Settlement
+	err := r.db.Where("group_id = ? AND status = 'confirmed'", groupID).Find(&settlements).Error
+	return settlements, err
+}
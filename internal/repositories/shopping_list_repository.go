@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ShoppingListRepository interface {
+	Create(list *models.ShoppingList) error
+	FindByID(id uuid.UUID) (*models.ShoppingList, error)
+	FindByUser(userID uuid.UUID, status string, page, limit int) ([]models.ShoppingList, int64, error)
+	FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.ShoppingList, int64, error)
+	Update(list *models.ShoppingList) error
+	Delete(id uuid.UUID) error
+	CreateItem(item *models.ShoppingListItem) error
+	FindItemByID(id uuid.UUID) (*models.ShoppingListItem, error)
+	UpdateItem(item *models.ShoppingListItem) error
+	DeleteItem(id uuid.UUID) error
+}
+
+type shoppingListRepository struct {
+	db *gorm.DB
+}
+
+func NewShoppingListRepository(db *gorm.DB) ShoppingListRepository {
+	return &shoppingListRepository{db: db}
+}
+
+func (r *shoppingListRepository) Create(list *models.ShoppingList) error {
+	return r.db.Create(list).Error
+}
+
+func (r *shoppingListRepository) FindByID(id uuid.UUID) (*models.ShoppingList, error) {
+	var list models.ShoppingList
+	err := r.db.Preload("User").Preload("Group").Preload("Items", orderItems).
+		Where("id = ?", id).First(&list).Error
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindByUser lists the user's personal shopping lists.
+func (r *shoppingListRepository) FindByUser(userID uuid.UUID, status string, page, limit int) ([]models.ShoppingList, int64, error) {
+	return r.findLists(r.db.Where("user_id = ? AND group_id IS NULL", userID), status, page, limit)
+}
+
+func (r *shoppingListRepository) FindByGroup(groupID uuid.UUID, status string, page, limit int) ([]models.ShoppingList, int64, error) {
+	return r.findLists(r.db.Where("group_id = ?", groupID), status, page, limit)
+}
+
+func (r *shoppingListRepository) findLists(query *gorm.DB, status string, page, limit int) ([]models.ShoppingList, int64, error) {
+	var lists []models.ShoppingList
+	var total int64
+
+	query = query.Model(&models.ShoppingList{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Preload("Group").Preload("Items", orderItems).
+		Order("created_at DESC").Offset((page - 1) * limit).Limit(limit).Find(&lists).Error
+	return lists, total, err
+}
+
+func (r *shoppingListRepository) Update(list *models.ShoppingList) error {
+	return r.db.Omit("User", "Group", "Items").Save(list).Error
+}
+
+func (r *shoppingListRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("list_id = ?", id).Delete(&models.ShoppingListItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ShoppingList{}, "id = ?", id).Error
+	})
+}
+
+func (r *shoppingListRepository) CreateItem(item *models.ShoppingListItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *shoppingListRepository) FindItemByID(id uuid.UUID) (*models.ShoppingListItem, error) {
+	var item models.ShoppingListItem
+	err := r.db.Where("id = ?", id).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *shoppingListRepository) UpdateItem(item *models.ShoppingListItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *shoppingListRepository) DeleteItem(id uuid.UUID) error {
+	return r.db.Delete(&models.ShoppingListItem{}, "id = ?", id).Error
+}
+
+// orderItems keeps list items in the order they were added.
+func orderItems(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC")
+}
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SplitRepository interface {
+	FindByExpense(expenseID uuid.UUID) (*models.ExpenseSplit, error)
+	FindByGroup(groupID uuid.UUID) ([]models.ExpenseSplit, error)
+}
+
+type splitRepository struct {
+	db *gorm.DB
+}
+
+func NewSplitRepository(db *gorm.DB) SplitRepository {
+	return &splitRepository{db: db}
+}
+
+// FindByExpense returns the expense's split, or nil when it has none.
+func (r *splitRepository) FindByExpense(expenseID uuid.UUID) (*models.ExpenseSplit, error) {
+	var split models.ExpenseSplit
+	err := r.db.Preload("PlannedExpense").Preload("Shares.User").
+		Where("planned_expense_id = ?", expenseID).First(&split).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &split, nil
+}
+
+func (r *splitRepository) FindByGroup(groupID uuid.UUID) ([]models.ExpenseSplit, error) {
+	var splits []models.ExpenseSplit
+	err := r.db.Preload("Shares").Where("group_id = ?", groupID).Find(&splits).Error
+	return splits, err
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"balanca/internal/models"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type TagRepository interface {
+	Create(tag *models.Tag) error
+	FindByID(id uuid.UUID) (*models.Tag, error)
+	FindByIDs(ids []uuid.UUID) ([]models.Tag, error)
+	FindByUser(userID uuid.UUID) ([]models.Tag, error)
+	FindByGroup(groupID uuid.UUID) ([]models.Tag, error)
+	FindByName(userID, groupID *uuid.UUID, name string) (*models.Tag, error)
+	Update(tag *models.Tag) error
+	Delete(id uuid.UUID) error
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *tagRepository) FindByID(id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	return &tag, err
+}
+
+func (r *tagRepository) FindByIDs(ids []uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByUser(userID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Where("user_id = ?", userID).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) FindByGroup(groupID uuid.UUID) ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.db.Where("group_id = ?", groupID).Order("name ASC").Find(&tags).Error
+	return tags, err
+}
+
+// FindByName looks a tag up case-insensitively within a personal or group
+// scope. It returns nil when no tag matches.
+func (r *tagRepository) FindByName(userID, groupID *uuid.UUID, name string) (*models.Tag, error) {
+	var tag models.Tag
+	query := r.db.Where("LOWER(name) = ?", strings.ToLower(strings.TrimSpace(name)))
+	if groupID != nil {
+		query = query.Where("group_id = ?", *groupID)
+	} else {
+		query = query.Where("user_id = ? AND group_id IS NULL", userID)
+	}
+
+	err := query.First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *tagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+func (r *tagRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM transaction_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM planned_expense_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE categorization_rules SET tag_id = NULL WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, "id = ?", id).Error
+	})
+}
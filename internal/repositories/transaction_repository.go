@@ -11,17 +11,28 @@ import (
 type TransactionRepository interface {
 	Create(transaction *models.Transaction) error
 	FindByID(id uuid.UUID) (*models.Transaction, error)
-	FindByOwner(ownerType string, ownerID uuid.UUID, page, limit int) ([]models.Transaction, int64, error)
-	FindByUser(userID uuid.UUID, page, limit int) ([]models.Transaction, int64, error)
-	FindByGroup(groupID uuid.UUID, page, limit int) ([]models.Transaction, int64, error)
+	FindByOwner(ownerType string, ownerID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error)
+	FindByUser(userID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error)
+	FindByGroup(groupID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error)
+	FindByUserAfter(userID uuid.UUID, filter TransactionFilter, cursor *Cursor, limit int) ([]models.Transaction, *Cursor, error)
+	FindByGroupAfter(groupID uuid.UUID, filter TransactionFilter, cursor *Cursor, limit int) ([]models.Transaction, *Cursor, error)
 	FindByDateRange(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error)
+	FindByImportFingerprints(ownerType string, ownerID uuid.UUID, fingerprints []string) ([]models.Transaction, error)
+	FindDebitAmounts(ownerType string, ownerID uuid.UUID, category string, startDate, endDate time.Time) ([]int64, error)
 	GetBalance(ownerType string, ownerID uuid.UUID) (int64, error)
 	GetMonthlySummary(ownerType string, ownerID uuid.UUID, year int, month int) (*models.Transaction, error)
 	GetCategorySummary(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) (map[string]int64, error)
 	GetSourceSummary(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) (map[string]int64, error)
+	GetTagSummary(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) (map[string]int64, error)
+	ReplaceTags(transaction *models.Transaction, tags []models.Tag) error
 	GetDB() *gorm.DB
 }
 
+// TransactionFilter narrows transaction listings. Zero values mean "no filter".
+type TransactionFilter struct {
+	TagID *uuid.UUID
+}
+
 type transactionRepository struct {
 	db *gorm.DB
 }
@@ -40,19 +51,20 @@ func (r *transactionRepository) GetDB() *gorm.DB {
 
 func (r *transactionRepository) FindByID(id uuid.UUID) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("User").Preload("Group").Preload("Payer").
+	err := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags").
 		Where("id = ?", id).First(&transaction).Error
 	return &transaction, err
 }
 
-func (r *transactionRepository) FindByOwner(ownerType string, ownerID uuid.UUID, page, limit int) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) FindByOwner(ownerType string, ownerID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("User").Preload("Group").Preload("Payer").
+	query := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags").
 		Where("owner_type = ? AND owner_id = ?", ownerType, ownerID).
 		Order("created_at DESC")
+	query = applyTransactionFilter(query, filter)
 
 	err := query.Model(&models.Transaction{}).Count(&total).Error
 	if err != nil {
@@ -63,14 +75,15 @@ func (r *transactionRepository) FindByOwner(ownerType string, ownerID uuid.UUID,
 	return transactions, total, err
 }
 
-func (r *transactionRepository) FindByUser(userID uuid.UUID, page, limit int) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) FindByUser(userID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("User").Preload("Group").Preload("Payer").
+	query := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags").
 		Where("user_id = ?", userID).
 		Order("created_at DESC")
+	query = applyTransactionFilter(query, filter)
 
 	err := query.Model(&models.Transaction{}).Count(&total).Error
 	if err != nil {
@@ -81,14 +94,15 @@ func (r *transactionRepository) FindByUser(userID uuid.UUID, page, limit int) ([
 	return transactions, total, err
 }
 
-func (r *transactionRepository) FindByGroup(groupID uuid.UUID, page, limit int) ([]models.Transaction, int64, error) {
+func (r *transactionRepository) FindByGroup(groupID uuid.UUID, filter TransactionFilter, page, limit int) ([]models.Transaction, int64, error) {
 	var transactions []models.Transaction
 	var total int64
 
 	offset := (page - 1) * limit
-	query := r.db.Preload("User").Preload("Group").Preload("Payer").
+	query := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags").
 		Where("group_id = ?", groupID).
 		Order("created_at DESC")
+	query = applyTransactionFilter(query, filter)
 
 	err := query.Model(&models.Transaction{}).Count(&total).Error
 	if err != nil {
@@ -99,9 +113,41 @@ func (r *transactionRepository) FindByGroup(groupID uuid.UUID, page, limit int)
 	return transactions, total, err
 }
 
+func (r *transactionRepository) FindByUserAfter(userID uuid.UUID, filter TransactionFilter, cursor *Cursor, limit int) ([]models.Transaction, *Cursor, error) {
+	query := r.db.Where("user_id = ?", userID)
+	return r.findAfter(applyTransactionFilter(query, filter), cursor, limit)
+}
+
+func (r *transactionRepository) FindByGroupAfter(groupID uuid.UUID, filter TransactionFilter, cursor *Cursor, limit int) ([]models.Transaction, *Cursor, error) {
+	query := r.db.Where("group_id = ?", groupID)
+	return r.findAfter(applyTransactionFilter(query, filter), cursor, limit)
+}
+
+// findAfter loads one keyset page. It fetches a single extra row to learn
+// whether another page exists instead of running a COUNT.
+func (r *transactionRepository) findAfter(query *gorm.DB, cursor *Cursor, limit int) ([]models.Transaction, *Cursor, error) {
+	var transactions []models.Transaction
+
+	query = applyCursor(query.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags"),
+		"transactions.created_at", "transactions.id", cursor)
+
+	if err := query.Limit(limit + 1).Find(&transactions).Error; err != nil {
+		return nil, nil, err
+	}
+
+	var next *Cursor
+	if len(transactions) > limit {
+		transactions = transactions[:limit]
+		last := transactions[limit-1]
+		next = &Cursor{Timestamp: last.CreatedAt, ID: last.ID}
+	}
+
+	return transactions, next, nil
+}
+
 func (r *transactionRepository) FindByDateRange(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	err := r.db.Preload("User").Preload("Group").Preload("Payer").
+	err := r.db.Preload("User").Preload("Group").Preload("Payer").Preload("LineItems").Preload("Tags").
 		Where("owner_type = ? AND owner_id = ? AND created_at BETWEEN ? AND ?", 
 			ownerType, ownerID, startDate, endDate).
 		Order("created_at ASC").
@@ -109,6 +155,30 @@ func (r *transactionRepository) FindByDateRange(ownerType string, ownerID uuid.U
 	return transactions, err
 }
 
+// FindDebitAmounts returns the amounts of the owner's DEBITs in a category
+// created in [startDate, endDate).
+func (r *transactionRepository) FindDebitAmounts(ownerType string, ownerID uuid.UUID, category string, startDate, endDate time.Time) ([]int64, error) {
+	var amounts []int64
+	err := r.db.Model(&models.Transaction{}).
+		Where("owner_type = ? AND owner_id = ? AND type = 'DEBIT' AND category = ? AND created_at >= ? AND created_at < ?",
+			ownerType, ownerID, category, startDate, endDate).
+		Pluck("amount", &amounts).Error
+	return amounts, err
+}
+
+// FindByImportFingerprints returns the transactions previously imported from
+// statement lines with the given fingerprints.
+func (r *transactionRepository) FindByImportFingerprints(ownerType string, ownerID uuid.UUID, fingerprints []string) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	if len(fingerprints) == 0 {
+		return transactions, nil
+	}
+	err := r.db.Where("owner_type = ? AND owner_id = ? AND metadata->>'import_fingerprint' IN ?",
+		ownerType, ownerID, fingerprints).
+		Find(&transactions).Error
+	return transactions, err
+}
+
 func (r *transactionRepository) GetBalance(ownerType string, ownerID uuid.UUID) (int64, error) {
 	var balance struct {
 		Total int64
@@ -162,11 +232,14 @@ func (r *transactionRepository) GetCategorySummary(ownerType string, ownerID uui
 	
 	summary := make(map[string]int64)
 	
+	// Split transactions contribute their line items; the rest fall back to
+	// the transaction's own category and amount.
 	err := r.db.Model(&models.Transaction{}).
-		Select("category, SUM(amount) as total").
-		Where("owner_type = ? AND owner_id = ? AND type = 'DEBIT' AND created_at BETWEEN ? AND ?", 
+		Select("COALESCE(transaction_line_items.category, transactions.category) as category, SUM(COALESCE(transaction_line_items.amount, transactions.amount)) as total").
+		Joins("LEFT JOIN transaction_line_items ON transaction_line_items.transaction_id = transactions.id AND transaction_line_items.deleted_at IS NULL").
+		Where("transactions.owner_type = ? AND transactions.owner_id = ? AND transactions.type = 'DEBIT' AND transactions.created_at BETWEEN ? AND ?", 
 			ownerType, ownerID, startDate, endDate).
-		Group("category").
+		Group("COALESCE(transaction_line_items.category, transactions.category)").
 		Find(&results).Error
 	
 	for _, result := range results {
@@ -196,4 +269,39 @@ func (r *transactionRepository) GetSourceSummary(ownerType string, ownerID uuid.
 	}
 	
 	return summary, err
-}
\ No newline at end of file
+}
+
+func (r *transactionRepository) GetTagSummary(ownerType string, ownerID uuid.UUID, startDate, endDate time.Time) (map[string]int64, error) {
+	var results []struct {
+		Tag   string
+		Total int64
+	}
+
+	summary := make(map[string]int64)
+
+	err := r.db.Model(&models.Transaction{}).
+		Select("tags.name as tag, SUM(transactions.amount) as total").
+		Joins("JOIN transaction_tags ON transaction_tags.transaction_id = transactions.id").
+		Joins("JOIN tags ON tags.id = transaction_tags.tag_id AND tags.deleted_at IS NULL").
+		Where("transactions.owner_type = ? AND transactions.owner_id = ? AND transactions.type = 'DEBIT' AND transactions.created_at BETWEEN ? AND ?",
+			ownerType, ownerID, startDate, endDate).
+		Group("tags.name").
+		Find(&results).Error
+
+	for _, result := range results {
+		summary[result.Tag] = result.Total
+	}
+
+	return summary, err
+}
+
+func (r *transactionRepository) ReplaceTags(transaction *models.Transaction, tags []models.Tag) error {
+	return r.db.Model(transaction).Association("Tags").Replace(tags)
+}
+
+func applyTransactionFilter(query *gorm.DB, filter TransactionFilter) *gorm.DB {
+	if filter.TagID != nil {
+		query = query.Where("transactions.id IN (SELECT transaction_id FROM transaction_tags WHERE tag_id = ?)", *filter.TagID)
+	}
+	return query
+}
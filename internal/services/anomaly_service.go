@@ -0,0 +1,148 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// A new DEBIT is compared with the owner's DEBITs in the same category over
+// the trailing window. It is an outlier when its robust z-score reaches the
+// threshold and there is enough history to judge.
+const (
+	anomalyWindowDays = 90
+	anomalyMinSamples = 8
+	anomalyThreshold  = 3.5
+)
+
+type AnomalyService interface {
+	GetPersonalAnomalies(userID uuid.UUID, page, limit int) ([]dto.AnomalyResponse, int64, error)
+	GetGroupAnomalies(userID, groupID uuid.UUID, page, limit int) ([]dto.AnomalyResponse, int64, error)
+}
+
+type anomalyService struct {
+	anomalyRepo repositories.AnomalyRepository
+	groupRepo   repositories.GroupRepository
+}
+
+func NewAnomalyService(anomalyRepo repositories.AnomalyRepository, groupRepo repositories.GroupRepository) AnomalyService {
+	return &anomalyService{
+		anomalyRepo: anomalyRepo,
+		groupRepo:   groupRepo,
+	}
+}
+
+func (s *anomalyService) GetPersonalAnomalies(userID uuid.UUID, page, limit int) ([]dto.AnomalyResponse, int64, error) {
+	anomalies, total, err := s.anomalyRepo.FindByOwner("USER", userID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal anomalies")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get anomalies"}
+	}
+
+	return mapAnomaliesToResponse(anomalies), total, nil
+}
+
+func (s *anomalyService) GetGroupAnomalies(userID, groupID uuid.UUID, page, limit int) ([]dto.AnomalyResponse, int64, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	anomalies, total, err := s.anomalyRepo.FindByOwner("GROUP", groupID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group anomalies")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get anomalies"}
+	}
+
+	return mapAnomaliesToResponse(anomalies), total, nil
+}
+
+// scoreDebit measures amount against earlier amounts of the same kind using
+// the median and the median absolute deviation, which a few past outliers
+// cannot skew the way they would a mean and standard deviation. The MAD is
+// floored at 5% of the median so that a flat history does not flag every
+// small increase.
+func scoreDebit(history []int64, amount int64) (median, mad int64, score float64, outlier bool) {
+	if len(history) == 0 {
+		return 0, 0, 0, false
+	}
+
+	median = medianOf(history)
+	deviations := make([]int64, len(history))
+	for i, value := range history {
+		deviation := value - median
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		deviations[i] = deviation
+	}
+	mad = medianOf(deviations)
+
+	spread := mad
+	if floor := median / 20; spread < floor {
+		spread = floor
+	}
+	if spread < 1 {
+		spread = 1
+	}
+
+	// 0.6745 scales the MAD to a standard deviation for normal data
+	score = 0.6745 * float64(amount-median) / float64(spread)
+	score = math.Round(score*100) / 100
+	outlier = len(history) >= anomalyMinSamples && amount > median && score >= anomalyThreshold
+	return median, mad, score, outlier
+}
+
+func medianOf(values []int64) int64 {
+	sorted := append([]int64(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+func mapAnomalyToResponse(anomaly *models.SpendingAnomaly) *dto.AnomalyResponse {
+	response := &dto.AnomalyResponse{
+		ID:              anomaly.ID,
+		TransactionID:   anomaly.TransactionID,
+		OwnerType:       anomaly.OwnerType,
+		OwnerID:         anomaly.OwnerID,
+		Category:        anomaly.Category,
+		Amount:          anomaly.Amount,
+		Currency:        anomaly.Currency,
+		FormattedAmount: formatAmount(anomaly.Amount, anomaly.Currency),
+		Median:          anomaly.Median,
+		FormattedMedian: formatAmount(anomaly.Median, anomaly.Currency),
+		MAD:             anomaly.MAD,
+		Score:           anomaly.Score,
+		SampleSize:      anomaly.SampleSize,
+		WindowDays:      anomaly.WindowDays,
+		RecordedBy:      anomaly.RecordedBy,
+		CreatedAt:       anomaly.CreatedAt.Format(time.RFC3339),
+	}
+
+	if anomaly.Transaction != nil {
+		response.Description = anomaly.Transaction.Description
+	}
+
+	return response
+}
+
+func mapAnomaliesToResponse(anomalies []models.SpendingAnomaly) []dto.AnomalyResponse {
+	response := []dto.AnomalyResponse{}
+	for _, anomaly := range anomalies {
+		response = append(response, *mapAnomalyToResponse(&anomaly))
+	}
+	return response
+}
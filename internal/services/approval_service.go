@@ -0,0 +1,678 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ApprovalService interface {
+	SetPolicy(userID, groupID uuid.UUID, req dto.ApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, error)
+	GetPolicy(userID, groupID uuid.UUID) (*dto.ApprovalPolicyResponse, error)
+	DeletePolicy(userID, groupID uuid.UUID) error
+	ApproveExpense(userID, expenseID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ExpenseApprovalResponse, error)
+	RejectExpense(userID, expenseID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ExpenseApprovalResponse, error)
+	GetApprovals(userID, expenseID uuid.UUID) (*dto.ExpenseApprovalResponse, error)
+	ReevaluatePending(userID, groupID uuid.UUID)
+}
+
+type approvalService struct {
+	approvalRepo     repositories.ApprovalRepository
+	expenseRepo      repositories.PlannedExpenseRepository
+	groupRepo        repositories.GroupRepository
+	auditRepo        repositories.AuditLogRepository
+	notificationRepo repositories.NotificationRepository
+	db               *gorm.DB
+}
+
+func NewApprovalService(
+	approvalRepo repositories.ApprovalRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+	notificationRepo repositories.NotificationRepository,
+	db *gorm.DB,
+) ApprovalService {
+	return &approvalService{
+		approvalRepo:     approvalRepo,
+		expenseRepo:      expenseRepo,
+		groupRepo:        groupRepo,
+		auditRepo:        auditRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
+	}
+}
+
+func (s *approvalService) SetPolicy(userID, groupID uuid.UUID, req dto.ApprovalPolicyRequest) (*dto.ApprovalPolicyResponse, error) {
+	// Check if user is a manager of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can set the approval policy"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to set approval policy"}
+	}
+	if policy == nil {
+		policy = &models.ApprovalPolicy{GroupID: groupID}
+	}
+
+	policy.Threshold = req.Threshold
+	policy.Mode = req.Mode
+	policy.RequiredApprovals = req.RequiredApprovals
+	if policy.RequiredApprovals == 0 {
+		policy.RequiredApprovals = 1
+	}
+	policy.UpdatedBy = userID
+
+	if policy.Mode == "managers" {
+		members, err := s.groupRepo.FindMembers(groupID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get group members")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to set approval policy"}
+		}
+		managers := 0
+		for _, member := range members {
+			if member.Status == "active" && member.Role == "manager" {
+				managers++
+			}
+		}
+		if policy.RequiredApprovals > managers {
+			return nil, &errors.AppError{Code: "INVALID_POLICY", Message: fmt.Sprintf("The group only has %d manager(s) to approve expenses", managers)}
+		}
+	}
+
+	if err := s.approvalRepo.SavePolicy(policy); err != nil {
+		log.Error().Err(err).Msg("Failed to save approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to set approval policy"}
+	}
+
+	// Expenses no longer above the threshold do not need approval anymore
+	released := s.releasePending(userID, groupID, "below_threshold", func(expense *models.PlannedExpense) bool {
+		return !requiresApproval(policy, expense.EstimatedPrice)
+	})
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "group",
+		EntityID: groupID,
+		Action:   "set_approval_policy",
+		Changes: map[string]interface{}{
+			"threshold":          policy.Threshold,
+			"mode":               policy.Mode,
+			"required_approvals": policy.RequiredApprovals,
+			"released_expenses":  released,
+		},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapPolicyToResponse(policy, group.Currency), nil
+}
+
+func (s *approvalService) GetPolicy(userID, groupID uuid.UUID) (*dto.ApprovalPolicyResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get approval policy"}
+	}
+	if policy == nil {
+		return nil, &errors.AppError{Code: "POLICY_NOT_FOUND", Message: "Group has no approval policy"}
+	}
+
+	return mapPolicyToResponse(policy, group.Currency), nil
+}
+
+func (s *approvalService) DeletePolicy(userID, groupID uuid.UUID) error {
+	// Check if user is a manager of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can remove the approval policy"}
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to remove approval policy"}
+	}
+	if policy == nil {
+		return &errors.AppError{Code: "POLICY_NOT_FOUND", Message: "Group has no approval policy"}
+	}
+
+	if err := s.approvalRepo.DeletePolicy(groupID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete approval policy")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to remove approval policy"}
+	}
+
+	// Without a policy nothing waits for approval
+	released := s.releasePending(userID, groupID, "policy_removed", func(*models.PlannedExpense) bool {
+		return true
+	})
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "group",
+		EntityID:    groupID,
+		Action:      "delete_approval_policy",
+		Changes:     map[string]interface{}{"released_expenses": released},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+func (s *approvalService) ApproveExpense(userID, expenseID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ExpenseApprovalResponse, error) {
+	return s.decide(userID, expenseID, "approve", req)
+}
+
+func (s *approvalService) RejectExpense(userID, expenseID uuid.UUID, req dto.ApprovalDecisionRequest) (*dto.ExpenseApprovalResponse, error) {
+	if strings.TrimSpace(req.Reason) == "" {
+		return nil, &errors.AppError{Code: "REASON_REQUIRED", Message: "A reason is required to reject an expense"}
+	}
+	return s.decide(userID, expenseID, "reject", req)
+}
+
+func (s *approvalService) GetApprovals(userID, expenseID uuid.UUID) (*dto.ExpenseApprovalResponse, error) {
+	expense, err := s.expenseRepo.FindByID(expenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
+	}
+	if expense.GroupID == nil {
+		return nil, &errors.AppError{Code: "NOT_GROUP_EXPENSE", Message: "Only group expenses need approval"}
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *expense.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get approvals"}
+	}
+
+	return s.approvalState(expense, policy)
+}
+
+// decide records a member's decision on a pending expense and, once the
+// decisions settle it, moves the expense to planned or rejected.
+func (s *approvalService) decide(userID, expenseID uuid.UUID, decision string, req dto.ApprovalDecisionRequest) (*dto.ExpenseApprovalResponse, error) {
+	expense, err := s.expenseRepo.FindByID(expenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
+	}
+	if expense.GroupID == nil {
+		return nil, &errors.AppError{Code: "NOT_GROUP_EXPENSE", Message: "Only group expenses need approval"}
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *expense.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	if expense.Status != "pending_approval" {
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not pending approval"}
+	}
+	if expense.UserID == userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You cannot decide on your own expense"}
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(*expense.GroupID)
+	if err != nil || policy == nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+	if policy.Mode == "managers" && userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can decide on this expense"}
+	}
+
+	members, err := s.groupRepo.FindMembers(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+	activeMembers, approvers := approvalElectorate(policy, members, expense.UserID)
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the expense so concurrent decisions are counted one after another
+	var locked models.PlannedExpense
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", expenseID).First(&locked).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to lock expense")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+	if locked.Status != "pending_approval" {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not pending approval"}
+	}
+
+	var decisions []models.ExpenseApproval
+	if err := tx.Where("planned_expense_id = ?", expenseID).Find(&decisions).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to get approval decisions")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+	for _, existing := range decisions {
+		if existing.UserID == userID {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "ALREADY_DECIDED", Message: "You have already decided on this expense"}
+		}
+	}
+
+	approval := &models.ExpenseApproval{
+		PlannedExpenseID: expenseID,
+		UserID:           userID,
+		Decision:         decision,
+		Reason:           req.Reason,
+	}
+
+	if err := tx.Create(approval).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create approval decision")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+
+	approvals, rejections := countDecisions(append(decisions, *approval))
+	outcome := approvalOutcome(policy, approvals, rejections, activeMembers, approvers)
+
+	if outcome != "" {
+		// Only the decision that settles the expense moves it
+		result := tx.Model(&models.PlannedExpense{}).
+			Where("id = ? AND status = 'pending_approval'", expenseID).
+			Update("status", outcome)
+		if result.Error != nil {
+			tx.Rollback()
+			log.Error().Err(result.Error).Msg("Failed to update expense status")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+		}
+		if result.RowsAffected == 0 {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not pending approval"}
+		}
+
+		// A rejected occurrence stops its series
+		if outcome == "rejected" && expense.RecurrenceID != nil {
+			if err := tx.Model(&models.ExpenseRecurrence{}).Where("id = ?", *expense.RecurrenceID).
+				Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				log.Error().Err(err).Msg("Failed to stop expense series")
+				return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+			}
+		}
+	}
+
+	// Create audit log
+	changes := map[string]interface{}{"decision": decision}
+	if req.Reason != "" {
+		changes["reason"] = req.Reason
+	}
+	if outcome != "" {
+		changes["status"] = map[string]interface{}{"old": expense.Status, "new": outcome}
+	}
+	auditLog := &models.AuditLog{
+		Entity:      "planned_expense",
+		EntityID:    expenseID,
+		Action:      decision,
+		Changes:     changes,
+		PerformedBy: userID,
+		GroupID:     expense.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record decision"}
+	}
+
+	if outcome != "" {
+		s.notifyRequester(expense, outcome, req.Reason)
+		expense.Status = outcome
+	}
+
+	return s.approvalState(expense, policy)
+}
+
+// ReevaluatePending releases the group's expenses awaiting approval that its
+// current members no longer hold back after someone left, was removed or lost
+// the manager role: the approvals given are now enough, or no one is left who
+// may decide, as when the requester is the only manager remaining.
+func (s *approvalService) ReevaluatePending(userID, groupID uuid.UUID) {
+	policy, err := s.approvalRepo.FindPolicy(groupID)
+	if err != nil || policy == nil {
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get approval policy")
+		}
+		return
+	}
+
+	members, err := s.groupRepo.FindMembers(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return
+	}
+
+	s.releasePending(userID, groupID, "members_changed", func(expense *models.PlannedExpense) bool {
+		decisions, err := s.approvalRepo.FindDecisions(expense.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get approval decisions")
+			return false
+		}
+		activeMembers, approvers := approvalElectorate(policy, members, expense.UserID)
+		approvals, rejections := countDecisions(decisions)
+		return approvalOutcome(policy, approvals, rejections, activeMembers, approvers) == "planned"
+	})
+}
+
+// releasePending makes the group's expenses awaiting approval that release
+// selects payable, recording why on each one and telling its requester, and
+// returns how many it released. Failures are logged and leave the expense
+// pending.
+func (s *approvalService) releasePending(userID, groupID uuid.UUID, reason string, release func(expense *models.PlannedExpense) bool) int {
+	pending, err := s.approvalRepo.FindPending(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get pending expenses")
+		return 0
+	}
+
+	released := 0
+	for i := range pending {
+		expense := &pending[i]
+		if !release(expense) {
+			continue
+		}
+
+		ok, err := s.approvalRepo.Release(expense.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to release pending expense")
+			continue
+		}
+		if !ok {
+			continue
+		}
+		released++
+
+		// Create audit log
+		auditLog := &models.AuditLog{
+			Entity:   "planned_expense",
+			EntityID: expense.ID,
+			Action:   "release_approval",
+			Changes: map[string]interface{}{
+				"reason": reason,
+				"status": map[string]interface{}{"old": "pending_approval", "new": "planned"},
+			},
+			PerformedBy: userID,
+			GroupID:     expense.GroupID,
+		}
+
+		if err := s.auditRepo.Create(auditLog); err != nil {
+			log.Error().Err(err).Msg("Failed to create audit log")
+		}
+
+		s.notifyRequester(expense, "planned", "")
+	}
+
+	return released
+}
+
+// notifyRequester tells the member who planned the expense how its approval
+// ended.
+func (s *approvalService) notifyRequester(expense *models.PlannedExpense, outcome, reason string) {
+	title := "Expense approved"
+	message := fmt.Sprintf("%s was approved and can now be paid", expense.Item)
+	if outcome == "rejected" {
+		title = "Expense rejected"
+		message = fmt.Sprintf("%s was rejected: %s", expense.Item, reason)
+	}
+
+	notifyUsers(s.notificationRepo, []uuid.UUID{expense.UserID}, "expense_"+outcome, title, message,
+		map[string]interface{}{
+			"expense_id": expense.ID.String(),
+			"group_id":   expense.GroupID.String(),
+		})
+}
+
+func (s *approvalService) approvalState(expense *models.PlannedExpense, policy *models.ApprovalPolicy) (*dto.ExpenseApprovalResponse, error) {
+	decisions, err := s.approvalRepo.FindDecisions(expense.ID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval decisions")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get approvals"}
+	}
+
+	response := &dto.ExpenseApprovalResponse{
+		ExpenseID: expense.ID,
+		Status:    expense.Status,
+		Decisions: []dto.ApprovalDecisionResponse{},
+	}
+	response.Approvals, response.Rejections = countDecisions(decisions)
+
+	if policy != nil {
+		members, err := s.groupRepo.FindMembers(*expense.GroupID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get group members")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get approvals"}
+		}
+		activeMembers, approvers := approvalElectorate(policy, members, expense.UserID)
+		response.Mode = policy.Mode
+		response.Required = approvalsRequired(policy, activeMembers, approvers)
+	}
+
+	for _, decision := range decisions {
+		item := dto.ApprovalDecisionResponse{
+			UserID:    decision.UserID,
+			Decision:  decision.Decision,
+			Reason:    decision.Reason,
+			CreatedAt: decision.CreatedAt,
+		}
+		if decision.User != nil {
+			item.FirstName = decision.User.FirstName
+			item.LastName = decision.User.LastName
+		}
+		response.Decisions = append(response.Decisions, item)
+	}
+
+	return response, nil
+}
+
+// approvalElectorate counts the active members of a group and, among them,
+// those who can decide on an expense requested by requester.
+func approvalElectorate(policy *models.ApprovalPolicy, members []models.UserGroup, requester uuid.UUID) (int, int) {
+	activeMembers := 0
+	for _, member := range members {
+		if member.Status == "active" {
+			activeMembers++
+		}
+	}
+	return activeMembers, len(approverIDs(policy, members, requester))
+}
+
+// requiresApproval reports whether a group expense of the given amount must
+// be approved before it can be paid.
+func requiresApproval(policy *models.ApprovalPolicy, amount int64) bool {
+	return policy != nil && amount > policy.Threshold
+}
+
+// approvalsRequired is the number of approvals an expense needs. In majority
+// mode the requester's own approval is implied and counts towards it. In
+// managers mode it is capped by the managers other than the requester, so a
+// manager's expense never waits on more approvals than there are people to
+// give them; with no one else to decide it needs none.
+func approvalsRequired(policy *models.ApprovalPolicy, activeMembers, approvers int) int {
+	if policy.Mode == "majority" {
+		return activeMembers/2 + 1
+	}
+	return min(policy.RequiredApprovals, approvers)
+}
+
+// approvalOutcome returns the status the decisions so far settle an expense
+// into, or "" while it is still pending. A manager's rejection is final; a
+// majority vote is rejected once a majority can no longer be reached.
+func approvalOutcome(policy *models.ApprovalPolicy, approvals, rejections, activeMembers, approvers int) string {
+	required := approvalsRequired(policy, activeMembers, approvers)
+	if policy.Mode == "majority" {
+		switch {
+		case approvals+1 >= required:
+			return "planned"
+		case activeMembers-rejections < required:
+			return "rejected"
+		}
+		return ""
+	}
+
+	switch {
+	case rejections > 0:
+		return "rejected"
+	case approvals >= required:
+		return "planned"
+	}
+	return ""
+}
+
+// approvalHold reports whether a new group expense has to wait for approval
+// and who can decide on it. Nothing is held when the requester's own approval
+// already settles it, as in a one-member group voting by majority, or when no
+// one else can decide, as for the only manager of a group.
+func approvalHold(policy *models.ApprovalPolicy, members []models.UserGroup, requester uuid.UUID, amount int64) ([]uuid.UUID, bool) {
+	if !requiresApproval(policy, amount) {
+		return nil, false
+	}
+
+	activeMembers, approvers := approvalElectorate(policy, members, requester)
+	if approvalOutcome(policy, 0, 0, activeMembers, approvers) == "planned" {
+		return nil, false
+	}
+
+	return approverIDs(policy, members, requester), true
+}
+
+// approvalRequests builds the notifications asking approvers to decide on a
+// held expense.
+func approvalRequests(expense *models.PlannedExpense, approvers []uuid.UUID) []models.Notification {
+	var notifications []models.Notification
+	for _, approver := range approvers {
+		notifications = append(notifications, models.Notification{
+			UserID:  approver,
+			Type:    "approval_request",
+			Title:   "Expense awaiting approval",
+			Message: fmt.Sprintf("%s is waiting for your approval", expense.Item),
+			Data: map[string]interface{}{
+				"expense_id": expense.ID.String(),
+				"group_id":   expense.GroupID.String(),
+			},
+		})
+	}
+	return notifications
+}
+
+// approverIDs returns the members who can decide on an expense requested by
+// requester.
+func approverIDs(policy *models.ApprovalPolicy, members []models.UserGroup, requester uuid.UUID) []uuid.UUID {
+	var approvers []uuid.UUID
+	for _, member := range members {
+		if member.Status != "active" || member.UserID == requester {
+			continue
+		}
+		if policy.Mode == "managers" && member.Role != "manager" {
+			continue
+		}
+		approvers = append(approvers, member.UserID)
+	}
+	return approvers
+}
+
+func countDecisions(decisions []models.ExpenseApproval) (approvals, rejections int) {
+	for _, decision := range decisions {
+		if decision.Decision == "approve" {
+			approvals++
+		} else {
+			rejections++
+		}
+	}
+	return approvals, rejections
+}
+
+// notifyUsers sends the same notification to each user. Notifications are a
+// courtesy, so failures are only logged.
+func notifyUsers(notificationRepo repositories.NotificationRepository, userIDs []uuid.UUID, kind, title, message string, data map[string]interface{}) {
+	var notifications []models.Notification
+	for _, userID := range userIDs {
+		notifications = append(notifications, models.Notification{
+			UserID:  userID,
+			Type:    kind,
+			Title:   title,
+			Message: message,
+			Data:    data,
+		})
+	}
+
+	if err := notificationRepo.CreateBatch(notifications); err != nil {
+		log.Error().Err(err).Msg("Failed to create notifications")
+	}
+}
+
+func mapPolicyToResponse(policy *models.ApprovalPolicy, currency string) *dto.ApprovalPolicyResponse {
+	response := &dto.ApprovalPolicyResponse{
+		GroupID:            policy.GroupID,
+		Threshold:          policy.Threshold,
+		FormattedThreshold: formatAmount(policy.Threshold, currency),
+		Currency:           currency,
+		Mode:               policy.Mode,
+		UpdatedBy:          policy.UpdatedBy,
+		UpdatedAt:          policy.UpdatedAt,
+	}
+	if policy.Mode == "managers" {
+		response.RequiredApprovals = policy.RequiredApprovals
+	}
+	return response
+}
@@ -0,0 +1,435 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/internal/storage"
+	"balanca/pkg/errors"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// allowedAttachmentTypes lists the sniffed MIME types accepted for uploads.
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+type AttachmentService interface {
+	UploadTransactionAttachment(userID, transactionID uuid.UUID, fileName string, content io.Reader) (*dto.AttachmentResponse, error)
+	UploadExpenseAttachment(userID, expenseID uuid.UUID, fileName string, content io.Reader) (*dto.AttachmentResponse, error)
+	GetTransactionAttachments(userID, transactionID uuid.UUID) ([]dto.AttachmentResponse, error)
+	GetExpenseAttachments(userID, expenseID uuid.UUID) ([]dto.AttachmentResponse, error)
+	GetDownloadURL(userID, attachmentID uuid.UUID) (*dto.AttachmentURLResponse, error)
+	OpenSignedDownload(attachmentID uuid.UUID, userID, expires, signature string) (*models.Attachment, io.ReadCloser, error)
+	DeleteAttachment(userID, attachmentID uuid.UUID) error
+}
+
+type attachmentService struct {
+	attachmentRepo  repositories.AttachmentRepository
+	transactionRepo repositories.TransactionRepository
+	expenseRepo     repositories.PlannedExpenseRepository
+	groupRepo       repositories.GroupRepository
+	auditRepo       repositories.AuditLogRepository
+	store           storage.BlobStore
+	maxSize         int64
+	urlSecret       []byte
+	urlExpiration   time.Duration
+}
+
+func NewAttachmentService(
+	attachmentRepo repositories.AttachmentRepository,
+	transactionRepo repositories.TransactionRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+	store storage.BlobStore,
+	maxSize int64,
+	urlSecret string,
+	urlExpiration time.Duration,
+) AttachmentService {
+	return &attachmentService{
+		attachmentRepo:  attachmentRepo,
+		transactionRepo: transactionRepo,
+		expenseRepo:     expenseRepo,
+		groupRepo:       groupRepo,
+		auditRepo:       auditRepo,
+		store:           store,
+		maxSize:         maxSize,
+		urlSecret:       []byte(urlSecret),
+		urlExpiration:   urlExpiration,
+	}
+}
+
+func (s *attachmentService) UploadTransactionAttachment(userID, transactionID uuid.UUID, fileName string, content io.Reader) (*dto.AttachmentResponse, error) {
+	groupID, err := s.checkTransactionAccess(userID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TransactionID: &transactionID,
+		GroupID:       groupID,
+	}
+
+	return s.upload(userID, attachment, fileName, content)
+}
+
+func (s *attachmentService) UploadExpenseAttachment(userID, expenseID uuid.UUID, fileName string, content io.Reader) (*dto.AttachmentResponse, error) {
+	groupID, err := s.checkExpenseAccess(userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		PlannedExpenseID: &expenseID,
+		GroupID:          groupID,
+	}
+
+	return s.upload(userID, attachment, fileName, content)
+}
+
+func (s *attachmentService) upload(userID uuid.UUID, attachment *models.Attachment, fileName string, content io.Reader) (*dto.AttachmentResponse, error) {
+	// Read one byte past the limit to detect oversized files
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to read upload")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to upload attachment"}
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, &errors.AppError{Code: "FILE_TOO_LARGE", Message: fmt.Sprintf("File exceeds the %d byte limit", s.maxSize)}
+	}
+	if len(data) == 0 {
+		return nil, &errors.AppError{Code: "INVALID_FILE", Message: "File is empty"}
+	}
+
+	// Trust the contents, not the client-supplied file name or header
+	contentType := http.DetectContentType(data)
+	if !allowedAttachmentTypes[contentType] {
+		return nil, &errors.AppError{Code: "INVALID_FILE_TYPE", Message: "Unsupported file type: " + contentType}
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	// Uploading the same file to the same record again is a no-op
+	existing, err := s.attachmentRepo.FindDuplicate(attachment.TransactionID, attachment.PlannedExpenseID, checksum)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check duplicate attachment")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to upload attachment"}
+	}
+	if existing != nil {
+		return mapAttachmentToResponse(existing), nil
+	}
+
+	exists, err := s.store.Exists(checksum)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check blob")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to upload attachment"}
+	}
+	if !exists {
+		if err := s.store.Put(checksum, bytes.NewReader(data)); err != nil {
+			log.Error().Err(err).Msg("Failed to store blob")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to upload attachment"}
+		}
+	}
+
+	attachment.FileName = sanitizeFileName(fileName)
+	attachment.ContentType = contentType
+	attachment.Size = int64(len(data))
+	attachment.Checksum = checksum
+	attachment.StorageKey = checksum
+	attachment.UploadedBy = userID
+
+	if err := s.attachmentRepo.Create(attachment); err != nil {
+		log.Error().Err(err).Msg("Failed to create attachment")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to upload attachment"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "attachment",
+		EntityID: attachment.ID,
+		Action:   "upload",
+		Changes: map[string]interface{}{
+			"file_name":          attachment.FileName,
+			"size":               attachment.Size,
+			"checksum":           attachment.Checksum,
+			"transaction_id":     attachment.TransactionID,
+			"planned_expense_id": attachment.PlannedExpenseID,
+		},
+		PerformedBy: userID,
+		GroupID:     attachment.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapAttachmentToResponse(attachment), nil
+}
+
+func (s *attachmentService) GetTransactionAttachments(userID, transactionID uuid.UUID) ([]dto.AttachmentResponse, error) {
+	if _, err := s.checkTransactionAccess(userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.FindByTransaction(transactionID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get attachments")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get attachments"}
+	}
+
+	return mapAttachmentsToResponse(attachments), nil
+}
+
+func (s *attachmentService) GetExpenseAttachments(userID, expenseID uuid.UUID) ([]dto.AttachmentResponse, error) {
+	if _, err := s.checkExpenseAccess(userID, expenseID); err != nil {
+		return nil, err
+	}
+
+	attachments, err := s.attachmentRepo.FindByPlannedExpense(expenseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get attachments")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get attachments"}
+	}
+
+	return mapAttachmentsToResponse(attachments), nil
+}
+
+// GetDownloadURL returns a short-lived URL that can be opened without the
+// Authorization header, e.g. from an <img> tag. The URL is bound to the user
+// so access is re-checked when it is used.
+func (s *attachmentService) GetDownloadURL(userID, attachmentID uuid.UUID) (*dto.AttachmentURLResponse, error) {
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "ATTACHMENT_NOT_FOUND", Message: "Attachment not found"}
+	}
+
+	if err := s.checkAttachmentAccess(userID, attachment); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(s.urlExpiration).UTC()
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	query := url.Values{}
+	query.Set("user_id", userID.String())
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(attachmentID.String(), userID.String(), expires))
+
+	return &dto.AttachmentURLResponse{
+		URL:       fmt.Sprintf("/api/v1/attachments/%s/download?%s", attachmentID, query.Encode()),
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *attachmentService) OpenSignedDownload(attachmentID uuid.UUID, userID, expires, signature string) (*models.Attachment, io.ReadCloser, error) {
+	expected := s.sign(attachmentID.String(), userID, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, nil, &errors.AppError{Code: "INVALID_SIGNATURE", Message: "Invalid download link"}
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, nil, &errors.AppError{Code: "LINK_EXPIRED", Message: "Download link has expired"}
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, nil, &errors.AppError{Code: "INVALID_SIGNATURE", Message: "Invalid download link"}
+	}
+
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return nil, nil, &errors.AppError{Code: "ATTACHMENT_NOT_FOUND", Message: "Attachment not found"}
+	}
+
+	// The user may have left the group since the link was issued
+	if err := s.checkAttachmentAccess(userUUID, attachment); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := s.store.Get(attachment.StorageKey)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to open blob")
+		return nil, nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to download attachment"}
+	}
+
+	return attachment, content, nil
+}
+
+func (s *attachmentService) DeleteAttachment(userID, attachmentID uuid.UUID) error {
+	attachment, err := s.attachmentRepo.FindByID(attachmentID)
+	if err != nil {
+		return &errors.AppError{Code: "ATTACHMENT_NOT_FOUND", Message: "Attachment not found"}
+	}
+
+	if err := s.checkAttachmentAccess(userID, attachment); err != nil {
+		return err
+	}
+
+	// Group attachments can be removed by the uploader or a manager
+	if attachment.UploadedBy != userID {
+		if attachment.GroupID == nil {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *attachment.GroupID)
+		if err != nil || userGroup.Role != "manager" {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Only the uploader or a manager can delete this attachment"}
+		}
+	}
+
+	if err := s.attachmentRepo.Delete(attachmentID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete attachment")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete attachment"}
+	}
+
+	// Drop the blob once no attachment refers to it anymore
+	count, err := s.attachmentRepo.CountByChecksum(attachment.Checksum)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to count attachments")
+	} else if count == 0 {
+		if err := s.store.Delete(attachment.StorageKey); err != nil {
+			log.Error().Err(err).Msg("Failed to delete blob")
+		}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "attachment",
+		EntityID: attachment.ID,
+		Action:   "delete",
+		Changes: map[string]interface{}{
+			"file_name":          attachment.FileName,
+			"checksum":           attachment.Checksum,
+			"transaction_id":     attachment.TransactionID,
+			"planned_expense_id": attachment.PlannedExpenseID,
+		},
+		PerformedBy: userID,
+		GroupID:     attachment.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+// checkTransactionAccess applies the same rules as reading the transaction
+// and returns its group, if any.
+func (s *attachmentService) checkTransactionAccess(userID, transactionID uuid.UUID) (*uuid.UUID, error) {
+	transaction, err := s.transactionRepo.FindByID(transactionID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "TRANSACTION_NOT_FOUND", Message: "Transaction not found"}
+	}
+
+	if transaction.OwnerType == "GROUP" && transaction.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *transaction.GroupID)
+		if err != nil || userGroup.Status != "active" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		return transaction.GroupID, nil
+	}
+
+	if transaction.UserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+	return nil, nil
+}
+
+// checkExpenseAccess applies the same rules as reading the planned expense
+// and returns its group, if any.
+func (s *attachmentService) checkExpenseAccess(userID, expenseID uuid.UUID) (*uuid.UUID, error) {
+	expense, err := s.expenseRepo.FindByID(expenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
+	}
+
+	if expense.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *expense.GroupID)
+		if err != nil || userGroup.Status != "active" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		return expense.GroupID, nil
+	}
+
+	if expense.UserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+	return nil, nil
+}
+
+func (s *attachmentService) checkAttachmentAccess(userID uuid.UUID, attachment *models.Attachment) error {
+	var err error
+	if attachment.TransactionID != nil {
+		_, err = s.checkTransactionAccess(userID, *attachment.TransactionID)
+	} else if attachment.PlannedExpenseID != nil {
+		_, err = s.checkExpenseAccess(userID, *attachment.PlannedExpenseID)
+	} else {
+		err = &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+	return err
+}
+
+func (s *attachmentService) sign(attachmentID, userID, expires string) string {
+	mac := hmac.New(sha256.New, s.urlSecret)
+	mac.Write([]byte(attachmentID + "|" + userID + "|" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// sanitizeFileName keeps only the base name so it is safe to echo back in a
+// Content-Disposition header.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+func mapAttachmentToResponse(attachment *models.Attachment) *dto.AttachmentResponse {
+	return &dto.AttachmentResponse{
+		ID:               attachment.ID,
+		TransactionID:    attachment.TransactionID,
+		PlannedExpenseID: attachment.PlannedExpenseID,
+		FileName:         attachment.FileName,
+		ContentType:      attachment.ContentType,
+		Size:             attachment.Size,
+		Checksum:         attachment.Checksum,
+		UploadedBy:       attachment.UploadedBy,
+		CreatedAt:        attachment.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapAttachmentsToResponse(attachments []models.Attachment) []dto.AttachmentResponse {
+	var response []dto.AttachmentResponse
+	for _, attachment := range attachments {
+		response = append(response, *mapAttachmentToResponse(&attachment))
+	}
+	return response
+}
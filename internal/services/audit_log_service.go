@@ -0,0 +1,117 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type AuditLogService interface {
+	GetPersonalAuditLogs(userID uuid.UUID, page, limit int) ([]dto.AuditLogResponse, int64, error)
+	GetPersonalAuditLogsAfter(userID uuid.UUID, cursor string, limit int) ([]dto.AuditLogResponse, string, error)
+	GetGroupAuditLogs(userID, groupID uuid.UUID, page, limit int) ([]dto.AuditLogResponse, int64, error)
+	GetGroupAuditLogsAfter(userID, groupID uuid.UUID, cursor string, limit int) ([]dto.AuditLogResponse, string, error)
+}
+
+type auditLogService struct {
+	auditRepo repositories.AuditLogRepository
+	groupRepo repositories.GroupRepository
+}
+
+func NewAuditLogService(auditRepo repositories.AuditLogRepository, groupRepo repositories.GroupRepository) AuditLogService {
+	return &auditLogService{
+		auditRepo: auditRepo,
+		groupRepo: groupRepo,
+	}
+}
+
+// GetPersonalAuditLogs lists the actions the user performed, newest first.
+func (s *auditLogService) GetPersonalAuditLogs(userID uuid.UUID, page, limit int) ([]dto.AuditLogResponse, int64, error) {
+	logs, total, err := s.auditRepo.FindByUser(userID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal audit logs")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get audit logs"}
+	}
+
+	return mapAuditLogsToResponse(logs), total, nil
+}
+
+func (s *auditLogService) GetPersonalAuditLogsAfter(userID uuid.UUID, cursor string, limit int) ([]dto.AuditLogResponse, string, error) {
+	after, err := repositories.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", &errors.AppError{Code: "INVALID_CURSOR", Message: "Invalid cursor"}
+	}
+
+	logs, next, err := s.auditRepo.FindByUserAfter(userID, after, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal audit logs")
+		return nil, "", &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get audit logs"}
+	}
+
+	return mapAuditLogsToResponse(logs), encodeCursor(next), nil
+}
+
+// GetGroupAuditLogs lists the actions performed in a group, newest first.
+func (s *auditLogService) GetGroupAuditLogs(userID, groupID uuid.UUID, page, limit int) ([]dto.AuditLogResponse, int64, error) {
+	if err := s.checkMember(userID, groupID); err != nil {
+		return nil, 0, err
+	}
+
+	logs, total, err := s.auditRepo.FindByGroup(groupID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group audit logs")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get audit logs"}
+	}
+
+	return mapAuditLogsToResponse(logs), total, nil
+}
+
+func (s *auditLogService) GetGroupAuditLogsAfter(userID, groupID uuid.UUID, cursor string, limit int) ([]dto.AuditLogResponse, string, error) {
+	if err := s.checkMember(userID, groupID); err != nil {
+		return nil, "", err
+	}
+
+	after, err := repositories.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", &errors.AppError{Code: "INVALID_CURSOR", Message: "Invalid cursor"}
+	}
+
+	logs, next, err := s.auditRepo.FindByGroupAfter(groupID, after, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group audit logs")
+		return nil, "", &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get audit logs"}
+	}
+
+	return mapAuditLogsToResponse(logs), encodeCursor(next), nil
+}
+
+func (s *auditLogService) checkMember(userID, groupID uuid.UUID) error {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+	return nil
+}
+
+func mapAuditLogsToResponse(logs []models.AuditLog) []dto.AuditLogResponse {
+	response := make([]dto.AuditLogResponse, 0, len(logs))
+	for _, auditLog := range logs {
+		response = append(response, dto.AuditLogResponse{
+			ID:            auditLog.ID,
+			Entity:        auditLog.Entity,
+			EntityID:      auditLog.EntityID,
+			Action:        auditLog.Action,
+			Changes:       auditLog.Changes,
+			PerformedBy:   auditLog.PerformedBy,
+			PerformerName: auditLog.User.FirstName + " " + auditLog.User.LastName,
+			PerformedAt:   auditLog.PerformedAt,
+			GroupID:       auditLog.GroupID,
+		})
+	}
+	return response
+}
@@ -6,6 +6,7 @@ import (
 	"balanca/internal/repositories"
 	"balanca/internal/utils"
 	"balanca/pkg/errors"
+	"balanca/pkg/money"
 	"fmt"
 	"time"
 
@@ -52,6 +53,10 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 		return nil, &errors.AppError{Code: "USER_EXISTS", Message: "User with this phone number already exists"}
 	}
 
+	if !money.IsSupported(money.NormalizeCode(req.Currency)) {
+		return nil, &errors.AppError{Code: "UNSUPPORTED_CURRENCY", Message: "Unsupported currency"}
+	}
+
 	// Hash password
 	hashedPassword, err := utils.HashPassword(req.Password)
 	if err != nil {
@@ -67,6 +72,7 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 		LastName:     req.LastName,
 		PasswordHash: hashedPassword,
 		Balance:      0,
+		Currency:     money.NormalizeCode(req.Currency),
 		IsActive:     true,
 	}
 
@@ -109,6 +115,7 @@ func (s *authService) Register(req dto.RegisterRequest) (*dto.AuthResponse, erro
 			FirstName:   user.FirstName,
 			LastName:    user.LastName,
 			Balance:     user.Balance,
+			Currency:    user.Currency,
 			IsActive:    user.IsActive,
 			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		},
@@ -170,6 +177,7 @@ func (s *authService) Login(req dto.LoginRequest) (*dto.AuthResponse, error) {
 			FirstName:   user.FirstName,
 			LastName:    user.LastName,
 			Balance:     user.Balance,
+			Currency:    user.Currency,
 			IsActive:    user.IsActive,
 			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		},
@@ -225,6 +233,7 @@ func (s *authService) RefreshToken(refreshToken string) (*dto.AuthResponse, erro
 			FirstName:   user.FirstName,
 			LastName:    user.LastName,
 			Balance:     user.Balance,
+			Currency:    user.Currency,
 			IsActive:    user.IsActive,
 			CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		},
@@ -0,0 +1,479 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const budgetDateLayout = "2006-01-02"
+
+type BudgetService interface {
+	CreatePersonalBudget(userID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error)
+	CreateGroupBudget(userID, groupID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error)
+	GetPersonalBudgets(userID uuid.UUID) ([]dto.BudgetResponse, error)
+	GetGroupBudgets(userID, groupID uuid.UUID) ([]dto.BudgetResponse, error)
+	UpdateBudget(userID, budgetID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error)
+	DeleteBudget(userID, budgetID uuid.UUID) error
+	GetPersonalBudgetReport(userID uuid.UUID, filter dto.BudgetReportFilter) (*dto.BudgetReportResponse, error)
+	GetGroupBudgetReport(userID, groupID uuid.UUID, filter dto.BudgetReportFilter) (*dto.BudgetReportResponse, error)
+}
+
+type budgetService struct {
+	budgetRepo      repositories.BudgetRepository
+	transactionRepo repositories.TransactionRepository
+	userRepo        repositories.UserRepository
+	groupRepo       repositories.GroupRepository
+	categoryRepo    repositories.CategoryRepository
+	auditRepo       repositories.AuditLogRepository
+}
+
+func NewBudgetService(
+	budgetRepo repositories.BudgetRepository,
+	transactionRepo repositories.TransactionRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+	categoryRepo repositories.CategoryRepository,
+	auditRepo repositories.AuditLogRepository,
+) BudgetService {
+	return &budgetService{
+		budgetRepo:      budgetRepo,
+		transactionRepo: transactionRepo,
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+		categoryRepo:    categoryRepo,
+		auditRepo:       auditRepo,
+	}
+}
+
+func (s *budgetService) CreatePersonalBudget(userID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error) {
+	budget := &models.Budget{
+		UserID:    &userID,
+		CreatedBy: userID,
+	}
+
+	return s.createBudget(userID, budget, req)
+}
+
+func (s *budgetService) CreateGroupBudget(userID, groupID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error) {
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group budgets"}
+	}
+
+	budget := &models.Budget{
+		GroupID:   &groupID,
+		CreatedBy: userID,
+	}
+
+	return s.createBudget(userID, budget, req)
+}
+
+func (s *budgetService) createBudget(userID uuid.UUID, budget *models.Budget, req dto.BudgetRequest) (*dto.BudgetResponse, error) {
+	if err := s.fillBudget(userID, budget, req); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.ownerCurrency(budget.UserID, budget.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Create(budget); err != nil {
+		log.Error().Err(err).Msg("Failed to create budget")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create budget"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "budget",
+		EntityID:    budget.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"category": budget.Category, "period": budget.Period, "amount": budget.Amount},
+		PerformedBy: userID,
+		GroupID:     budget.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapBudgetToResponse(budget, currency), nil
+}
+
+func (s *budgetService) GetPersonalBudgets(userID uuid.UUID) ([]dto.BudgetResponse, error) {
+	currency, err := s.ownerCurrency(&userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.FindByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal budgets")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get budgets"}
+	}
+
+	return mapBudgetsToResponse(budgets, currency), nil
+}
+
+func (s *budgetService) GetGroupBudgets(userID, groupID uuid.UUID) ([]dto.BudgetResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	currency, err := s.ownerCurrency(nil, &groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.FindByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group budgets")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get budgets"}
+	}
+
+	return mapBudgetsToResponse(budgets, currency), nil
+}
+
+func (s *budgetService) UpdateBudget(userID, budgetID uuid.UUID, req dto.BudgetRequest) (*dto.BudgetResponse, error) {
+	budget, err := s.findBudget(userID, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	old := map[string]interface{}{"category": budget.Category, "period": budget.Period, "amount": budget.Amount, "rollover": budget.Rollover}
+	if err := s.fillBudget(userID, budget, req); err != nil {
+		return nil, err
+	}
+
+	currency, err := s.ownerCurrency(budget.UserID, budget.GroupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.budgetRepo.Update(budget); err != nil {
+		log.Error().Err(err).Msg("Failed to update budget")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update budget"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "budget",
+		EntityID: budget.ID,
+		Action:   "update",
+		Changes: map[string]interface{}{
+			"old": old,
+			"new": map[string]interface{}{"category": budget.Category, "period": budget.Period, "amount": budget.Amount, "rollover": budget.Rollover},
+		},
+		PerformedBy: userID,
+		GroupID:     budget.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapBudgetToResponse(budget, currency), nil
+}
+
+func (s *budgetService) DeleteBudget(userID, budgetID uuid.UUID) error {
+	budget, err := s.findBudget(userID, budgetID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.budgetRepo.Delete(budgetID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete budget")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete budget"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "budget",
+		EntityID:    budgetID,
+		Action:      "delete",
+		Changes:     map[string]interface{}{"category": budget.Category, "period": budget.Period},
+		PerformedBy: userID,
+		GroupID:     budget.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+func (s *budgetService) GetPersonalBudgetReport(userID uuid.UUID, filter dto.BudgetReportFilter) (*dto.BudgetReportResponse, error) {
+	currency, err := s.ownerCurrency(&userID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.FindByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal budgets")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
+	return s.report("USER", userID, currency, budgets, filter)
+}
+
+func (s *budgetService) GetGroupBudgetReport(userID, groupID uuid.UUID, filter dto.BudgetReportFilter) (*dto.BudgetReportResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	currency, err := s.ownerCurrency(nil, &groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	budgets, err := s.budgetRepo.FindByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group budgets")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
+	return s.report("GROUP", groupID, currency, budgets, filter)
+}
+
+// report compares each budget with the category's spending in the period
+// that contains the filter date. Budgets starting after that period are
+// left out. Category totals are shared between budgets with the same period.
+func (s *budgetService) report(ownerType string, ownerID uuid.UUID, currency string, budgets []models.Budget, filter dto.BudgetReportFilter) (*dto.BudgetReportResponse, error) {
+	date := time.Now().UTC()
+	if filter.Date != "" {
+		parsed, err := time.Parse(budgetDateLayout, filter.Date)
+		if err != nil {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Date must be formatted as YYYY-MM-DD"}
+		}
+		date = parsed
+	}
+
+	summaries := make(map[[2]time.Time]map[string]int64)
+	spentIn := func(category string, startDate, endDate time.Time) (int64, error) {
+		key := [2]time.Time{startDate, endDate}
+		summary, ok := summaries[key]
+		if !ok {
+			var err error
+			summary, err = s.transactionRepo.GetCategorySummary(ownerType, ownerID, startDate, endDate)
+			if err != nil {
+				return 0, err
+			}
+			summaries[key] = summary
+		}
+		return summary[category], nil
+	}
+
+	response := &dto.BudgetReportResponse{
+		Date:     date.Format(budgetDateLayout),
+		Currency: currency,
+		Budgets:  []dto.BudgetActual{},
+	}
+	for _, budget := range budgets {
+		startDate := periodStart(budget.Period, date)
+		if startDate.Before(budget.StartDate) {
+			continue
+		}
+		endDate := nextPeriod(budget.Period, startDate).Add(-time.Nanosecond)
+
+		spent, err := spentIn(budget.Category, startDate, endDate)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get category summary")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+		}
+
+		// Earlier periods left (amount * periods - spent) to carry over
+		var carryover int64
+		if budget.Rollover && startDate.After(budget.StartDate) {
+			earlier, err := spentIn(budget.Category, budget.StartDate, startDate.Add(-time.Nanosecond))
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to get category summary")
+				return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+			}
+			carryover = budget.Amount*int64(periodsBetween(budget.Period, budget.StartDate, startDate)) - earlier
+		}
+
+		available := budget.Amount + carryover
+		percentUsed := 100.0
+		if available > 0 {
+			percentUsed = float64(spent) / float64(available) * 100
+		}
+
+		response.Budgets = append(response.Budgets, dto.BudgetActual{
+			BudgetID:    budget.ID,
+			Category:    budget.Category,
+			Period:      budget.Period,
+			PeriodStart: startDate.Format(budgetDateLayout),
+			PeriodEnd:   endDate.Format(budgetDateLayout),
+			Amount:      budget.Amount,
+			Carryover:   carryover,
+			Available:   available,
+			Spent:       spent,
+			Remaining:   available - spent,
+			PercentUsed: percentUsed,
+			OverBudget:  spent > available,
+		})
+		response.TotalBudgeted += available
+		response.TotalSpent += spent
+	}
+	response.TotalRemaining = response.TotalBudgeted - response.TotalSpent
+
+	return response, nil
+}
+
+// findBudget loads a budget the user may change: their own, or one of a
+// group they manage.
+func (s *budgetService) findBudget(userID, budgetID uuid.UUID) (*models.Budget, error) {
+	budget, err := s.budgetRepo.FindByID(budgetID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "BUDGET_NOT_FOUND", Message: "Budget not found"}
+	}
+
+	if budget.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *budget.GroupID)
+		if err != nil || userGroup.Status != "active" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		if userGroup.Role != "manager" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group budgets"}
+		}
+	} else if budget.UserID == nil || *budget.UserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	return budget, nil
+}
+
+// fillBudget validates a request and copies it onto the budget. A scope has
+// at most one budget per category and period.
+func (s *budgetService) fillBudget(userID uuid.UUID, budget *models.Budget, req dto.BudgetRequest) error {
+	category, err := resolveCategory(s.categoryRepo, "category", userID, budget.GroupID, req.Category)
+	if err != nil {
+		return err
+	}
+
+	var existing []models.Budget
+	if budget.GroupID != nil {
+		existing, err = s.budgetRepo.FindByGroup(*budget.GroupID)
+	} else {
+		existing, err = s.budgetRepo.FindByUser(userID)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get budgets")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to save budget"}
+	}
+	for _, other := range existing {
+		if other.ID != budget.ID && other.Period == req.Period && strings.EqualFold(other.Category, category) {
+			return &errors.AppError{Code: "BUDGET_EXISTS", Message: "There is already a " + req.Period + " budget for " + category}
+		}
+	}
+
+	startDate := budget.StartDate
+	if req.StartDate != "" {
+		startDate, err = time.Parse(budgetDateLayout, req.StartDate)
+		if err != nil {
+			return &errors.AppError{Code: "INVALID_REQUEST", Message: "Start date must be formatted as YYYY-MM-DD"}
+		}
+	} else if startDate.IsZero() {
+		startDate = time.Now().UTC()
+	}
+
+	budget.Category = category
+	budget.Period = req.Period
+	budget.Amount = req.Amount
+	budget.Rollover = req.Rollover
+	budget.StartDate = periodStart(req.Period, startDate)
+
+	return nil
+}
+
+func (s *budgetService) ownerCurrency(userID, groupID *uuid.UUID) (string, error) {
+	if groupID != nil {
+		group, err := s.groupRepo.FindByID(*groupID)
+		if err != nil {
+			return "", &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+		}
+		return group.Currency, nil
+	}
+
+	user, err := s.userRepo.FindByID(*userID)
+	if err != nil {
+		return "", &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+	return user.Currency, nil
+}
+
+// periodStart returns the start of the budget period containing t, in UTC.
+// Weeks start on Monday.
+func periodStart(period string, t time.Time) time.Time {
+	t = t.UTC()
+	switch period {
+	case "weekly":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	case "yearly":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+func nextPeriod(period string, start time.Time) time.Time {
+	switch period {
+	case "weekly":
+		return start.AddDate(0, 0, 7)
+	case "yearly":
+		return start.AddDate(1, 0, 0)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// periodsBetween counts the whole periods from one period start to another.
+func periodsBetween(period string, from, to time.Time) int {
+	switch period {
+	case "weekly":
+		return int(to.Sub(from).Hours()) / (24 * 7)
+	case "yearly":
+		return to.Year() - from.Year()
+	default:
+		return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+	}
+}
+
+func mapBudgetToResponse(budget *models.Budget, currency string) *dto.BudgetResponse {
+	return &dto.BudgetResponse{
+		ID:              budget.ID,
+		UserID:          budget.UserID,
+		GroupID:         budget.GroupID,
+		Category:        budget.Category,
+		Period:          budget.Period,
+		Amount:          budget.Amount,
+		FormattedAmount: formatAmount(budget.Amount, currency),
+		Currency:        currency,
+		Rollover:        budget.Rollover,
+		StartDate:       budget.StartDate.UTC().Format(budgetDateLayout),
+		CreatedBy:       budget.CreatedBy,
+		CreatedAt:       budget.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapBudgetsToResponse(budgets []models.Budget, currency string) []dto.BudgetResponse {
+	var response []dto.BudgetResponse
+	for _, budget := range budgets {
+		response = append(response, *mapBudgetToResponse(&budget, currency))
+	}
+	return response
+}
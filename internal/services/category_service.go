@@ -0,0 +1,496 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// systemCategories are seeded on startup and visible to every user and group.
+var systemCategories = []models.Category{
+	{Kind: "category", Name: "food", Icon: "utensils", Color: "#F59E0B"},
+	{Kind: "category", Name: "groceries", Icon: "shopping-cart", Color: "#84CC16"},
+	{Kind: "category", Name: "transport", Icon: "bus", Color: "#3B82F6"},
+	{Kind: "category", Name: "housing", Icon: "home", Color: "#8B5CF6"},
+	{Kind: "category", Name: "utilities", Icon: "bolt", Color: "#06B6D4"},
+	{Kind: "category", Name: "health", Icon: "heart", Color: "#EF4444"},
+	{Kind: "category", Name: "education", Icon: "book", Color: "#6366F1"},
+	{Kind: "category", Name: "entertainment", Icon: "film", Color: "#EC4899"},
+	{Kind: "category", Name: "shopping", Icon: "bag", Color: "#F97316"},
+	{Kind: "category", Name: "travel", Icon: "plane", Color: "#14B8A6"},
+	{Kind: "category", Name: "income", Icon: "wallet", Color: "#22C55E"},
+	{Kind: "category", Name: "other", Icon: "dots", Color: "#6B7280"},
+	{Kind: "source", Name: "salary", Icon: "briefcase", Color: "#22C55E"},
+	{Kind: "source", Name: "business", Icon: "store", Color: "#0EA5E9"},
+	{Kind: "source", Name: "gift", Icon: "gift", Color: "#EC4899"},
+	{Kind: "source", Name: "investment", Icon: "chart", Color: "#8B5CF6"},
+	{Kind: "source", Name: "cash", Icon: "money", Color: "#84CC16"},
+	{Kind: "source", Name: "bank", Icon: "bank", Color: "#3B82F6"},
+	{Kind: "source", Name: "card", Icon: "credit-card", Color: "#F59E0B"},
+	{Kind: "source", Name: "mobile_money", Icon: "phone", Color: "#F97316"},
+	{Kind: "source", Name: "other", Icon: "dots", Color: "#6B7280"},
+}
+
+// reservedCategoryNames are written by the services themselves (transfers,
+// contributions, expense payments, reimbursements, settlements between
+// members, shopping list checkouts) and cannot be chosen by clients.
+var reservedCategoryNames = map[string]bool{
+	"transfer":            true,
+	"member_contribution": true,
+	"external_income":     true,
+	"group_transfer":      true,
+	"member":              true,
+	"expense_payment":     true,
+	"reimbursement":       true,
+	"settlement":          true,
+	"shopping_list":       true,
+}
+
+type CategoryService interface {
+	CreatePersonalCategory(userID uuid.UUID, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	CreateGroupCategory(userID, groupID uuid.UUID, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error)
+	GetPersonalCategories(userID uuid.UUID, filter dto.CategoryFilter) ([]dto.CategoryResponse, error)
+	GetGroupCategories(userID, groupID uuid.UUID, filter dto.CategoryFilter) ([]dto.CategoryResponse, error)
+	UpdateCategory(userID, categoryID uuid.UUID, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
+	MergeCategories(userID, targetID uuid.UUID, req dto.MergeCategoriesRequest) (*dto.CategoryResponse, error)
+	DeleteCategory(userID, categoryID uuid.UUID) error
+	SeedSystemCategories() error
+}
+
+type categoryService struct {
+	categoryRepo repositories.CategoryRepository
+	groupRepo    repositories.GroupRepository
+	auditRepo    repositories.AuditLogRepository
+}
+
+func NewCategoryService(
+	categoryRepo repositories.CategoryRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+) CategoryService {
+	return &categoryService{
+		categoryRepo: categoryRepo,
+		groupRepo:    groupRepo,
+		auditRepo:    auditRepo,
+	}
+}
+
+func (s *categoryService) CreatePersonalCategory(userID uuid.UUID, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	category := &models.Category{
+		Name:      strings.TrimSpace(req.Name),
+		Kind:      req.Kind,
+		Icon:      req.Icon,
+		Color:     req.Color,
+		UserID:    &userID,
+		CreatedBy: &userID,
+	}
+
+	return s.createCategory(userID, category, req.ParentID)
+}
+
+func (s *categoryService) CreateGroupCategory(userID, groupID uuid.UUID, req dto.CreateCategoryRequest) (*dto.CategoryResponse, error) {
+	// Only managers can change the group catalog
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group categories"}
+	}
+
+	category := &models.Category{
+		Name:      strings.TrimSpace(req.Name),
+		Kind:      req.Kind,
+		Icon:      req.Icon,
+		Color:     req.Color,
+		GroupID:   &groupID,
+		CreatedBy: &userID,
+	}
+
+	return s.createCategory(userID, category, req.ParentID)
+}
+
+func (s *categoryService) createCategory(userID uuid.UUID, category *models.Category, parentID *uuid.UUID) (*dto.CategoryResponse, error) {
+	if err := s.checkNameAvailable(category, category.Name); err != nil {
+		return nil, err
+	}
+
+	if parentID != nil {
+		if err := s.validateParent(category, *parentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = parentID
+	}
+
+	if err := s.categoryRepo.Create(category); err != nil {
+		log.Error().Err(err).Msg("Failed to create category")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create category"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "category",
+		EntityID:    category.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"name": category.Name, "kind": category.Kind},
+		PerformedBy: userID,
+		GroupID:     category.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapCategoryToResponse(category), nil
+}
+
+func (s *categoryService) GetPersonalCategories(userID uuid.UUID, filter dto.CategoryFilter) ([]dto.CategoryResponse, error) {
+	categories, err := s.categoryRepo.FindVisible(filter.Kind, &userID, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal categories")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get categories"}
+	}
+
+	return mapCategoriesToResponse(categories), nil
+}
+
+func (s *categoryService) GetGroupCategories(userID, groupID uuid.UUID, filter dto.CategoryFilter) ([]dto.CategoryResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	categories, err := s.categoryRepo.FindVisible(filter.Kind, nil, &groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group categories")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get categories"}
+	}
+
+	return mapCategoriesToResponse(categories), nil
+}
+
+func (s *categoryService) UpdateCategory(userID, categoryID uuid.UUID, req dto.UpdateCategoryRequest) (*dto.CategoryResponse, error) {
+	category, err := s.categoryRepo.FindByID(categoryID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "CATEGORY_NOT_FOUND", Message: "Category not found"}
+	}
+
+	if err := s.checkCanManage(userID, category); err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]interface{})
+	oldName := category.Name
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != category.Name {
+			if !strings.EqualFold(name, category.Name) {
+				if err := s.checkNameAvailable(category, name); err != nil {
+					return nil, err
+				}
+			}
+			changes["name"] = map[string]interface{}{"old": category.Name, "new": name}
+			category.Name = name
+		}
+	}
+
+	if req.Icon != nil && *req.Icon != category.Icon {
+		changes["icon"] = map[string]interface{}{"old": category.Icon, "new": *req.Icon}
+		category.Icon = *req.Icon
+	}
+
+	if req.Color != nil && *req.Color != category.Color {
+		changes["color"] = map[string]interface{}{"old": category.Color, "new": *req.Color}
+		category.Color = *req.Color
+	}
+
+	if req.ClearParent {
+		if category.ParentID != nil {
+			changes["parent_id"] = map[string]interface{}{"old": category.ParentID, "new": nil}
+			category.ParentID = nil
+		}
+	} else if req.ParentID != nil && (category.ParentID == nil || *category.ParentID != *req.ParentID) {
+		if err := s.validateParent(category, *req.ParentID); err != nil {
+			return nil, err
+		}
+		changes["parent_id"] = map[string]interface{}{"old": category.ParentID, "new": *req.ParentID}
+		category.ParentID = req.ParentID
+	}
+
+	// Renaming rewrites the records that used the old name
+	if _, renamed := changes["name"]; renamed {
+		err = s.categoryRepo.Rename(category, oldName)
+	} else {
+		err = s.categoryRepo.Update(category)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to update category")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update category"}
+	}
+
+	if len(changes) > 0 {
+		auditLog := &models.AuditLog{
+			Entity:      "category",
+			EntityID:    category.ID,
+			Action:      "update",
+			Changes:     changes,
+			PerformedBy: userID,
+			GroupID:     category.GroupID,
+		}
+
+		if err := s.auditRepo.Create(auditLog); err != nil {
+			log.Error().Err(err).Msg("Failed to create audit log")
+		}
+	}
+
+	return mapCategoryToResponse(category), nil
+}
+
+func (s *categoryService) MergeCategories(userID, targetID uuid.UUID, req dto.MergeCategoriesRequest) (*dto.CategoryResponse, error) {
+	if len(req.SourceIDs) == 0 && len(req.Names) == 0 {
+		return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Nothing to merge"}
+	}
+
+	target, err := s.categoryRepo.FindByID(targetID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "CATEGORY_NOT_FOUND", Message: "Category not found"}
+	}
+
+	// History is rewritten in the target's scope. System entries have no
+	// scope of their own, so the caller picks one.
+	scopeUserID, scopeGroupID := target.UserID, target.GroupID
+	if target.IsSystem {
+		scopeUserID, scopeGroupID = &userID, req.GroupID
+		if req.GroupID != nil {
+			scopeUserID = nil
+			userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *req.GroupID)
+			if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+				return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group categories"}
+			}
+		}
+	} else if err := s.checkCanManage(userID, target); err != nil {
+		return nil, err
+	}
+	scope := &models.Category{UserID: scopeUserID, GroupID: scopeGroupID}
+
+	var sources []models.Category
+	for _, sourceID := range uniqueIDs(req.SourceIDs) {
+		if sourceID == target.ID {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Cannot merge a category into itself"}
+		}
+
+		source, err := s.categoryRepo.FindByID(sourceID)
+		if err != nil {
+			return nil, &errors.AppError{Code: "CATEGORY_NOT_FOUND", Message: "Category not found"}
+		}
+
+		if source.IsSystem || source.Kind != target.Kind || !sameCategoryScope(source, scope) {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Only custom entries of the same kind and scope can be merged"}
+		}
+
+		sources = append(sources, *source)
+	}
+
+	if err := s.categoryRepo.Merge(target, scopeUserID, scopeGroupID, sources, req.Names); err != nil {
+		log.Error().Err(err).Msg("Failed to merge categories")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to merge categories"}
+	}
+
+	var mergedNames []string
+	for _, source := range sources {
+		mergedNames = append(mergedNames, source.Name)
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "category",
+		EntityID: target.ID,
+		Action:   "merge",
+		Changes: map[string]interface{}{
+			"into":       target.Name,
+			"categories": mergedNames,
+			"names":      req.Names,
+		},
+		PerformedBy: userID,
+		GroupID:     scopeGroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapCategoryToResponse(target), nil
+}
+
+func (s *categoryService) DeleteCategory(userID, categoryID uuid.UUID) error {
+	category, err := s.categoryRepo.FindByID(categoryID)
+	if err != nil {
+		return &errors.AppError{Code: "CATEGORY_NOT_FOUND", Message: "Category not found"}
+	}
+
+	if err := s.checkCanManage(userID, category); err != nil {
+		return err
+	}
+
+	if err := s.categoryRepo.Delete(category); err != nil {
+		log.Error().Err(err).Msg("Failed to delete category")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete category"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "category",
+		EntityID:    category.ID,
+		Action:      "delete",
+		Changes:     map[string]interface{}{"name": category.Name, "kind": category.Kind},
+		PerformedBy: userID,
+		GroupID:     category.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+func (s *categoryService) SeedSystemCategories() error {
+	defaults := make([]models.Category, len(systemCategories))
+	copy(defaults, systemCategories)
+	return s.categoryRepo.EnsureSystem(defaults)
+}
+
+// checkCanManage allows owners to change personal entries and managers to
+// change group entries. System entries are read-only.
+func (s *categoryService) checkCanManage(userID uuid.UUID, category *models.Category) error {
+	if category.IsSystem {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "System categories cannot be changed"}
+	}
+
+	if category.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *category.GroupID)
+		if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group categories"}
+		}
+		return nil
+	}
+
+	if category.UserID == nil || *category.UserID != userID {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+	return nil
+}
+
+func (s *categoryService) checkNameAvailable(category *models.Category, name string) error {
+	if name == "" {
+		return &errors.AppError{Code: "INVALID_REQUEST", Message: "Name is required"}
+	}
+
+	if reservedCategoryNames[strings.ToLower(name)] {
+		return &errors.AppError{Code: "CATEGORY_RESERVED", Message: "This name is reserved"}
+	}
+
+	existing, err := s.categoryRepo.FindByName(category.Kind, category.UserID, category.GroupID, name)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check category name")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to save category"}
+	}
+	if existing != nil && existing.ID != category.ID {
+		return &errors.AppError{Code: "CATEGORY_EXISTS", Message: "A category with this name already exists"}
+	}
+
+	return nil
+}
+
+// validateParent checks that the parent is visible from the category's scope,
+// has the same kind and would not create a cycle.
+func (s *categoryService) validateParent(category *models.Category, parentID uuid.UUID) error {
+	parent, err := s.categoryRepo.FindByID(parentID)
+	if err != nil {
+		return &errors.AppError{Code: "CATEGORY_NOT_FOUND", Message: "Parent category not found"}
+	}
+
+	if parent.Kind != category.Kind {
+		return &errors.AppError{Code: "INVALID_PARENT", Message: "Parent must be of the same kind"}
+	}
+
+	if !parent.IsSystem && !sameCategoryScope(parent, category) {
+		return &errors.AppError{Code: "INVALID_PARENT", Message: "Parent is not available in this scope"}
+	}
+
+	// Walk up from the parent; reaching the category itself means a cycle
+	for current := parent; ; {
+		if current.ID == category.ID {
+			return &errors.AppError{Code: "INVALID_PARENT", Message: "A category cannot be its own ancestor"}
+		}
+		if current.ParentID == nil {
+			return nil
+		}
+		current, err = s.categoryRepo.FindByID(*current.ParentID)
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+func sameCategoryScope(a, b *models.Category) bool {
+	if a.GroupID != nil || b.GroupID != nil {
+		return a.GroupID != nil && b.GroupID != nil && *a.GroupID == *b.GroupID
+	}
+	return a.UserID != nil && b.UserID != nil && *a.UserID == *b.UserID
+}
+
+// resolveCategory checks a client-supplied category or source name against
+// the catalog visible from the given scope and returns the catalog spelling.
+func resolveCategory(categoryRepo repositories.CategoryRepository, kind string, userID uuid.UUID, groupID *uuid.UUID, name string) (string, error) {
+	var category *models.Category
+	var err error
+	if !reservedCategoryNames[strings.ToLower(strings.TrimSpace(name))] {
+		if groupID != nil {
+			category, err = categoryRepo.FindByName(kind, nil, groupID, name)
+		} else {
+			category, err = categoryRepo.FindByName(kind, &userID, nil, name)
+		}
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to look up category")
+			return "", &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to validate " + kind}
+		}
+	}
+
+	if category == nil {
+		if kind == "source" {
+			return "", &errors.AppError{Code: "INVALID_SOURCE", Message: "Unknown source: " + name}
+		}
+		return "", &errors.AppError{Code: "INVALID_CATEGORY", Message: "Unknown category: " + name}
+	}
+
+	return category.Name, nil
+}
+
+func mapCategoryToResponse(category *models.Category) *dto.CategoryResponse {
+	return &dto.CategoryResponse{
+		ID:        category.ID,
+		Name:      category.Name,
+		Kind:      category.Kind,
+		Icon:      category.Icon,
+		Color:     category.Color,
+		ParentID:  category.ParentID,
+		IsSystem:  category.IsSystem,
+		UserID:    category.UserID,
+		GroupID:   category.GroupID,
+		CreatedAt: category.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapCategoriesToResponse(categories []models.Category) []dto.CategoryResponse {
+	var response []dto.CategoryResponse
+	for _, category := range categories {
+		response = append(response, *mapCategoryToResponse(&category))
+	}
+	return response
+}
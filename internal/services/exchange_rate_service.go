@@ -0,0 +1,218 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"balanca/pkg/money"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const rateDateLayout = "2006-01-02"
+
+type ExchangeRateService interface {
+	ImportCSV(content io.Reader, source string) (*dto.ImportExchangeRatesResponse, error)
+	GetRate(filter dto.ExchangeRateFilter) (*dto.ExchangeRateResponse, error)
+}
+
+type exchangeRateService struct {
+	rateRepo repositories.ExchangeRateRepository
+}
+
+func NewExchangeRateService(rateRepo repositories.ExchangeRateRepository) ExchangeRateService {
+	return &exchangeRateService{rateRepo: rateRepo}
+}
+
+// ImportCSV loads rates from a CSV file with a header row naming the columns
+// date, base, quote and rate (in any order). Invalid rows are reported and
+// skipped; valid rows are upserted.
+func (s *exchangeRateService) ImportCSV(content io.Reader, source string) (*dto.ImportExchangeRatesResponse, error) {
+	reader := csv.NewReader(content)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, &errors.AppError{Code: "INVALID_FILE", Message: "Missing CSV header"}
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		name = strings.TrimSuffix(name, "_currency")
+		columns[name] = i
+	}
+	for _, required := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[required]; !ok {
+			return nil, &errors.AppError{Code: "INVALID_FILE", Message: "CSV header must contain date, base, quote and rate"}
+		}
+	}
+
+	response := &dto.ImportExchangeRatesResponse{}
+	// Later rows win when a file repeats a pair and date
+	byKey := make(map[string]models.ExchangeRate)
+	var order []string
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			response.Skipped++
+			response.Errors = append(response.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+
+		rate, err := parseRateRecord(record, columns)
+		if err != nil {
+			response.Skipped++
+			response.Errors = append(response.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		rate.Source = source
+
+		key := rate.BaseCurrency + rate.QuoteCurrency + rate.EffectiveDate.Format(rateDateLayout)
+		if _, seen := byKey[key]; !seen {
+			order = append(order, key)
+		}
+		byKey[key] = *rate
+	}
+
+	rates := make([]models.ExchangeRate, 0, len(order))
+	for _, key := range order {
+		rates = append(rates, byKey[key])
+	}
+
+	if err := s.rateRepo.Upsert(rates); err != nil {
+		log.Error().Err(err).Msg("Failed to import exchange rates")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to import exchange rates"}
+	}
+
+	response.Imported = len(rates)
+	return response, nil
+}
+
+func (s *exchangeRateService) GetRate(filter dto.ExchangeRateFilter) (*dto.ExchangeRateResponse, error) {
+	date := time.Now().UTC()
+	if filter.Date != "" {
+		parsed, err := time.Parse(rateDateLayout, filter.Date)
+		if err != nil {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Date must be formatted as YYYY-MM-DD"}
+		}
+		date = parsed
+	}
+
+	base := money.NormalizeCode(filter.Base)
+	quote := money.NormalizeCode(filter.Quote)
+
+	rate, effectiveDate, inverse, err := lookupRate(s.rateRepo, base, quote, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ExchangeRateResponse{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          formatRate(rate),
+		EffectiveDate: effectiveDate.Format(rateDateLayout),
+		Inverse:       inverse,
+	}, nil
+}
+
+func parseRateRecord(record []string, columns map[string]int) (*models.ExchangeRate, error) {
+	field := func(name string) string {
+		if i := columns[name]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	date, err := time.Parse(rateDateLayout, field("date"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q", field("date"))
+	}
+
+	base := strings.ToUpper(field("base"))
+	quote := strings.ToUpper(field("quote"))
+	if !money.IsSupported(base) || !money.IsSupported(quote) {
+		return nil, fmt.Errorf("unsupported currency pair %s/%s", base, quote)
+	}
+	if base == quote {
+		return nil, fmt.Errorf("base and quote currencies are the same")
+	}
+
+	rate, err := money.ParseRate(field("rate"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          formatRate(rate),
+		EffectiveDate: date,
+	}, nil
+}
+
+// lookupRate finds the rate effective on the date for base -> quote, falling
+// back to the inverse of the quote -> base rate when only that one is known.
+func lookupRate(rateRepo repositories.ExchangeRateRepository, base, quote string, date time.Time) (*big.Rat, time.Time, bool, error) {
+	if base == quote {
+		return big.NewRat(1, 1), date, false, nil
+	}
+
+	direct, err := rateRepo.FindLatest(base, quote, date)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to look up exchange rate")
+		return nil, time.Time{}, false, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to look up exchange rate"}
+	}
+
+	inverse, err := rateRepo.FindLatest(quote, base, date)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to look up exchange rate")
+		return nil, time.Time{}, false, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to look up exchange rate"}
+	}
+
+	// Prefer the direct rate unless the inverse one is more recent
+	useInverse := direct == nil || (inverse != nil && inverse.EffectiveDate.After(direct.EffectiveDate))
+	if direct == nil && inverse == nil {
+		return nil, time.Time{}, false, &errors.AppError{
+			Code:    "EXCHANGE_RATE_NOT_FOUND",
+			Message: fmt.Sprintf("No %s/%s exchange rate on or before %s", base, quote, date.Format(rateDateLayout)),
+		}
+	}
+
+	found := direct
+	if useInverse {
+		found = inverse
+	}
+
+	rate, err := money.ParseRate(found.Rate)
+	if err != nil {
+		log.Error().Err(err).Str("rate", found.Rate).Msg("Stored exchange rate is invalid")
+		return nil, time.Time{}, false, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to look up exchange rate"}
+	}
+	if useInverse {
+		rate.Inv(rate)
+	}
+
+	return rate, found.EffectiveDate, useInverse, nil
+}
+
+// formatRate renders a rate as a plain decimal with up to 12 places.
+func formatRate(rate *big.Rat) string {
+	value := rate.FloatString(12)
+	if strings.Contains(value, ".") {
+		value = strings.TrimRight(strings.TrimRight(value, "0"), ".")
+	}
+	return value
+}
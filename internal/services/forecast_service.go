@@ -0,0 +1,245 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultForecastDays   = 30
+	defaultForecastMonths = 3
+)
+
+// forecastPriority orders the expenses due on the same day: the most
+// important ones are paid first.
+var forecastPriority = map[string]int{"high": 0, "medium": 1, "low": 2}
+
+type ForecastService interface {
+	GetPersonalForecast(userID uuid.UUID, filter dto.ForecastFilter) (*dto.ForecastResponse, error)
+	GetGroupForecast(userID, groupID uuid.UUID, filter dto.ForecastFilter) (*dto.ForecastResponse, error)
+}
+
+type forecastService struct {
+	transactionRepo repositories.TransactionRepository
+	expenseRepo     repositories.PlannedExpenseRepository
+	userRepo        repositories.UserRepository
+	groupRepo       repositories.GroupRepository
+}
+
+func NewForecastService(
+	transactionRepo repositories.TransactionRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+) ForecastService {
+	return &forecastService{
+		transactionRepo: transactionRepo,
+		expenseRepo:     expenseRepo,
+		userRepo:        userRepo,
+		groupRepo:       groupRepo,
+	}
+}
+
+func (s *forecastService) GetPersonalForecast(userID uuid.UUID, filter dto.ForecastFilter) (*dto.ForecastResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
+	expenses, err := s.expenseRepo.FindOpenByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get open expenses")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate forecast"}
+	}
+
+	return s.forecast("USER", userID, user.Balance, user.Currency, expenses, filter)
+}
+
+func (s *forecastService) GetGroupForecast(userID, groupID uuid.UUID, filter dto.ForecastFilter) (*dto.ForecastResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	expenses, err := s.expenseRepo.FindOpenByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get open group expenses")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate forecast"}
+	}
+
+	return s.forecast("GROUP", groupID, group.Balance, group.Currency, expenses, filter)
+}
+
+// forecast projects the balance from today over the requested number of
+// days. Income already received today is in the balance, so the average
+// daily income by source, taken over the last months, is added from
+// tomorrow. Each open expense is paid in full on its due date, overdue ones
+// today, in priority order within a day. The purchase being considered is
+// paid last on its day so it only counts as affordable after everything
+// already planned.
+func (s *forecastService) forecast(ownerType string, ownerID uuid.UUID, balance int64, currency string, expenses []models.PlannedExpense, filter dto.ForecastFilter) (*dto.ForecastResponse, error) {
+	days := filter.Days
+	if days == 0 {
+		days = defaultForecastDays
+	}
+	months := filter.Months
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+
+	today := startOfDay(time.Now())
+	endDate := today.AddDate(0, 0, days-1)
+
+	var hypothetical *dto.ForecastExpense
+	if filter.Amount > 0 {
+		date := today
+		if filter.Date != "" {
+			parsed, err := time.Parse(budgetDateLayout, filter.Date)
+			if err != nil {
+				return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Date must be formatted as YYYY-MM-DD"}
+			}
+			if parsed.Before(today) || parsed.After(endDate) {
+				return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Date must fall within the forecast"}
+			}
+			date = parsed
+		}
+		item := filter.Item
+		if item == "" {
+			item = "Planned purchase"
+		}
+		hypothetical = &dto.ForecastExpense{
+			Item:         item,
+			DueDate:      date.Format(budgetDateLayout),
+			Date:         date.Format(budgetDateLayout),
+			Amount:       filter.Amount,
+			Hypothetical: true,
+		}
+	}
+
+	// Average the income by source over the history window
+	historyStart := today.AddDate(0, -months, 0)
+	historyDays := int64(today.Sub(historyStart).Hours() / 24)
+	sources, err := s.transactionRepo.GetSourceSummary(ownerType, ownerID, historyStart, today.Add(-time.Nanosecond))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get income history")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate forecast"}
+	}
+
+	response := &dto.ForecastResponse{
+		Currency:        currency,
+		StartDate:       today.Format(budgetDateLayout),
+		EndDate:         endDate.Format(budgetDateLayout),
+		StartingBalance: balance,
+		IncomeMonths:    months,
+		Income:          []dto.ForecastIncome{},
+		Expenses:        []dto.ForecastExpense{},
+		Unscheduled:     []dto.ForecastExpense{},
+		Days:            []dto.ForecastDay{},
+	}
+
+	var incomeTotal int64
+	for source, total := range sources {
+		incomeTotal += total
+		response.Income = append(response.Income, dto.ForecastIncome{
+			Source:         source,
+			Total:          total,
+			MonthlyAverage: total / int64(months),
+		})
+	}
+	sort.Slice(response.Income, func(i, j int) bool {
+		if response.Income[i].Total != response.Income[j].Total {
+			return response.Income[i].Total > response.Income[j].Total
+		}
+		return response.Income[i].Source < response.Income[j].Source
+	})
+
+	// Schedule the open expenses on their payment day
+	scheduled := make(map[string][]dto.ForecastExpense)
+	for _, expense := range expenses {
+		entry := dto.ForecastExpense{
+			ExpenseID: &expense.ID,
+			Item:      expense.Item,
+			Priority:  expense.Priority,
+			Status:    expense.Status,
+			Amount:    expense.EstimatedPrice - expense.PaidAmount,
+		}
+		if entry.Amount <= 0 {
+			continue
+		}
+		if expense.DueDate == nil {
+			response.Unscheduled = append(response.Unscheduled, entry)
+			continue
+		}
+
+		dueDate := startOfDay(*expense.DueDate)
+		if dueDate.After(endDate) {
+			continue
+		}
+		entry.DueDate = dueDate.Format(budgetDateLayout)
+		entry.Date = entry.DueDate
+		if dueDate.Before(today) {
+			entry.Overdue = true
+			entry.Date = today.Format(budgetDateLayout)
+		}
+		scheduled[entry.Date] = append(scheduled[entry.Date], entry)
+	}
+	for date := range scheduled {
+		entries := scheduled[date]
+		sort.SliceStable(entries, func(i, j int) bool {
+			return forecastPriority[entries[i].Priority] < forecastPriority[entries[j].Priority]
+		})
+	}
+	if hypothetical != nil {
+		scheduled[hypothetical.Date] = append(scheduled[hypothetical.Date], *hypothetical)
+	}
+
+	// Walk the days, spreading the income so the whole-unit amounts add up
+	response.LowestBalance = balance
+	response.LowestBalanceDate = response.StartDate
+	for i := 0; i < days; i++ {
+		date := today.AddDate(0, 0, i).Format(budgetDateLayout)
+		day := dto.ForecastDay{Date: date}
+		if i > 0 && historyDays > 0 {
+			day.Income = incomeTotal*int64(i)/historyDays - incomeTotal*int64(i-1)/historyDays
+		}
+		balance += day.Income
+
+		for _, entry := range scheduled[date] {
+			before := balance
+			balance -= entry.Amount
+			day.Expenses += entry.Amount
+			entry.BalanceAfter = balance
+			entry.PushesNegative = before >= 0 && balance < 0
+			response.Expenses = append(response.Expenses, entry)
+		}
+
+		day.Balance = balance
+		day.Negative = balance < 0
+		if balance < response.LowestBalance {
+			response.LowestBalance = balance
+			response.LowestBalanceDate = date
+		}
+		if day.Negative && response.FirstNegativeDate == nil {
+			response.FirstNegativeDate = &day.Date
+		}
+		response.Days = append(response.Days, day)
+	}
+
+	response.EndingBalance = balance
+	response.Affordable = response.FirstNegativeDate == nil
+
+	return response, nil
+}
@@ -5,6 +5,7 @@ import (
 	"balanca/internal/models"
 	"balanca/internal/repositories"
 	"balanca/pkg/errors"
+	"balanca/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,27 +28,34 @@ type GroupService interface {
 }
 
 type groupService struct {
-	groupRepo repositories.GroupRepository
-	userRepo  repositories.UserRepository
-	auditRepo repositories.AuditLogRepository
-	db        *gorm.DB
+	groupRepo       repositories.GroupRepository
+	userRepo        repositories.UserRepository
+	auditRepo       repositories.AuditLogRepository
+	approvalService ApprovalService
+	db              *gorm.DB
 }
 
 func NewGroupService(
 	groupRepo repositories.GroupRepository,
 	userRepo repositories.UserRepository,
 	auditRepo repositories.AuditLogRepository,
+	approvalService ApprovalService,
 	db *gorm.DB,
 ) GroupService {
 	return &groupService{
-		groupRepo: groupRepo,
-		userRepo:  userRepo,
-		auditRepo: auditRepo,
-		db:        db,
+		groupRepo:       groupRepo,
+		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		approvalService: approvalService,
+		db:              db,
 	}
 }
 
 func (s *groupService) CreateGroup(userID uuid.UUID, req dto.CreateGroupRequest) (*dto.GroupResponse, error) {
+	if !money.IsSupported(money.NormalizeCode(req.Currency)) {
+		return nil, &errors.AppError{Code: "UNSUPPORTED_CURRENCY", Message: "Unsupported currency"}
+	}
+
 	// Start transaction
 	tx := s.db.Begin()
 	defer func() {
@@ -61,6 +69,7 @@ func (s *groupService) CreateGroup(userID uuid.UUID, req dto.CreateGroupRequest)
 		Name:        req.Name,
 		Description: req.Description,
 		Balance:     0,
+		Currency:    money.NormalizeCode(req.Currency),
 		CreatedBy:   userID,
 		IsActive:    true,
 	}
@@ -130,6 +139,7 @@ func (s *groupService) CreateGroup(userID uuid.UUID, req dto.CreateGroupRequest)
 				FirstName:   member.User.FirstName,
 				LastName:    member.User.LastName,
 				Balance:     member.User.Balance,
+				Currency:    member.User.Currency,
 				IsActive:    member.User.IsActive,
 				CreatedAt:   member.User.CreatedAt.Format(time.RFC3339),
 			},
@@ -142,6 +152,7 @@ func (s *groupService) CreateGroup(userID uuid.UUID, req dto.CreateGroupRequest)
 		Description: fullGroup.Description,
 		Balance:     fullGroup.Balance,
 		CreatedBy:   fullGroup.CreatedBy,
+		Currency:    fullGroup.Currency,
 		IsActive:    fullGroup.IsActive,
 		CreatedAt:   fullGroup.CreatedAt.Format(time.RFC3339),
 		Members:     members,
@@ -177,6 +188,7 @@ func (s *groupService) GetGroup(userID, groupID uuid.UUID) (*dto.GroupResponse,
 				FirstName:   member.User.FirstName,
 				LastName:    member.User.LastName,
 				Balance:     member.User.Balance,
+				Currency:    member.User.Currency,
 				IsActive:    member.User.IsActive,
 				CreatedAt:   member.User.CreatedAt.Format(time.RFC3339),
 			},
@@ -189,6 +201,7 @@ func (s *groupService) GetGroup(userID, groupID uuid.UUID) (*dto.GroupResponse,
 		Description: group.Description,
 		Balance:     group.Balance,
 		CreatedBy:   group.CreatedBy,
+		Currency:    group.Currency,
 		IsActive:    group.IsActive,
 		CreatedAt:   group.CreatedAt.Format(time.RFC3339),
 		Members:     members,
@@ -220,6 +233,7 @@ func (s *groupService) GetGroups(userID uuid.UUID) ([]dto.GroupResponse, error)
 					FirstName:   member.User.FirstName,
 					LastName:    member.User.LastName,
 					Balance:     member.User.Balance,
+					Currency:    member.User.Currency,
 					IsActive:    member.User.IsActive,
 					CreatedAt:   member.User.CreatedAt.Format(time.RFC3339),
 				},
@@ -232,6 +246,7 @@ func (s *groupService) GetGroups(userID uuid.UUID) ([]dto.GroupResponse, error)
 			Description: group.Description,
 			Balance:     group.Balance,
 			CreatedBy:   group.CreatedBy,
+			Currency:    group.Currency,
 			IsActive:    group.IsActive,
 			CreatedAt:   group.CreatedAt.Format(time.RFC3339),
 			Members:     members,
@@ -402,6 +417,11 @@ func (s *groupService) UpdateMemberRole(userID, groupID uuid.UUID, req dto.Updat
 		log.Error().Err(err).Msg("Failed to create audit log")
 	}
 
+	// A former manager can no longer decide on pending expenses
+	if oldRole == "manager" && req.Role != "manager" {
+		s.approvalService.ReevaluatePending(userID, groupID)
+	}
+
 	return nil
 }
 
@@ -436,6 +456,8 @@ func (s *groupService) RemoveMember(userID, groupID, targetUserID uuid.UUID) err
 		log.Error().Err(err).Msg("Failed to create audit log")
 	}
 
+	s.approvalService.ReevaluatePending(userID, groupID)
+
 	return nil
 }
 
@@ -462,6 +484,7 @@ func (s *groupService) GetPendingInvitations(userID uuid.UUID) ([]dto.GroupInvit
 				FirstName:   creator.FirstName,
 				LastName:    creator.LastName,
 				Balance:     creator.Balance,
+				Currency:    creator.Currency,
 				IsActive:    creator.IsActive,
 				CreatedAt:   creator.CreatedAt.Format(time.RFC3339),
 			},
@@ -520,6 +543,8 @@ func (s *groupService) LeaveGroup(userID, groupID uuid.UUID) error {
 		log.Error().Err(err).Msg("Failed to create audit log")
 	}
 
+	s.approvalService.ReevaluatePending(userID, groupID)
+
 	return nil
 }
 
@@ -0,0 +1,822 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/importers"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"balanca/pkg/money"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// importClaimTimeout is how long a commit may hold a batch before another
+// request may assume it died and take the batch over.
+const importClaimTimeout = 15 * time.Minute
+
+type ImportService interface {
+	CreatePersonalProfile(userID uuid.UUID, req dto.CreateImportProfileRequest) (*dto.ImportProfileResponse, error)
+	CreateGroupProfile(userID, groupID uuid.UUID, req dto.CreateImportProfileRequest) (*dto.ImportProfileResponse, error)
+	GetPersonalProfiles(userID uuid.UUID) ([]dto.ImportProfileResponse, error)
+	GetGroupProfiles(userID, groupID uuid.UUID) ([]dto.ImportProfileResponse, error)
+	DeleteProfile(userID, profileID uuid.UUID) error
+	PreviewPersonalImport(userID uuid.UUID, req dto.PreviewImportRequest, fileName string, content io.Reader) (*dto.ImportBatchResponse, error)
+	PreviewGroupImport(userID, groupID uuid.UUID, req dto.PreviewImportRequest, fileName string, content io.Reader) (*dto.ImportBatchResponse, error)
+	GetBatch(userID, batchID uuid.UUID) (*dto.ImportBatchResponse, error)
+	CommitBatch(userID, batchID uuid.UUID, req dto.CommitImportRequest) (*dto.ImportBatchResponse, error)
+}
+
+type importService struct {
+	importRepo         repositories.ImportRepository
+	transactionRepo    repositories.TransactionRepository
+	userRepo           repositories.UserRepository
+	groupRepo          repositories.GroupRepository
+	categoryRepo       repositories.CategoryRepository
+	auditRepo          repositories.AuditLogRepository
+	transactionService TransactionService
+}
+
+func NewImportService(
+	importRepo repositories.ImportRepository,
+	transactionRepo repositories.TransactionRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+	categoryRepo repositories.CategoryRepository,
+	auditRepo repositories.AuditLogRepository,
+	transactionService TransactionService,
+) ImportService {
+	return &importService{
+		importRepo:         importRepo,
+		transactionRepo:    transactionRepo,
+		userRepo:           userRepo,
+		groupRepo:          groupRepo,
+		categoryRepo:       categoryRepo,
+		auditRepo:          auditRepo,
+		transactionService: transactionService,
+	}
+}
+
+func (s *importService) CreatePersonalProfile(userID uuid.UUID, req dto.CreateImportProfileRequest) (*dto.ImportProfileResponse, error) {
+	profile, err := s.buildProfile(userID, nil, req)
+	if err != nil {
+		return nil, err
+	}
+	profile.UserID = &userID
+
+	return s.createProfile(profile)
+}
+
+func (s *importService) CreateGroupProfile(userID, groupID uuid.UUID, req dto.CreateImportProfileRequest) (*dto.ImportProfileResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	profile, err := s.buildProfile(userID, &groupID, req)
+	if err != nil {
+		return nil, err
+	}
+	profile.GroupID = &groupID
+
+	return s.createProfile(profile)
+}
+
+func (s *importService) buildProfile(userID uuid.UUID, groupID *uuid.UUID, req dto.CreateImportProfileRequest) (*models.ImportProfile, error) {
+	profile := &models.ImportProfile{
+		Name:              strings.TrimSpace(req.Name),
+		CreatedBy:         userID,
+		Delimiter:         req.Delimiter,
+		HasHeader:         req.HasHeader == nil || *req.HasHeader,
+		DateColumn:        req.DateColumn,
+		DateFormat:        req.DateFormat,
+		AmountColumn:      req.AmountColumn,
+		DebitColumn:       req.DebitColumn,
+		CreditColumn:      req.CreditColumn,
+		DescriptionColumn: req.DescriptionColumn,
+		CategoryColumn:    req.CategoryColumn,
+		SignConvention:    req.SignConvention,
+	}
+
+	if profile.Delimiter == "" {
+		profile.Delimiter = ","
+	}
+	if profile.DateFormat == "" {
+		profile.DateFormat = "YYYY-MM-DD"
+	}
+	if profile.SignConvention == "" {
+		profile.SignConvention = importers.SignSigned
+	}
+
+	if _, err := importers.DateLayout(profile.DateFormat); err != nil {
+		return nil, &errors.AppError{Code: "INVALID_DATE_FORMAT", Message: "Date format must use YYYY, YY, MM and DD"}
+	}
+
+	if req.Locale != "" {
+		locale, ok := money.LookupLocale(req.Locale)
+		if !ok {
+			return nil, &errors.AppError{Code: "UNSUPPORTED_LOCALE", Message: "Unsupported locale"}
+		}
+		profile.Locale = locale.Tag
+	}
+
+	var err error
+	if req.DefaultCategory != "" {
+		profile.DefaultCategory, err = resolveCategory(s.categoryRepo, "category", userID, groupID, req.DefaultCategory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.DefaultSource != "" {
+		profile.DefaultSource, err = resolveCategory(s.categoryRepo, "source", userID, groupID, req.DefaultSource)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return profile, nil
+}
+
+func (s *importService) createProfile(profile *models.ImportProfile) (*dto.ImportProfileResponse, error) {
+	if err := s.importRepo.CreateProfile(profile); err != nil {
+		log.Error().Err(err).Msg("Failed to create import profile")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create import profile"}
+	}
+
+	return mapImportProfileToResponse(profile), nil
+}
+
+func (s *importService) GetPersonalProfiles(userID uuid.UUID) ([]dto.ImportProfileResponse, error) {
+	profiles, err := s.importRepo.FindProfilesByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get import profiles")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get import profiles"}
+	}
+
+	return mapImportProfilesToResponse(profiles), nil
+}
+
+func (s *importService) GetGroupProfiles(userID, groupID uuid.UUID) ([]dto.ImportProfileResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	profiles, err := s.importRepo.FindProfilesByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get import profiles")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get import profiles"}
+	}
+
+	return mapImportProfilesToResponse(profiles), nil
+}
+
+func (s *importService) DeleteProfile(userID, profileID uuid.UUID) error {
+	profile, err := s.importRepo.FindProfileByID(profileID)
+	if err != nil {
+		return &errors.AppError{Code: "PROFILE_NOT_FOUND", Message: "Import profile not found"}
+	}
+
+	if profile.GroupID != nil {
+		// Group profiles can be removed by their creator or a manager
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *profile.GroupID)
+		if err != nil || userGroup.Status != "active" || (userGroup.Role != "manager" && profile.CreatedBy != userID) {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+	} else if profile.UserID == nil || *profile.UserID != userID {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	if err := s.importRepo.DeleteProfile(profileID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete import profile")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete import profile"}
+	}
+
+	return nil
+}
+
+func (s *importService) PreviewPersonalImport(userID uuid.UUID, req dto.PreviewImportRequest, fileName string, content io.Reader) (*dto.ImportBatchResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
+	return s.preview(userID, nil, user.Currency, req, fileName, content)
+}
+
+func (s *importService) PreviewGroupImport(userID, groupID uuid.UUID, req dto.PreviewImportRequest, fileName string, content io.Reader) (*dto.ImportBatchResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	return s.preview(userID, &groupID, group.Currency, req, fileName, content)
+}
+
+// preview parses an uploaded statement into a batch of rows, marking the
+// ones that cannot be read or are already in the ledger. Nothing is posted.
+func (s *importService) preview(userID uuid.UUID, groupID *uuid.UUID, currency string, req dto.PreviewImportRequest, fileName string, content io.Reader) (*dto.ImportBatchResponse, error) {
+	format := req.Format
+	if format == "" {
+		format = importFormat(fileName)
+	}
+
+	var profile *models.ImportProfile
+	if req.ProfileID != "" {
+		profileID, err := uuid.Parse(req.ProfileID)
+		if err != nil {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Invalid profile ID"}
+		}
+
+		profile, err = s.importRepo.FindProfileByID(profileID)
+		if err != nil || !sameImportScope(profile, userID, groupID) {
+			return nil, &errors.AppError{Code: "PROFILE_NOT_FOUND", Message: "Import profile not found"}
+		}
+	}
+
+	var locale *money.Locale
+	if profile != nil {
+		if l, ok := money.LookupLocale(profile.Locale); ok {
+			locale = &l
+		}
+	}
+
+	var entries []importers.Entry
+	var err error
+	switch format {
+	case "ofx":
+		entries, err = importers.ParseOFX(content, currency)
+	case "qif":
+		options := importers.QIFOptions{Currency: currency, Locale: locale}
+		if profile != nil {
+			options.DayFirst = strings.HasPrefix(strings.ToUpper(profile.DateFormat), "DD")
+		}
+		entries, err = importers.ParseQIF(content, options)
+	default:
+		if profile == nil {
+			return nil, &errors.AppError{Code: "PROFILE_REQUIRED", Message: "An import profile is required for CSV statements"}
+		}
+
+		mapping := importers.CSVMapping{
+			HasHeader:         profile.HasHeader,
+			DateColumn:        profile.DateColumn,
+			DateFormat:        profile.DateFormat,
+			AmountColumn:      profile.AmountColumn,
+			DebitColumn:       profile.DebitColumn,
+			CreditColumn:      profile.CreditColumn,
+			DescriptionColumn: profile.DescriptionColumn,
+			CategoryColumn:    profile.CategoryColumn,
+			SignConvention:    profile.SignConvention,
+			Currency:          currency,
+			Locale:            locale,
+		}
+		if profile.Delimiter != "" {
+			mapping.Delimiter = []rune(profile.Delimiter)[0]
+		}
+		entries, err = importers.ParseCSV(content, mapping)
+	}
+	if err != nil {
+		return nil, &errors.AppError{Code: "INVALID_FILE", Message: "Could not read statement: " + err.Error()}
+	}
+	if len(entries) == 0 {
+		return nil, &errors.AppError{Code: "INVALID_FILE", Message: "Statement has no rows"}
+	}
+
+	batch := &models.ImportBatch{
+		UserID:   userID,
+		GroupID:  groupID,
+		Format:   format,
+		FileName: fileName,
+		Currency: currency,
+		Status:   "pending",
+		Rows:     buildImportRows(entries),
+	}
+	if profile != nil {
+		batch.ProfileID = &profile.ID
+	}
+
+	if err := s.markDuplicates(batch, batch.Rows); err != nil {
+		log.Error().Err(err).Msg("Failed to check for duplicate transactions")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to preview import"}
+	}
+
+	if err := s.importRepo.CreateBatch(batch); err != nil {
+		log.Error().Err(err).Msg("Failed to create import batch")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to preview import"}
+	}
+
+	return mapImportBatchToResponse(batch), nil
+}
+
+func (s *importService) GetBatch(userID, batchID uuid.UUID) (*dto.ImportBatchResponse, error) {
+	batch, err := s.findBatch(userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapImportBatchToResponse(batch), nil
+}
+
+// CommitBatch posts the selected rows, oldest first, through the regular
+// transaction logic so balances, categories and audit logs are handled as
+// for manual entries. Rows that fail keep their error and stay pending.
+func (s *importService) CommitBatch(userID, batchID uuid.UUID, req dto.CommitImportRequest) (*dto.ImportBatchResponse, error) {
+	found, err := s.findBatch(userID, batchID)
+	if err != nil {
+		return nil, err
+	}
+	recovered := found.Status == "committing"
+
+	// Claim the batch so a repeated request cannot post the same rows twice
+	claimed, err := s.importRepo.ClaimBatch(batchID, time.Now().Add(-importClaimTimeout))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to claim import batch")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to import rows"}
+	}
+	if !claimed {
+		return nil, &errors.AppError{Code: "IMPORT_IN_PROGRESS", Message: "This import is already being committed"}
+	}
+
+	// Reload the rows now that no other commit can change them
+	batch, err := s.importRepo.FindBatchByID(batchID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get import batch")
+		s.releaseBatch(batchID)
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to import rows"}
+	}
+
+	// A commit that died may have posted rows it did not get to mark
+	if recovered {
+		if err := s.recoverRows(batch); err != nil {
+			log.Error().Err(err).Msg("Failed to recover import rows")
+			s.releaseBatch(batchID)
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to import rows"}
+		}
+	}
+
+	var rows []*models.ImportRow
+	if len(req.RowIDs) > 0 {
+		byID := make(map[uuid.UUID]*models.ImportRow)
+		for i := range batch.Rows {
+			byID[batch.Rows[i].ID] = &batch.Rows[i]
+		}
+		for _, id := range uniqueIDs(req.RowIDs) {
+			row, ok := byID[id]
+			if !ok {
+				s.releaseBatch(batchID)
+				return nil, &errors.AppError{Code: "ROW_NOT_FOUND", Message: "Import row not found"}
+			}
+			if row.Status == "pending" || row.Status == "duplicate" {
+				rows = append(rows, row)
+			}
+		}
+	} else {
+		// Another upload of the same statement may have been posted since
+		// this one was previewed
+		if err := s.markDuplicates(batch, batch.Rows); err != nil {
+			log.Error().Err(err).Msg("Failed to check for duplicate transactions")
+			s.releaseBatch(batchID)
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to import rows"}
+		}
+
+		for i := range batch.Rows {
+			switch batch.Rows[i].Status {
+			case "pending":
+				rows = append(rows, &batch.Rows[i])
+			case "duplicate":
+				if err := s.importRepo.UpdateRow(&batch.Rows[i]); err != nil {
+					log.Error().Err(err).Msg("Failed to update import row")
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Date.Equal(*rows[j].Date) {
+			return rows[i].RowNumber < rows[j].RowNumber
+		}
+		return rows[i].Date.Before(*rows[j].Date)
+	})
+
+	var profile *models.ImportProfile
+	if batch.ProfileID != nil {
+		profile, _ = s.importRepo.FindProfileByID(*batch.ProfileID)
+	}
+
+	imported := 0
+	for _, row := range rows {
+		transaction, err := s.postRow(userID, batch, profile, row)
+		if err != nil {
+			if appErr, ok := err.(*errors.AppError); ok {
+				row.Error = appErr.Message
+			} else {
+				row.Error = "Failed to create transaction"
+			}
+		} else {
+			row.Status = "imported"
+			row.Error = ""
+			row.TransactionID = &transaction.ID
+			imported++
+		}
+
+		if err := s.importRepo.UpdateRow(row); err != nil {
+			log.Error().Err(err).Msg("Failed to update import row")
+		}
+	}
+
+	batch.Status = batchStatus(batch)
+	if err := s.importRepo.UpdateBatch(batch); err != nil {
+		log.Error().Err(err).Msg("Failed to update import batch")
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "import_batch",
+		EntityID:    batch.ID,
+		Action:      "commit",
+		Changes:     map[string]interface{}{"file_name": batch.FileName, "imported": imported},
+		PerformedBy: userID,
+		GroupID:     batch.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapImportBatchToResponse(batch), nil
+}
+
+func (s *importService) postRow(userID uuid.UUID, batch *models.ImportBatch, profile *models.ImportProfile, row *models.ImportRow) (*dto.TransactionResponse, error) {
+	category := row.Category
+	source := "bank"
+	if profile != nil {
+		if category == "" {
+			category = profile.DefaultCategory
+		}
+		if profile.DefaultSource != "" {
+			source = profile.DefaultSource
+		}
+	}
+	if category == "" {
+		category = "other"
+		if row.Type == "CREDIT" {
+			category = "income"
+		}
+	}
+
+	description := row.Description
+	if description == "" {
+		description = "Imported from " + batch.FileName
+	}
+
+	metadata := map[string]interface{}{
+		"import_batch_id":    batch.ID.String(),
+		"import_fingerprint": row.Fingerprint,
+		"statement_date":     row.Date.Format("2006-01-02"),
+	}
+	if row.Reference != "" {
+		metadata["import_reference"] = row.Reference
+	}
+
+	req := dto.CreateTransactionRequest{
+		Type:        row.Type,
+		Amount:      row.Amount,
+		Currency:    batch.Currency,
+		Category:    category,
+		Source:      source,
+		Description: description,
+		Metadata:    metadata,
+		Date:        row.Date,
+	}
+
+	if batch.GroupID != nil {
+		req.GroupID = batch.GroupID
+		return s.transactionService.CreateGroupTransaction(userID, req)
+	}
+	return s.transactionService.CreatePersonalTransaction(userID, req)
+}
+
+// markDuplicates flags rows already in the owner's ledger: rows imported
+// before (same fingerprint) and manual entries made on the statement date
+// with the same type and amount. Each existing transaction matches one row.
+func (s *importService) markDuplicates(batch *models.ImportBatch, rows []models.ImportRow) error {
+	ownerType, ownerID := "USER", batch.UserID
+	if batch.GroupID != nil {
+		ownerType, ownerID = "GROUP", *batch.GroupID
+	}
+
+	var fingerprints []string
+	var first, last time.Time
+	for _, row := range rows {
+		if row.Status != "pending" {
+			continue
+		}
+		fingerprints = append(fingerprints, row.Fingerprint)
+		if first.IsZero() || row.Date.Before(first) {
+			first = *row.Date
+		}
+		if row.Date.After(last) {
+			last = *row.Date
+		}
+	}
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	imported, err := s.transactionRepo.FindByImportFingerprints(ownerType, ownerID, fingerprints)
+	if err != nil {
+		return err
+	}
+	byFingerprint := make(map[string][]uuid.UUID)
+	for _, transaction := range imported {
+		if fingerprint, ok := transaction.Metadata["import_fingerprint"].(string); ok {
+			byFingerprint[fingerprint] = append(byFingerprint[fingerprint], transaction.ID)
+		}
+	}
+
+	existing, err := s.transactionRepo.FindByDateRange(ownerType, ownerID, first, last.AddDate(0, 0, 1).Add(-time.Nanosecond))
+	if err != nil {
+		return err
+	}
+	byDay := make(map[string][]uuid.UUID)
+	for _, transaction := range existing {
+		if _, ok := transaction.Metadata["import_fingerprint"]; ok {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%d", transaction.CreatedAt.Format("2006-01-02"), transaction.Type, transaction.Amount)
+		byDay[key] = append(byDay[key], transaction.ID)
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != "pending" {
+			continue
+		}
+
+		if ids := byFingerprint[row.Fingerprint]; len(ids) > 0 {
+			row.Status = "duplicate"
+			row.DuplicateOf = &ids[0]
+			byFingerprint[row.Fingerprint] = ids[1:]
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%s|%d", row.Date.Format("2006-01-02"), row.Type, row.Amount)
+		if ids := byDay[key]; len(ids) > 0 {
+			row.Status = "duplicate"
+			row.DuplicateOf = &ids[0]
+			byDay[key] = ids[1:]
+		}
+	}
+
+	return nil
+}
+
+// recoverRows marks the pending rows that an earlier commit of the batch
+// posted before it died as imported, matching them to the transactions it
+// created.
+func (s *importService) recoverRows(batch *models.ImportBatch) error {
+	ownerType, ownerID := "USER", batch.UserID
+	if batch.GroupID != nil {
+		ownerType, ownerID = "GROUP", *batch.GroupID
+	}
+
+	var fingerprints []string
+	for _, row := range batch.Rows {
+		if row.Status == "pending" {
+			fingerprints = append(fingerprints, row.Fingerprint)
+		}
+	}
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	transactions, err := s.transactionRepo.FindByImportFingerprints(ownerType, ownerID, fingerprints)
+	if err != nil {
+		return err
+	}
+	posted := make(map[string][]uuid.UUID)
+	for _, transaction := range transactions {
+		if transaction.Metadata["import_batch_id"] != batch.ID.String() {
+			continue
+		}
+		if fingerprint, ok := transaction.Metadata["import_fingerprint"].(string); ok {
+			posted[fingerprint] = append(posted[fingerprint], transaction.ID)
+		}
+	}
+
+	for i := range batch.Rows {
+		row := &batch.Rows[i]
+		ids := posted[row.Fingerprint]
+		if row.Status != "pending" || len(ids) == 0 {
+			continue
+		}
+		row.Status = "imported"
+		row.Error = ""
+		row.TransactionID = &ids[0]
+		posted[row.Fingerprint] = ids[1:]
+		if err := s.importRepo.UpdateRow(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseBatch hands a claimed batch back when a commit stops before
+// posting anything.
+func (s *importService) releaseBatch(batchID uuid.UUID) {
+	batch, err := s.importRepo.FindBatchByID(batchID)
+	if err == nil {
+		batch.Status = batchStatus(batch)
+		err = s.importRepo.UpdateBatch(batch)
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to release import batch")
+	}
+}
+
+// batchStatus is "completed" once no row is left waiting to be imported.
+func batchStatus(batch *models.ImportBatch) string {
+	for _, row := range batch.Rows {
+		if row.Status == "pending" {
+			return "pending"
+		}
+	}
+	return "completed"
+}
+
+func (s *importService) findBatch(userID, batchID uuid.UUID) (*models.ImportBatch, error) {
+	batch, err := s.importRepo.FindBatchByID(batchID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "IMPORT_NOT_FOUND", Message: "Import not found"}
+	}
+
+	if batch.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *batch.GroupID)
+		if err != nil || userGroup.Status != "active" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+	} else if batch.UserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	return batch, nil
+}
+
+func buildImportRows(entries []importers.Entry) []models.ImportRow {
+	rows := make([]models.ImportRow, 0, len(entries))
+	seenReferences := make(map[string]int)
+	for _, entry := range entries {
+		row := models.ImportRow{
+			RowNumber:   entry.Line,
+			Description: entry.Description,
+			Category:    entry.Category,
+			Reference:   entry.Reference,
+			Status:      "pending",
+		}
+
+		switch {
+		case entry.Err != nil:
+			row.Status = "invalid"
+			row.Error = entry.Err.Error()
+		case entry.Amount == 0:
+			row.Status = "invalid"
+			row.Error = "amount is zero"
+		default:
+			date := entry.Date
+			row.Date = &date
+			row.Fingerprint = entry.Fingerprint()
+			row.Type = "CREDIT"
+			row.Amount = entry.Amount
+			if entry.Amount < 0 {
+				row.Type = "DEBIT"
+				row.Amount = -entry.Amount
+			}
+
+			// Statements downloaded for overlapping periods may repeat a
+			// bank reference within the same file
+			if entry.Reference != "" {
+				if line, ok := seenReferences[row.Fingerprint]; ok {
+					row.Status = "duplicate"
+					row.Error = fmt.Sprintf("same bank reference as row %d", line)
+				} else {
+					seenReferences[row.Fingerprint] = entry.Line
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// importFormat guesses the statement format from the uploaded file name.
+func importFormat(fileName string) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".ofx", ".qfx":
+		return "ofx"
+	case ".qif":
+		return "qif"
+	default:
+		return "csv"
+	}
+}
+
+func sameImportScope(profile *models.ImportProfile, userID uuid.UUID, groupID *uuid.UUID) bool {
+	if groupID != nil {
+		return profile.GroupID != nil && *profile.GroupID == *groupID
+	}
+	return profile.GroupID == nil && profile.UserID != nil && *profile.UserID == userID
+}
+
+func mapImportProfileToResponse(profile *models.ImportProfile) *dto.ImportProfileResponse {
+	return &dto.ImportProfileResponse{
+		ID:                profile.ID,
+		Name:              profile.Name,
+		UserID:            profile.UserID,
+		GroupID:           profile.GroupID,
+		Delimiter:         profile.Delimiter,
+		HasHeader:         profile.HasHeader,
+		DateColumn:        profile.DateColumn,
+		DateFormat:        profile.DateFormat,
+		AmountColumn:      profile.AmountColumn,
+		DebitColumn:       profile.DebitColumn,
+		CreditColumn:      profile.CreditColumn,
+		DescriptionColumn: profile.DescriptionColumn,
+		CategoryColumn:    profile.CategoryColumn,
+		SignConvention:    profile.SignConvention,
+		Locale:            profile.Locale,
+		DefaultCategory:   profile.DefaultCategory,
+		DefaultSource:     profile.DefaultSource,
+		CreatedBy:         profile.CreatedBy,
+		CreatedAt:         profile.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapImportProfilesToResponse(profiles []models.ImportProfile) []dto.ImportProfileResponse {
+	var response []dto.ImportProfileResponse
+	for _, profile := range profiles {
+		response = append(response, *mapImportProfileToResponse(&profile))
+	}
+	return response
+}
+
+func mapImportBatchToResponse(batch *models.ImportBatch) *dto.ImportBatchResponse {
+	response := &dto.ImportBatchResponse{
+		ID:        batch.ID,
+		GroupID:   batch.GroupID,
+		ProfileID: batch.ProfileID,
+		Format:    batch.Format,
+		FileName:  batch.FileName,
+		Currency:  batch.Currency,
+		Status:    batch.Status,
+		TotalRows: len(batch.Rows),
+		CreatedBy: batch.UserID,
+		CreatedAt: batch.CreatedAt.Format(time.RFC3339),
+	}
+
+	for _, row := range batch.Rows {
+		switch row.Status {
+		case "pending":
+			response.PendingRows++
+		case "duplicate":
+			response.DuplicateRows++
+		case "invalid":
+			response.InvalidRows++
+		case "imported":
+			response.ImportedRows++
+		}
+
+		response.Rows = append(response.Rows, dto.ImportRowResponse{
+			ID:            row.ID,
+			RowNumber:     row.RowNumber,
+			Date:          row.Date,
+			Type:          row.Type,
+			Amount:        row.Amount,
+			Description:   row.Description,
+			Category:      row.Category,
+			Reference:     row.Reference,
+			Status:        row.Status,
+			Error:         row.Error,
+			DuplicateOf:   row.DuplicateOf,
+			TransactionID: row.TransactionID,
+		})
+	}
+
+	return response
+}
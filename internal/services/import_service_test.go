@@ -0,0 +1,100 @@
+package services
+
+import (
+	"balanca/internal/importers"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func parseOFXFixture(t *testing.T, name string) []importers.Entry {
+	t.Helper()
+	file, err := os.Open(filepath.Join("..", "importers", "testdata", name))
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer file.Close()
+
+	entries, err := importers.ParseOFX(file, "USD")
+	if err != nil {
+		t.Fatalf("ParseOFX(%s): %v", name, err)
+	}
+	return entries
+}
+
+type importRowWant struct {
+	status string
+	kind   string
+	amount int64
+	error  string
+}
+
+func TestBuildImportRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []importers.Entry
+		want    []importRowWant
+	}{
+		{
+			name:    "repeated FITID in overlapping statements",
+			entries: parseOFXFixture(t, "repeated_fitid.ofx"),
+			want: []importRowWant{
+				{status: "pending", kind: "CREDIT", amount: 250000},
+				{status: "pending", kind: "DEBIT", amount: 4567},
+				{status: "pending", kind: "DEBIT", amount: 500},
+				{status: "duplicate", kind: "DEBIT", amount: 4567, error: "same bank reference as row 43"},
+				{status: "pending", kind: "CREDIT", amount: 250000},
+			},
+		},
+		{
+			name: "invalid entries",
+			entries: []importers.Entry{
+				{Line: 2, Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 0, Description: "Nothing"},
+				{Line: 3, Err: os.ErrInvalid},
+				{Line: 4, Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 100, Description: "Same"},
+				{Line: 5, Date: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), Amount: 100, Description: "Same"},
+			},
+			want: []importRowWant{
+				{status: "invalid", error: "amount is zero"},
+				{status: "invalid", error: os.ErrInvalid.Error()},
+				// Without a bank reference identical lines are kept: they
+				// may be two real purchases
+				{status: "pending", kind: "CREDIT", amount: 100},
+				{status: "pending", kind: "CREDIT", amount: 100},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := buildImportRows(tt.entries)
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.want))
+			}
+			for i, want := range tt.want {
+				row := rows[i]
+				if row.Status != want.status || row.Type != want.kind || row.Amount != want.amount || row.Error != want.error {
+					t.Errorf("row %d = {%s %s %d %q}, want {%s %s %d %q}", i,
+						row.Status, row.Type, row.Amount, row.Error, want.status, want.kind, want.amount, want.error)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildImportRowsFingerprintsMatchAcrossStatements(t *testing.T) {
+	january := buildImportRows(parseOFXFixture(t, "bank_v1.ofx"))
+	overlap := buildImportRows(parseOFXFixture(t, "bank_overlap_v1.ofx"))
+
+	// Each upload is checked against the transactions already imported by
+	// fingerprint, so the shared FITID must produce the same one
+	if january[1].Fingerprint != overlap[0].Fingerprint {
+		t.Errorf("FITID JAN-0002 fingerprints differ: %s and %s", january[1].Fingerprint, overlap[0].Fingerprint)
+	}
+	for _, row := range overlap {
+		if row.Status != "pending" {
+			t.Errorf("row %d status = %s, want pending within its own statement", row.RowNumber, row.Status)
+		}
+	}
+}
@@ -15,22 +15,27 @@ import (
 type PlannedExpenseService interface {
 	CreatePersonalExpense(userID uuid.UUID, req dto.CreatePlannedExpenseRequest) (*dto.PlannedExpenseResponse, error)
 	CreateGroupExpense(userID uuid.UUID, req dto.CreatePlannedExpenseRequest) (*dto.PlannedExpenseResponse, error)
-	GetPersonalExpenses(userID uuid.UUID, status string, page, limit int) ([]dto.PlannedExpenseResponse, int64, error)
-	GetGroupExpenses(userID, groupID uuid.UUID, status string, page, limit int) ([]dto.PlannedExpenseResponse, int64, error)
+	GetPersonalExpenses(userID uuid.UUID, filter dto.PlannedExpenseFilter, page, limit int) ([]dto.PlannedExpenseResponse, int64, error)
+	GetGroupExpenses(userID, groupID uuid.UUID, filter dto.PlannedExpenseFilter, page, limit int) ([]dto.PlannedExpenseResponse, int64, error)
 	GetExpense(userID, expenseID uuid.UUID) (*dto.PlannedExpenseResponse, error)
 	UpdateExpense(userID, expenseID uuid.UUID, req dto.UpdatePlannedExpenseRequest) (*dto.PlannedExpenseResponse, error)
 	DeleteExpense(userID, expenseID uuid.UUID) error
 	MarkAsBought(userID, expenseID uuid.UUID, req dto.MarkAsBoughtRequest) (*dto.PlannedExpenseResponse, error)
 	MarkAsCancelled(userID, expenseID uuid.UUID) error
 	GetOverdueExpenses(userID uuid.UUID) ([]dto.PlannedExpenseResponse, error)
+	SpawnDueOccurrences() (int, error)
 }
 
 type plannedExpenseService struct {
-	expenseRepo repositories.PlannedExpenseRepository
-	userRepo    repositories.UserRepository
-	groupRepo   repositories.GroupRepository
-	auditRepo   repositories.AuditLogRepository
-	db          *gorm.DB
+	expenseRepo      repositories.PlannedExpenseRepository
+	userRepo         repositories.UserRepository
+	groupRepo        repositories.GroupRepository
+	auditRepo        repositories.AuditLogRepository
+	tagRepo          repositories.TagRepository
+	categoryRepo     repositories.CategoryRepository
+	approvalRepo     repositories.ApprovalRepository
+	notificationRepo repositories.NotificationRepository
+	db               *gorm.DB
 }
 
 func NewPlannedExpenseService(
@@ -38,32 +43,50 @@ func NewPlannedExpenseService(
 	userRepo repositories.UserRepository,
 	groupRepo repositories.GroupRepository,
 	auditRepo repositories.AuditLogRepository,
+	tagRepo repositories.TagRepository,
+	categoryRepo repositories.CategoryRepository,
+	approvalRepo repositories.ApprovalRepository,
+	notificationRepo repositories.NotificationRepository,
 	db *gorm.DB,
 ) PlannedExpenseService {
 	return &plannedExpenseService{
-		expenseRepo: expenseRepo,
-		userRepo:    userRepo,
-		groupRepo:   groupRepo,
-		auditRepo:   auditRepo,
-		db:          db,
+		expenseRepo:      expenseRepo,
+		userRepo:         userRepo,
+		groupRepo:        groupRepo,
+		auditRepo:        auditRepo,
+		tagRepo:          tagRepo,
+		categoryRepo:     categoryRepo,
+		approvalRepo:     approvalRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
 	}
 }
 
 func (s *plannedExpenseService) CreatePersonalExpense(userID uuid.UUID, req dto.CreatePlannedExpenseRequest) (*dto.PlannedExpenseResponse, error) {
+	category, err := resolveCategory(s.categoryRepo, "category", userID, nil, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := resolveTags(s.tagRepo, userID, nil, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	expense := &models.PlannedExpense{
 		Item:           req.Item,
 		Description:    req.Description,
 		EstimatedPrice: req.EstimatedPrice,
-		Category:       req.Category,
+		Category:       category,
 		Priority:       req.Priority,
 		Status:         "planned",
 		UserID:         userID,
 		DueDate:        req.DueDate,
+		Tags:           tags,
 	}
 
-	if err := s.expenseRepo.Create(expense); err != nil {
-		log.Error().Err(err).Msg("Failed to create planned expense")
-		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	if err := s.createExpense(expense, req.Recurrence); err != nil {
+		return nil, err
 	}
 
 	// Create audit log
@@ -99,21 +122,36 @@ func (s *plannedExpenseService) CreateGroupExpense(userID uuid.UUID, req dto.Cre
 		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
 	}
 
+	category, err := resolveCategory(s.categoryRepo, "category", userID, req.GroupID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := resolveTags(s.tagRepo, userID, req.GroupID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	expense := &models.PlannedExpense{
 		Item:           req.Item,
 		Description:    req.Description,
 		EstimatedPrice: req.EstimatedPrice,
-		Category:       req.Category,
+		Category:       category,
 		Priority:       req.Priority,
 		Status:         "planned",
 		UserID:         userID,
 		GroupID:        req.GroupID,
 		DueDate:        req.DueDate,
+		Tags:           tags,
 	}
 
-	if err := s.expenseRepo.Create(expense); err != nil {
-		log.Error().Err(err).Msg("Failed to create planned expense")
-		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	approvers, err := s.holdForApproval(expense)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.createExpense(expense, req.Recurrence); err != nil {
+		return nil, err
 	}
 
 	// Create audit log
@@ -130,6 +168,10 @@ func (s *plannedExpenseService) CreateGroupExpense(userID uuid.UUID, req dto.Cre
 		log.Error().Err(err).Msg("Failed to create audit log")
 	}
 
+	if expense.Status == "pending_approval" {
+		s.requestApproval(expense, approvers, userID)
+	}
+
 	// Get full expense data
 	fullExpense, err := s.expenseRepo.FindByID(expense.ID)
 	if err != nil {
@@ -139,8 +181,13 @@ func (s *plannedExpenseService) CreateGroupExpense(userID uuid.UUID, req dto.Cre
 	return s.mapExpenseToResponse(fullExpense), nil
 }
 
-func (s *plannedExpenseService) GetPersonalExpenses(userID uuid.UUID, status string, page, limit int) ([]dto.PlannedExpenseResponse, int64, error) {
-	expenses, total, err := s.expenseRepo.FindByUser(userID, status, page, limit)
+func (s *plannedExpenseService) GetPersonalExpenses(userID uuid.UUID, filter dto.PlannedExpenseFilter, page, limit int) ([]dto.PlannedExpenseResponse, int64, error) {
+	repoFilter, err := buildPlannedExpenseFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	expenses, total, err := s.expenseRepo.FindByUser(userID, repoFilter, page, limit)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get personal expenses")
 		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get expenses"}
@@ -154,14 +201,19 @@ func (s *plannedExpenseService) GetPersonalExpenses(userID uuid.UUID, status str
 	return response, total, nil
 }
 
-func (s *plannedExpenseService) GetGroupExpenses(userID, groupID uuid.UUID, status string, page, limit int) ([]dto.PlannedExpenseResponse, int64, error) {
+func (s *plannedExpenseService) GetGroupExpenses(userID, groupID uuid.UUID, filter dto.PlannedExpenseFilter, page, limit int) ([]dto.PlannedExpenseResponse, int64, error) {
 	// Check if user is a member of the group
 	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
 	if err != nil || userGroup.Status != "active" {
 		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
 	}
 
-	expenses, total, err := s.expenseRepo.FindByGroup(groupID, status, page, limit)
+	repoFilter, err := buildPlannedExpenseFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	expenses, total, err := s.expenseRepo.FindByGroup(groupID, repoFilter, page, limit)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get group expenses")
 		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get expenses"}
@@ -216,32 +268,71 @@ func (s *plannedExpenseService) UpdateExpense(userID, expenseID uuid.UUID, req d
 		}
 	}
 
-	// Record changes for audit log
+	// Series edits need a series, and a new schedule starts from its latest
+	// occurrence so that no occurrence is spawned twice
+	var series *models.ExpenseRecurrence
+	if req.Scope == "series" {
+		if expense.RecurrenceID == nil {
+			return nil, &errors.AppError{Code: "NOT_RECURRING", Message: "Expense is not part of a series"}
+		}
+		series, err = s.expenseRepo.FindRecurrence(*expense.RecurrenceID)
+		if err != nil {
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get expense series"}
+		}
+		if (req.DueDate != nil || req.Recurrence != nil) && (series.LastExpenseID == nil || *series.LastExpenseID != expense.ID) {
+			return nil, &errors.AppError{Code: "NOT_LATEST_OCCURRENCE", Message: "Change the schedule from the latest occurrence of the series"}
+		}
+	} else if req.Recurrence != nil {
+		return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Recurrence can only be changed with the series scope"}
+	}
+
+	// Members vote on the item at its price, so neither changes mid-vote
+	itemChanged := req.Item != nil && *req.Item != expense.Item
+	priceChanged := req.EstimatedPrice != nil && *req.EstimatedPrice != expense.EstimatedPrice
+	if expense.Status == "proposed" && (itemChanged || priceChanged) {
+		return nil, &errors.AppError{Code: "VOTE_PENDING", Message: "Cancel the proposal before changing the item or price of this expense"}
+	}
+
+	// Record changes for audit log, and the columns they touch
 	changes := make(map[string]interface{})
+	fields := make(map[string]interface{})
 
 	// Update fields if provided
 	if req.Item != nil && *req.Item != expense.Item {
 		changes["item"] = map[string]interface{}{"old": expense.Item, "new": *req.Item}
+		fields["item"] = *req.Item
 		expense.Item = *req.Item
 	}
 
 	if req.Description != nil && *req.Description != expense.Description {
 		changes["description"] = map[string]interface{}{"old": expense.Description, "new": *req.Description}
+		fields["description"] = *req.Description
 		expense.Description = *req.Description
 	}
 
+	priceRaised := req.EstimatedPrice != nil && *req.EstimatedPrice > expense.EstimatedPrice
+	priceLowered := req.EstimatedPrice != nil && *req.EstimatedPrice < expense.EstimatedPrice
 	if req.EstimatedPrice != nil && *req.EstimatedPrice != expense.EstimatedPrice {
 		changes["estimated_price"] = map[string]interface{}{"old": expense.EstimatedPrice, "new": *req.EstimatedPrice}
+		fields["estimated_price"] = *req.EstimatedPrice
 		expense.EstimatedPrice = *req.EstimatedPrice
 	}
 
 	if req.Category != nil && *req.Category != expense.Category {
-		changes["category"] = map[string]interface{}{"old": expense.Category, "new": *req.Category}
-		expense.Category = *req.Category
+		category, err := resolveCategory(s.categoryRepo, "category", expense.UserID, expense.GroupID, *req.Category)
+		if err != nil {
+			return nil, err
+		}
+		if category != expense.Category {
+			changes["category"] = map[string]interface{}{"old": expense.Category, "new": category}
+			fields["category"] = category
+			expense.Category = category
+		}
 	}
 
 	if req.Priority != nil && *req.Priority != expense.Priority {
 		changes["priority"] = map[string]interface{}{"old": expense.Priority, "new": *req.Priority}
+		fields["priority"] = *req.Priority
 		expense.Priority = *req.Priority
 	}
 
@@ -252,12 +343,79 @@ func (s *plannedExpenseService) UpdateExpense(userID, expenseID uuid.UUID, req d
 		} else if oldDueDate == nil && req.DueDate != nil {
 			changes["due_date"] = map[string]interface{}{"old": nil, "new": req.DueDate}
 		}
+		fields["due_date"] = req.DueDate
 		expense.DueDate = req.DueDate
 	}
 
-	if err := s.expenseRepo.Update(expense); err != nil {
-		log.Error().Err(err).Msg("Failed to update expense")
-		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense"}
+	var tags []models.Tag
+	if req.TagIDs != nil {
+		tags, err = resolveTags(s.tagRepo, expense.UserID, expense.GroupID, *req.TagIDs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// A raised price is approved afresh; a lowered one may no longer need it
+	var approvers []uuid.UUID
+	resubmit := false
+	oldStatus := expense.Status
+	if priceRaised && oldStatus == "partially_paid" {
+		// Payments already went towards it, so it cannot go back on hold
+		if _, err := s.holdForApproval(expense); err != nil {
+			return nil, err
+		}
+		if expense.Status == "pending_approval" {
+			return nil, &errors.AppError{Code: "APPROVAL_REQUIRED", Message: "The new price needs approval, which a partially paid expense cannot wait for"}
+		}
+	}
+	if (priceRaised && oldStatus == "planned") || ((priceRaised || priceLowered) && oldStatus == "pending_approval") {
+		expense.Status = "planned"
+		approvers, err = s.holdForApproval(expense)
+		if err != nil {
+			return nil, err
+		}
+		resubmit = priceRaised && expense.Status == "pending_approval"
+		if expense.Status != oldStatus {
+			changes["status"] = map[string]interface{}{"old": oldStatus, "new": expense.Status}
+			fields["status"] = expense.Status
+		}
+	}
+
+	if len(fields) > 0 {
+		updated, err := s.expenseRepo.Update(expense.ID, oldStatus, expense.PaidAmount, fields)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to update expense")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense"}
+		}
+		if !updated {
+			return nil, &errors.AppError{Code: "EXPENSE_CONFLICT", Message: "Expense was updated by another request, please retry"}
+		}
+	}
+
+	if req.TagIDs != nil {
+		if err := s.expenseRepo.ReplaceTags(expense, tags); err != nil {
+			log.Error().Err(err).Msg("Failed to update expense tags")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense"}
+		}
+
+		var tagNames []string
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+		changes["tags"] = tagNames
+	}
+
+	if series != nil {
+		if err := s.updateSeries(userID, series, expense, req, tags); err != nil {
+			return nil, err
+		}
+		changes["scope"] = "series"
+	}
+
+	if resubmit {
+		if err := s.approvalRepo.DeleteDecisions(expense.ID); err != nil {
+			log.Error().Err(err).Msg("Failed to reset approval decisions")
+		}
 	}
 
 	// Create audit log if there were changes
@@ -276,6 +434,10 @@ func (s *plannedExpenseService) UpdateExpense(userID, expenseID uuid.UUID, req d
 		}
 	}
 
+	if resubmit {
+		s.requestApproval(expense, approvers, userID)
+	}
+
 	// Get updated expense data
 	updatedExpense, err := s.expenseRepo.FindByID(expenseID)
 	if err != nil {
@@ -331,34 +493,66 @@ func (s *plannedExpenseService) MarkAsBought(userID, expenseID uuid.UUID, req dt
 		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
 	}
 
-	// Check if expense is in planned status
-	if expense.Status != "planned" {
+	// Check if expense is still to be paid
+	if expense.Status != "planned" && expense.Status != "partially_paid" {
 		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not in planned status"}
 	}
 
-	// For personal expenses, just mark as bought
+	// For personal expenses, just record the payment
 	if expense.GroupID == nil {
 		if expense.UserID != userID {
 			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
 		}
 
-		if err := s.expenseRepo.MarkAsBought(expenseID, req.ActualPrice, userID); err != nil {
+		payment := &models.ExpensePayment{
+			PlannedExpenseID: expenseID,
+			Amount:           req.ActualPrice,
+			PaidBy:           userID,
+			PaidAt:           time.Now(),
+		}
+
+		tx := s.db.Begin()
+		defer func() {
+			if r := recover(); r != nil {
+				tx.Rollback()
+			}
+		}()
+
+		if err := recordPayment(tx, expense, payment, req.Partial); err != nil {
+			tx.Rollback()
+			if appErr, ok := err.(*errors.AppError); ok {
+				return nil, appErr
+			}
 			log.Error().Err(err).Msg("Failed to mark expense as bought")
 			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to mark expense as bought"}
 		}
 
+		if err := tx.Commit().Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to commit transaction")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to mark expense as bought"}
+		}
+
 		// Create audit log
+		action := "record_payment"
+		if expense.Status == "bought" {
+			action = "mark_as_bought"
+		}
 		auditLog := &models.AuditLog{
 			Entity:      "planned_expense",
 			EntityID:    expenseID,
-			Action:      "mark_as_bought",
-			Changes:     map[string]interface{}{"actual_price": req.ActualPrice},
+			Action:      action,
+			Changes:     map[string]interface{}{"amount": req.ActualPrice, "paid_amount": expense.PaidAmount, "status": expense.Status},
 			PerformedBy: userID,
 		}
 
 		if err := s.auditRepo.Create(auditLog); err != nil {
 			log.Error().Err(err).Msg("Failed to create audit log")
 		}
+
+		if expense.Status == "bought" {
+			s.continueSeries(expense, userID)
+		}
 	} else {
 		// For group expenses, use the transaction service to handle payment
 		// This will be called from the group transaction flow
@@ -393,10 +587,15 @@ func (s *plannedExpenseService) MarkAsCancelled(userID, expenseID uuid.UUID) err
 		}
 	}
 
-	if err := s.expenseRepo.MarkAsCancelled(expenseID); err != nil {
+	// Only expenses nothing was paid on yet can be cancelled
+	cancelled, err := s.expenseRepo.MarkAsCancelled(expenseID)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to mark expense as cancelled")
 		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to mark expense as cancelled"}
 	}
+	if !cancelled {
+		return &errors.AppError{Code: "INVALID_STATUS", Message: "Only planned, pending or proposed expenses can be cancelled"}
+	}
 
 	// Create audit log
 	auditLog := &models.AuditLog{
@@ -411,48 +610,21 @@ func (s *plannedExpenseService) MarkAsCancelled(userID, expenseID uuid.UUID) err
 		log.Error().Err(err).Msg("Failed to create audit log")
 	}
 
+	s.continueSeries(expense, userID)
+
 	return nil
 }
 
 func (s *plannedExpenseService) GetOverdueExpenses(userID uuid.UUID) ([]dto.PlannedExpenseResponse, error) {
-	// Get user's personal overdue expenses
-	personalExpenses, err := s.expenseRepo.FindOverdue(0) // 0 days means all past due
+	// Get the user's personal and group overdue expenses
+	expenses, err := s.expenseRepo.FindOverdueForUser(userID, time.Now())
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get overdue expenses")
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get overdue expenses"}
 	}
 
-	// Filter only user's expenses
-	var userExpenses []models.PlannedExpense
-	for _, expense := range personalExpenses {
-		if expense.UserID == userID && expense.GroupID == nil {
-			userExpenses = append(userExpenses, expense)
-		}
-	}
-
-	// Get user's groups
-	groups, err := s.groupRepo.FindUserGroups(userID)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to get user groups")
-		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get overdue expenses"}
-	}
-
-	// Get overdue expenses for each group
-	for _, group := range groups {
-		groupExpenses, err := s.expenseRepo.FindOverdue(0)
-		if err != nil {
-			continue
-		}
-
-		for _, expense := range groupExpenses {
-			if expense.GroupID != nil && *expense.GroupID == group.ID {
-				userExpenses = append(userExpenses, expense)
-			}
-		}
-	}
-
 	var response []dto.PlannedExpenseResponse
-	for _, expense := range userExpenses {
+	for _, expense := range expenses {
 		response = append(response, *s.mapExpenseToResponse(&expense))
 	}
 
@@ -466,6 +638,7 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 		Description:    expense.Description,
 		EstimatedPrice: expense.EstimatedPrice,
 		ActualPrice:    expense.ActualPrice,
+		PaidAmount:     expense.PaidAmount,
 		Category:       expense.Category,
 		Status:         expense.Status,
 		Priority:       expense.Priority,
@@ -474,8 +647,11 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 		PaidBy:         expense.PaidBy,
 		PaidAt:         expense.PaidAt,
 		DueDate:        expense.DueDate,
+		Recurrence:     mapRecurrenceToResponse(expense.Recurrence),
 		CreatedAt:      expense.CreatedAt,
 		UpdatedAt:      expense.UpdatedAt,
+		Tags:           mapTagsToResponse(expense.Tags),
+		Payments:       mapPaymentsToResponse(expense.Payments),
 		User: dto.UserResponse{
 			ID:          expense.User.ID,
 			PhoneNumber: expense.User.PhoneNumber,
@@ -483,11 +659,16 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 			FirstName:   expense.User.FirstName,
 			LastName:    expense.User.LastName,
 			Balance:     expense.User.Balance,
+			Currency:    expense.User.Currency,
 			IsActive:    expense.User.IsActive,
 			CreatedAt:   expense.User.CreatedAt.Format(time.RFC3339),
 		},
 	}
 
+	if expense.Status != "bought" && expense.Status != "cancelled" && expense.PaidAmount < expense.EstimatedPrice {
+		response.RemainingAmount = expense.EstimatedPrice - expense.PaidAmount
+	}
+
 	// Add group info if available
 	if expense.GroupID != nil && expense.Group.ID != uuid.Nil {
 		response.Group = &dto.GroupResponse{
@@ -496,6 +677,7 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 			Description: expense.Group.Description,
 			Balance:     expense.Group.Balance,
 			CreatedBy:   expense.Group.CreatedBy,
+			Currency:    expense.Group.Currency,
 			IsActive:    expense.Group.IsActive,
 			CreatedAt:   expense.Group.CreatedAt.Format(time.RFC3339),
 		}
@@ -510,6 +692,7 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 			FirstName:   expense.Payer.FirstName,
 			LastName:    expense.Payer.LastName,
 			Balance:     expense.Payer.Balance,
+			Currency:    expense.Payer.Currency,
 			IsActive:    expense.Payer.IsActive,
 			CreatedAt:   expense.Payer.CreatedAt.Format(time.RFC3339),
 		}
@@ -517,3 +700,614 @@ func (s *plannedExpenseService) mapExpenseToResponse(expense *models.PlannedExpe
 
 	return response
 }
+
+// checkPayable explains why a group expense cannot be paid yet, if it cannot.
+func checkPayable(expense *models.PlannedExpense) error {
+	switch expense.Status {
+	case "planned", "partially_paid":
+		return nil
+	case "pending_approval":
+		return &errors.AppError{Code: "APPROVAL_PENDING", Message: "Expense is waiting for approval"}
+	case "proposed":
+		return &errors.AppError{Code: "VOTE_PENDING", Message: "Expense is being voted on"}
+	}
+	return &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not in planned status"}
+}
+
+// recordPayment records a payment towards an expense inside tx and moves the
+// expense to bought. A partial payment only moves it to partially_paid, until
+// the payments reach its estimated price. The actual price of a bought
+// expense is the total paid.
+func recordPayment(tx *gorm.DB, expense *models.PlannedExpense, payment *models.ExpensePayment, partial bool) error {
+	paidAmount := expense.PaidAmount + payment.Amount
+	fields := map[string]interface{}{
+		"paid_amount": paidAmount,
+		"status":      "partially_paid",
+	}
+	settled := !partial || paidAmount >= expense.EstimatedPrice
+	if settled {
+		fields["status"] = "bought"
+		fields["actual_price"] = paidAmount
+		fields["paid_by"] = payment.PaidBy
+		fields["paid_at"] = payment.PaidAt
+	}
+
+	// Only from the state the expense was read in, so concurrent payments
+	// cannot both count against the same balance
+	result := tx.Model(&models.PlannedExpense{}).
+		Where("id = ? AND paid_amount = ? AND status IN ?", expense.ID, expense.PaidAmount, []string{"planned", "partially_paid"}).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &errors.AppError{Code: "PAYMENT_CONFLICT", Message: "Expense was updated by another payment, please retry"}
+	}
+
+	if err := tx.Create(payment).Error; err != nil {
+		return err
+	}
+
+	expense.PaidAmount = paidAmount
+	expense.Status = fields["status"].(string)
+	if settled {
+		expense.ActualPrice = &paidAmount
+		expense.PaidBy = &payment.PaidBy
+		expense.PaidAt = &payment.PaidAt
+	}
+	return nil
+}
+
+func mapPaymentsToResponse(payments []models.ExpensePayment) []dto.ExpensePaymentResponse {
+	response := []dto.ExpensePaymentResponse{}
+	for _, payment := range payments {
+		item := dto.ExpensePaymentResponse{
+			ID:            payment.ID,
+			Amount:        payment.Amount,
+			PaidBy:        payment.PaidBy,
+			PaidAt:        payment.PaidAt,
+			TransactionID: payment.TransactionID,
+			Description:   payment.Description,
+		}
+		if payment.Payer != nil {
+			item.PayerName = payment.Payer.FirstName + " " + payment.Payer.LastName
+		}
+		response = append(response, item)
+	}
+	return response
+}
+
+// buildPlannedExpenseFilter converts the query parameters of a listing into a
+// repository filter.
+func buildPlannedExpenseFilter(filter dto.PlannedExpenseFilter) (repositories.PlannedExpenseFilter, error) {
+	tagID, err := parseOptionalID(filter.TagID)
+	if err != nil {
+		return repositories.PlannedExpenseFilter{}, &errors.AppError{Code: "INVALID_REQUEST", Message: "Invalid tag ID"}
+	}
+
+	return repositories.PlannedExpenseFilter{Status: filter.Status, TagID: tagID}, nil
+}
+
+// SpawnDueOccurrences creates the occurrences of recurring expenses whose due
+// date has arrived, catching up on any periods missed while the server was
+// down. It returns the number of occurrences created.
+func (s *plannedExpenseService) SpawnDueOccurrences() (int, error) {
+	now := time.Now()
+	due, err := s.expenseRepo.FindDueRecurrences(now)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get due recurring expenses")
+		return 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to spawn recurring expenses"}
+	}
+
+	spawned := 0
+	for i := range due {
+		spawned += s.spawnSeries(&due[i], now)
+	}
+
+	return spawned, nil
+}
+
+// spawnSeries plans the occurrences of a series due by now in one database
+// transaction and returns how many it created. Failures are logged and leave
+// the series for the next run.
+func (s *plannedExpenseService) spawnSeries(series *models.ExpenseRecurrence, now time.Time) int {
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	created := 0
+	for series.IsActive && !series.NextDueDate.After(now) {
+		next, err := spawnNextOccurrence(tx, series, series.UserID)
+		if err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to spawn recurring expense")
+			return 0
+		}
+		if next == nil {
+			break
+		}
+		created++
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit recurring expenses")
+		return 0
+	}
+	return created
+}
+
+// holdForApproval puts a group expense in pending_approval when the group's
+// approval policy requires it, and returns the members who can approve it.
+func (s *plannedExpenseService) holdForApproval(expense *models.PlannedExpense) ([]uuid.UUID, error) {
+	if expense.GroupID == nil {
+		return nil, nil
+	}
+
+	policy, err := s.approvalRepo.FindPolicy(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get approval policy")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check approval policy"}
+	}
+	if !requiresApproval(policy, expense.EstimatedPrice) {
+		return nil, nil
+	}
+
+	members, err := s.groupRepo.FindMembers(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check approval policy"}
+	}
+
+	approvers, hold := approvalHold(policy, members, expense.UserID, expense.EstimatedPrice)
+	if hold {
+		expense.Status = "pending_approval"
+	}
+	return approvers, nil
+}
+
+// requestApproval records that a held expense awaits approval and asks the
+// approvers to decide on it.
+func (s *plannedExpenseService) requestApproval(expense *models.PlannedExpense, approvers []uuid.UUID, userID uuid.UUID) {
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "planned_expense",
+		EntityID:    expense.ID,
+		Action:      "request_approval",
+		Changes:     map[string]interface{}{"estimated_price": expense.EstimatedPrice, "approvers": len(approvers)},
+		PerformedBy: userID,
+		GroupID:     expense.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	if err := s.notificationRepo.CreateBatch(approvalRequests(expense, approvers)); err != nil {
+		log.Error().Err(err).Msg("Failed to create approval notifications")
+	}
+}
+
+// createExpense saves a new planned expense and, with a recurrence, the
+// series it is the first occurrence of.
+func (s *plannedExpenseService) createExpense(expense *models.PlannedExpense, recurrence *dto.RecurrenceRequest) error {
+	if recurrence == nil {
+		if err := s.expenseRepo.Create(expense); err != nil {
+			log.Error().Err(err).Msg("Failed to create planned expense")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+		}
+		return nil
+	}
+
+	if expense.DueDate == nil {
+		return &errors.AppError{Code: "INVALID_REQUEST", Message: "A recurring expense needs a due date"}
+	}
+	if recurrence.EndDate != nil && recurrence.EndDate.Before(*expense.DueDate) {
+		return &errors.AppError{Code: "INVALID_REQUEST", Message: "The recurrence cannot end before the first due date"}
+	}
+
+	series := &models.ExpenseRecurrence{
+		UserID:         expense.UserID,
+		GroupID:        expense.GroupID,
+		Item:           expense.Item,
+		Description:    expense.Description,
+		EstimatedPrice: expense.EstimatedPrice,
+		Category:       expense.Category,
+		Priority:       expense.Priority,
+		Tags:           expense.Tags,
+	}
+	scheduleSeries(series, *expense.DueDate, recurrence)
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(series).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create expense series")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	}
+
+	expense.RecurrenceID = &series.ID
+	if err := tx.Create(expense).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create planned expense")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	}
+
+	if err := tx.Model(series).Update("last_expense_id", expense.ID).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update expense series")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create planned expense"}
+	}
+
+	return nil
+}
+
+// updateSeries carries a series-scope edit of one occurrence over to the
+// series template and to the series' other planned occurrences. A new due
+// date or recurrence restarts the schedule from this occurrence, and the
+// occurrences whose price goes up go through the approval policy again.
+func (s *plannedExpenseService) updateSeries(userID uuid.UUID, series *models.ExpenseRecurrence, expense *models.PlannedExpense, req dto.UpdatePlannedExpenseRequest, tags []models.Tag) error {
+	fields := map[string]interface{}{}
+	if req.Item != nil {
+		series.Item = expense.Item
+		fields["item"] = expense.Item
+	}
+	if req.Description != nil {
+		series.Description = expense.Description
+		fields["description"] = expense.Description
+	}
+	if req.EstimatedPrice != nil {
+		series.EstimatedPrice = expense.EstimatedPrice
+		fields["estimated_price"] = expense.EstimatedPrice
+	}
+	if req.Category != nil {
+		series.Category = expense.Category
+		fields["category"] = expense.Category
+	}
+	if req.Priority != nil {
+		series.Priority = expense.Priority
+		fields["priority"] = expense.Priority
+	}
+
+	if req.DueDate != nil || req.Recurrence != nil {
+		if expense.DueDate == nil {
+			return &errors.AppError{Code: "INVALID_REQUEST", Message: "A recurring expense needs a due date"}
+		}
+		recurrence := req.Recurrence
+		if recurrence == nil {
+			recurrence = &dto.RecurrenceRequest{Frequency: series.Frequency, Interval: series.Interval, EndDate: series.EndDate}
+		}
+		if recurrence.EndDate != nil && recurrence.EndDate.Before(*expense.DueDate) {
+			return &errors.AppError{Code: "INVALID_REQUEST", Message: "The recurrence cannot end before the first due date"}
+		}
+		scheduleSeries(series, *expense.DueDate, recurrence)
+	}
+
+	var others []models.PlannedExpense
+	if req.TagIDs != nil || req.EstimatedPrice != nil {
+		var err error
+		others, err = s.expenseRepo.FindPlannedOccurrences(series.ID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get series occurrences")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+		}
+	}
+
+	// The other occurrences whose price goes up are approved afresh
+	var held []models.PlannedExpense
+	var heldApprovers [][]uuid.UUID
+	if req.EstimatedPrice != nil {
+		for _, other := range others {
+			if other.ID == expense.ID || other.EstimatedPrice >= expense.EstimatedPrice {
+				continue
+			}
+			other.EstimatedPrice = expense.EstimatedPrice
+			approvers, err := s.holdForApproval(&other)
+			if err != nil {
+				return err
+			}
+			if other.Status == "pending_approval" {
+				held = append(held, other)
+				heldApprovers = append(heldApprovers, approvers)
+			}
+		}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Omit("Tags").Save(series).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update expense series")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+	}
+
+	if len(fields) > 0 {
+		if err := tx.Model(&models.PlannedExpense{}).
+			Where("recurrence_id = ? AND status = 'planned' AND id <> ?", series.ID, expense.ID).
+			Updates(fields).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update series occurrences")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+		}
+	}
+
+	for _, occurrence := range held {
+		if err := tx.Model(&models.PlannedExpense{}).
+			Where("id = ? AND status = 'planned'", occurrence.ID).
+			Update("status", "pending_approval").Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to hold series occurrence for approval")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+		}
+		if err := tx.Unscoped().Delete(&models.ExpenseApproval{}, "planned_expense_id = ?", occurrence.ID).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to reset approval decisions")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+		}
+	}
+
+	if req.TagIDs != nil {
+		if err := tx.Model(series).Association("Tags").Replace(tags); err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update series tags")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+		}
+		for i := range others {
+			if others[i].ID == expense.ID {
+				continue
+			}
+			if err := tx.Model(&others[i]).Association("Tags").Replace(tags); err != nil {
+				tx.Rollback()
+				log.Error().Err(err).Msg("Failed to update series occurrence tags")
+				return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+			}
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update expense series"}
+	}
+
+	for i := range held {
+		s.requestApproval(&held[i], heldApprovers[i], userID)
+	}
+
+	return nil
+}
+
+// continueSeries spawns the next occurrence after the latest one of a series
+// is bought or cancelled. The expense is already settled, so failures are
+// only logged; the scheduler catches up on the next due date.
+func (s *plannedExpenseService) continueSeries(expense *models.PlannedExpense, userID uuid.UUID) {
+	if expense.RecurrenceID == nil {
+		return
+	}
+
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if _, err := spawnAfter(tx, expense, userID); err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to spawn next recurring expense")
+		return
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit recurring expense")
+	}
+}
+
+// spawnAfter spawns the occurrence following expense if expense is the latest
+// occurrence of an active series.
+func spawnAfter(tx *gorm.DB, expense *models.PlannedExpense, performedBy uuid.UUID) (*models.PlannedExpense, error) {
+	if expense.RecurrenceID == nil {
+		return nil, nil
+	}
+
+	var series models.ExpenseRecurrence
+	if err := tx.Preload("Tags").Where("id = ?", *expense.RecurrenceID).First(&series).Error; err != nil {
+		return nil, err
+	}
+	if !series.IsActive || series.LastExpenseID == nil || *series.LastExpenseID != expense.ID {
+		return nil, nil
+	}
+
+	return spawnNextOccurrence(tx, &series, performedBy)
+}
+
+// spawnNextOccurrence creates the series' next occurrence from its template
+// and advances the schedule, deactivating the series after its end date. It
+// returns nil when another request advanced the series first.
+func spawnNextOccurrence(tx *gorm.DB, series *models.ExpenseRecurrence, performedBy uuid.UUID) (*models.PlannedExpense, error) {
+	status, approvers, err := occurrenceStatus(tx, series)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate := series.NextDueDate
+	next := &models.PlannedExpense{
+		BaseModel:      models.BaseModel{ID: uuid.New()},
+		Item:           series.Item,
+		Description:    series.Description,
+		EstimatedPrice: series.EstimatedPrice,
+		Category:       series.Category,
+		Status:         status,
+		Priority:       series.Priority,
+		GroupID:        series.GroupID,
+		UserID:         series.UserID,
+		DueDate:        &dueDate,
+		RecurrenceID:   &series.ID,
+		Tags:           series.Tags,
+	}
+
+	nextDueDate := occurrenceDate(series.Frequency, series.Interval, series.StartDate, series.Occurrences+1)
+	isActive := series.EndDate == nil || !nextDueDate.After(*series.EndDate)
+
+	// Advance the series only from the state it was read in
+	result := tx.Model(&models.ExpenseRecurrence{}).
+		Where("id = ? AND occurrences = ?", series.ID, series.Occurrences).
+		Updates(map[string]interface{}{
+			"occurrences":     series.Occurrences + 1,
+			"last_expense_id": next.ID,
+			"next_due_date":   nextDueDate,
+			"is_active":       isActive,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	if err := tx.Create(next).Error; err != nil {
+		return nil, err
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "planned_expense",
+		EntityID:    next.ID,
+		Action:      "spawn_occurrence",
+		Changes:     map[string]interface{}{"recurrence_id": series.ID.String(), "due_date": dueDate},
+		PerformedBy: performedBy,
+		GroupID:     series.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		return nil, err
+	}
+
+	if notifications := approvalRequests(next, approvers); len(notifications) > 0 {
+		if err := tx.Create(&notifications).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	series.Occurrences++
+	series.LastExpenseID = &next.ID
+	series.NextDueDate = nextDueDate
+	series.IsActive = isActive
+
+	return next, nil
+}
+
+// occurrenceStatus applies the group's approval policy to a new occurrence of
+// a series, returning its status and the members who can approve it.
+func occurrenceStatus(tx *gorm.DB, series *models.ExpenseRecurrence) (string, []uuid.UUID, error) {
+	if series.GroupID == nil {
+		return "planned", nil, nil
+	}
+
+	var policies []models.ApprovalPolicy
+	if err := tx.Where("group_id = ?", *series.GroupID).Limit(1).Find(&policies).Error; err != nil {
+		return "", nil, err
+	}
+	if len(policies) == 0 || !requiresApproval(&policies[0], series.EstimatedPrice) {
+		return "planned", nil, nil
+	}
+
+	var members []models.UserGroup
+	if err := tx.Where("group_id = ?", *series.GroupID).Find(&members).Error; err != nil {
+		return "", nil, err
+	}
+
+	approvers, hold := approvalHold(&policies[0], members, series.UserID, series.EstimatedPrice)
+	if !hold {
+		return "planned", nil, nil
+	}
+	return "pending_approval", approvers, nil
+}
+
+// scheduleSeries (re)starts a series' schedule with its first occurrence due
+// on startDate. That occurrence already exists.
+func scheduleSeries(series *models.ExpenseRecurrence, startDate time.Time, recurrence *dto.RecurrenceRequest) {
+	series.Frequency = recurrence.Frequency
+	series.Interval = recurrence.Interval
+	if series.Interval < 1 {
+		series.Interval = 1
+	}
+	series.EndDate = recurrence.EndDate
+	series.StartDate = startDate
+	series.Occurrences = 1
+	series.NextDueDate = occurrenceDate(series.Frequency, series.Interval, startDate, 1)
+	series.IsActive = series.EndDate == nil || !series.NextDueDate.After(*series.EndDate)
+}
+
+// occurrenceDate returns the due date of occurrence n (from 0) of a series.
+// Monthly and yearly dates are computed from the start date rather than the
+// previous occurrence, so the 31st stays on the last day of shorter months
+// without drifting.
+func occurrenceDate(frequency string, interval int, start time.Time, n int) time.Time {
+	switch frequency {
+	case "weekly":
+		return start.AddDate(0, 0, 7*interval*n)
+	case "yearly":
+		return addMonthsClamped(start, 12*interval*n)
+	default:
+		return addMonthsClamped(start, interval*n)
+	}
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	first = first.AddDate(0, months, 0)
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+func mapRecurrenceToResponse(series *models.ExpenseRecurrence) *dto.RecurrenceResponse {
+	if series == nil || series.ID == uuid.Nil {
+		return nil
+	}
+
+	response := &dto.RecurrenceResponse{
+		ID:          series.ID,
+		Frequency:   series.Frequency,
+		Interval:    series.Interval,
+		StartDate:   series.StartDate,
+		EndDate:     series.EndDate,
+		IsActive:    series.IsActive,
+		Occurrences: series.Occurrences,
+	}
+	if series.IsActive {
+		nextDueDate := series.NextDueDate
+		response.NextDueDate = &nextDueDate
+	}
+
+	return response
+}
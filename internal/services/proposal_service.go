@@ -0,0 +1,651 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultProposalQuorum = 50
+	maxProposalDays       = 90
+)
+
+type ProposalService interface {
+	CreateProposal(userID, expenseID uuid.UUID, req dto.CreateProposalRequest) (*dto.ProposalResponse, error)
+	GetProposal(userID, expenseID uuid.UUID) (*dto.ProposalResponse, error)
+	GetGroupProposals(userID, groupID uuid.UUID, filter dto.ProposalFilter, page, limit int) ([]dto.ProposalResponse, int64, error)
+	CastVote(userID, expenseID uuid.UUID, req dto.CastVoteRequest) (*dto.ProposalResponse, error)
+	CancelProposal(userID, expenseID uuid.UUID) error
+	CloseDueProposals() (int, error)
+}
+
+type proposalService struct {
+	proposalRepo     repositories.ProposalRepository
+	expenseRepo      repositories.PlannedExpenseRepository
+	groupRepo        repositories.GroupRepository
+	auditRepo        repositories.AuditLogRepository
+	notificationRepo repositories.NotificationRepository
+	db               *gorm.DB
+}
+
+func NewProposalService(
+	proposalRepo repositories.ProposalRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+	notificationRepo repositories.NotificationRepository,
+	db *gorm.DB,
+) ProposalService {
+	return &proposalService{
+		proposalRepo:     proposalRepo,
+		expenseRepo:      expenseRepo,
+		groupRepo:        groupRepo,
+		auditRepo:        auditRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
+	}
+}
+
+func (s *proposalService) CreateProposal(userID, expenseID uuid.UUID, req dto.CreateProposalRequest) (*dto.ProposalResponse, error) {
+	expense, err := s.expenseRepo.FindByID(expenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
+	}
+	if expense.GroupID == nil {
+		return nil, &errors.AppError{Code: "NOT_GROUP_EXPENSE", Message: "Only group expenses can be put to a vote"}
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *expense.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	if expense.Status != "planned" {
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Only planned expenses can be put to a vote"}
+	}
+
+	now := time.Now()
+	if !req.Deadline.After(now) {
+		return nil, &errors.AppError{Code: "INVALID_DEADLINE", Message: "Deadline must be in the future"}
+	}
+	if req.Deadline.After(now.AddDate(0, 0, maxProposalDays)) {
+		return nil, &errors.AppError{Code: "INVALID_DEADLINE", Message: fmt.Sprintf("Deadline must be within %d days", maxProposalDays)}
+	}
+
+	quorum := req.Quorum
+	if quorum == 0 {
+		quorum = defaultProposalQuorum
+	}
+
+	proposal := &models.ExpenseProposal{
+		PlannedExpenseID: expenseID,
+		GroupID:          *expense.GroupID,
+		ProposedBy:       userID,
+		Description:      req.Description,
+		Quorum:           quorum,
+		Deadline:         req.Deadline,
+		Status:           "open",
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// The expense cannot be paid while it is being voted on
+	result := tx.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status = 'planned'", expenseID).
+		Update("status", "proposed")
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to update expense status")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create proposal"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Only planned expenses can be put to a vote"}
+	}
+
+	if err := tx.Create(proposal).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create proposal")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create proposal"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "expense_proposal",
+		EntityID: proposal.ID,
+		Action:   "create",
+		Changes: map[string]interface{}{
+			"expense_id": expenseID.String(),
+			"quorum":     quorum,
+			"deadline":   req.Deadline,
+		},
+		PerformedBy: userID,
+		GroupID:     expense.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create proposal"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create proposal"}
+	}
+
+	members, err := s.groupRepo.FindMembers(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+	}
+	notifyUsers(s.notificationRepo, activeMemberIDs(members, userID), "proposal_opened", "New proposal to vote on",
+		fmt.Sprintf("Should the group buy %s? Vote before %s", expense.Item, req.Deadline.Format(time.RFC1123)),
+		map[string]interface{}{
+			"proposal_id": proposal.ID.String(),
+			"expense_id":  expenseID.String(),
+			"group_id":    expense.GroupID.String(),
+		})
+
+	return s.getProposal(proposal.ID)
+}
+
+// GetProposal only reads the proposal: one past its deadline is reported as
+// expired and left for CloseDueProposals or the next vote to close.
+func (s *proposalService) GetProposal(userID, expenseID uuid.UUID) (*dto.ProposalResponse, error) {
+	proposal, err := s.findProposal(userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getProposal(proposal.ID)
+}
+
+func (s *proposalService) GetGroupProposals(userID, groupID uuid.UUID, filter dto.ProposalFilter, page, limit int) ([]dto.ProposalResponse, int64, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	proposals, total, err := s.proposalRepo.FindByGroup(groupID, filter.Status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group proposals")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposals"}
+	}
+
+	members, err := s.groupRepo.FindMembers(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposals"}
+	}
+
+	response := []dto.ProposalResponse{}
+	for i := range proposals {
+		response = append(response, *mapProposalToResponse(&proposals[i], members))
+	}
+
+	return response, total, nil
+}
+
+func (s *proposalService) CastVote(userID, expenseID uuid.UUID, req dto.CastVoteRequest) (*dto.ProposalResponse, error) {
+	proposal, err := s.findProposal(userID, expenseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if proposal.Status == "open" && !time.Now().Before(proposal.Deadline) {
+		if err := s.closeProposal(proposal); err != nil {
+			return nil, err
+		}
+	}
+	if proposal.Status != "open" {
+		return nil, &errors.AppError{Code: "VOTING_CLOSED", Message: "Voting on this proposal has closed"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the proposal so it cannot close between the check and the vote
+	var locked models.ExpenseProposal
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", proposal.ID).First(&locked).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to lock proposal")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+	if locked.Status != "open" || !time.Now().Before(locked.Deadline) {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "VOTING_CLOSED", Message: "Voting on this proposal has closed"}
+	}
+
+	previous := ""
+	var existing models.ProposalVote
+	err = tx.Where("proposal_id = ? AND user_id = ?", proposal.ID, userID).First(&existing).Error
+	if err == nil {
+		previous = existing.Vote
+	} else if err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to get vote")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+
+	vote := &models.ProposalVote{
+		ProposalID: proposal.ID,
+		UserID:     userID,
+		Vote:       req.Vote,
+	}
+
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "proposal_id"}, {Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"vote", "updated_at"}),
+	}).Create(vote).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to save vote")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+
+	// Create audit log
+	changes := map[string]interface{}{"vote": req.Vote}
+	if previous != "" {
+		changes["vote"] = map[string]interface{}{"old": previous, "new": req.Vote}
+	}
+	auditLog := &models.AuditLog{
+		Entity:      "expense_proposal",
+		EntityID:    proposal.ID,
+		Action:      "vote",
+		Changes:     changes,
+		PerformedBy: userID,
+		GroupID:     &proposal.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+
+	// The vote closes early once every member has voted
+	proposal, err = s.proposalRepo.FindByID(proposal.ID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposal"}
+	}
+	members, err := s.groupRepo.FindMembers(proposal.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cast vote"}
+	}
+	if tally := tallyProposal(proposal, members); tally.Cast == tally.Eligible {
+		if err := s.closeProposal(proposal); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getProposal(proposal.ID)
+}
+
+func (s *proposalService) CancelProposal(userID, expenseID uuid.UUID) error {
+	proposal, err := s.findProposal(userID, expenseID)
+	if err != nil {
+		return err
+	}
+
+	if proposal.ProposedBy != userID {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, proposal.GroupID)
+		if err != nil || userGroup.Role != "manager" {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Only the proposer or a manager can cancel a proposal"}
+		}
+	}
+	if proposal.Status != "open" {
+		return &errors.AppError{Code: "VOTING_CLOSED", Message: "Voting on this proposal has closed"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	result := tx.Model(&models.ExpenseProposal{}).
+		Where("id = ? AND status = 'open'", proposal.ID).
+		Updates(map[string]interface{}{"status": "cancelled", "closed_at": now})
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to cancel proposal")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cancel proposal"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return &errors.AppError{Code: "VOTING_CLOSED", Message: "Voting on this proposal has closed"}
+	}
+
+	if err := tx.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status = 'proposed'", proposal.PlannedExpenseID).
+		Update("status", "planned").Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update expense status")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cancel proposal"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "expense_proposal",
+		EntityID:    proposal.ID,
+		Action:      "cancel",
+		PerformedBy: userID,
+		GroupID:     &proposal.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cancel proposal"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to cancel proposal"}
+	}
+
+	return nil
+}
+
+// CloseDueProposals closes the open proposals whose deadline has passed and
+// returns how many it closed.
+func (s *proposalService) CloseDueProposals() (int, error) {
+	due, err := s.proposalRepo.FindDue(time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get due proposals")
+		return 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposals"}
+	}
+
+	closed := 0
+	for i := range due {
+		if err := s.closeProposal(&due[i]); err != nil {
+			continue
+		}
+		closed++
+	}
+
+	return closed, nil
+}
+
+// findProposal returns the latest proposal on an expense the user can see.
+func (s *proposalService) findProposal(userID, expenseID uuid.UUID) (*models.ExpenseProposal, error) {
+	proposal, err := s.proposalRepo.FindLatestByExpense(expenseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get proposal")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposal"}
+	}
+	if proposal == nil {
+		return nil, &errors.AppError{Code: "PROPOSAL_NOT_FOUND", Message: "Proposal not found"}
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, proposal.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	return proposal, nil
+}
+
+func (s *proposalService) getProposal(proposalID uuid.UUID) (*dto.ProposalResponse, error) {
+	proposal, err := s.proposalRepo.FindByID(proposalID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposal"}
+	}
+
+	members, err := s.groupRepo.FindMembers(proposal.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get proposal"}
+	}
+
+	return mapProposalToResponse(proposal, members), nil
+}
+
+// closeProposal settles an open proposal from its tally and moves the expense
+// back to planned when it passed, or to rejected when it failed, which also
+// stops its series.
+func (s *proposalService) closeProposal(proposal *models.ExpenseProposal) error {
+	members, err := s.groupRepo.FindMembers(proposal.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock the proposal and tally the votes cast up to now
+	var locked models.ExpenseProposal
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", proposal.ID).First(&locked).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to lock proposal")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+	if locked.Status != "open" {
+		// Closed by another request in the meantime
+		tx.Rollback()
+		return nil
+	}
+	var votes []models.ProposalVote
+	if err := tx.Where("proposal_id = ?", proposal.ID).Find(&votes).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to get votes")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+	proposal.Votes = votes
+
+	tally := tallyProposal(proposal, members)
+	outcome, expenseStatus := "failed", "rejected"
+	if tally.Passing {
+		outcome, expenseStatus = "passed", "planned"
+	}
+
+	now := time.Now()
+	result := tx.Model(&models.ExpenseProposal{}).
+		Where("id = ? AND status = 'open'", proposal.ID).
+		Updates(map[string]interface{}{"status": outcome, "closed_at": now})
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to close proposal")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+	if result.RowsAffected == 0 {
+		// Closed by another request in the meantime
+		tx.Rollback()
+		return nil
+	}
+
+	expenseResult := tx.Model(&models.PlannedExpense{}).
+		Where("id = ? AND status = 'proposed'", proposal.PlannedExpenseID).
+		Update("status", expenseStatus)
+	if expenseResult.Error != nil {
+		tx.Rollback()
+		log.Error().Err(expenseResult.Error).Msg("Failed to update expense status")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+
+	// A rejected occurrence stops its series, as when it is rejected on approval
+	if expenseStatus == "rejected" && expenseResult.RowsAffected > 0 && proposal.PlannedExpense.RecurrenceID != nil {
+		if err := tx.Model(&models.ExpenseRecurrence{}).Where("id = ?", *proposal.PlannedExpense.RecurrenceID).
+			Update("is_active", false).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to stop expense series")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+		}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "expense_proposal",
+		EntityID: proposal.ID,
+		Action:   "close",
+		Changes: map[string]interface{}{
+			"outcome":    outcome,
+			"yes":        tally.Yes,
+			"no":         tally.No,
+			"abstain":    tally.Abstain,
+			"eligible":   tally.Eligible,
+			"quorum_met": tally.QuorumMet,
+		},
+		PerformedBy: proposal.ProposedBy,
+		GroupID:     &proposal.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to close proposal"}
+	}
+
+	proposal.Status = outcome
+	proposal.ClosedAt = &now
+
+	item := proposal.PlannedExpense.Item
+	message := fmt.Sprintf("The group voted to buy %s (%d yes, %d no)", item, tally.Yes, tally.No)
+	if !tally.Passing {
+		message = fmt.Sprintf("The group voted against buying %s (%d yes, %d no)", item, tally.Yes, tally.No)
+		if !tally.QuorumMet {
+			message = fmt.Sprintf("The vote on %s failed: only %d of %d needed votes were cast", item, tally.Cast, tally.QuorumVotes)
+		}
+	}
+	notifyUsers(s.notificationRepo, activeMemberIDs(members, uuid.Nil), "proposal_closed", "Vote closed", message,
+		map[string]interface{}{
+			"proposal_id": proposal.ID.String(),
+			"expense_id":  proposal.PlannedExpenseID.String(),
+			"group_id":    proposal.GroupID.String(),
+			"outcome":     outcome,
+		})
+
+	return nil
+}
+
+// tallyProposal counts the votes of the group's current active members, so
+// the votes of members who have left no longer count.
+func tallyProposal(proposal *models.ExpenseProposal, members []models.UserGroup) dto.ProposalTally {
+	eligible := make(map[uuid.UUID]bool)
+	for _, member := range members {
+		if member.Status == "active" {
+			eligible[member.UserID] = true
+		}
+	}
+
+	tally := dto.ProposalTally{Eligible: len(eligible)}
+	for _, vote := range proposal.Votes {
+		if !eligible[vote.UserID] {
+			continue
+		}
+		switch vote.Vote {
+		case "yes":
+			tally.Yes++
+		case "no":
+			tally.No++
+		default:
+			tally.Abstain++
+		}
+	}
+	tally.Cast = tally.Yes + tally.No + tally.Abstain
+
+	tally.QuorumVotes = (tally.Eligible*proposal.Quorum + 99) / 100
+	if tally.QuorumVotes < 1 {
+		tally.QuorumVotes = 1
+	}
+	tally.QuorumMet = tally.Cast >= tally.QuorumVotes
+	tally.Passing = tally.QuorumMet && tally.Yes > tally.No
+	return tally
+}
+
+// activeMemberIDs returns the group's active members other than except.
+func activeMemberIDs(members []models.UserGroup, except uuid.UUID) []uuid.UUID {
+	var userIDs []uuid.UUID
+	for _, member := range members {
+		if member.Status == "active" && member.UserID != except {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	return userIDs
+}
+
+func mapProposalToResponse(proposal *models.ExpenseProposal, members []models.UserGroup) *dto.ProposalResponse {
+	response := &dto.ProposalResponse{
+		ID:             proposal.ID,
+		ExpenseID:      proposal.PlannedExpenseID,
+		Item:           proposal.PlannedExpense.Item,
+		EstimatedPrice: proposal.PlannedExpense.EstimatedPrice,
+		GroupID:        proposal.GroupID,
+		ProposedBy:     proposal.ProposedBy,
+		ProposerName:   proposal.Proposer.FirstName + " " + proposal.Proposer.LastName,
+		Description:    proposal.Description,
+		Quorum:         proposal.Quorum,
+		Deadline:       proposal.Deadline,
+		Status:         proposal.Status,
+		Expired:        proposal.Status == "open" && !time.Now().Before(proposal.Deadline),
+		ClosedAt:       proposal.ClosedAt,
+		Tally:          tallyProposal(proposal, members),
+		Votes:          []dto.ProposalVoteResponse{},
+		CreatedAt:      proposal.CreatedAt,
+	}
+
+	for _, vote := range proposal.Votes {
+		item := dto.ProposalVoteResponse{
+			UserID:    vote.UserID,
+			Vote:      vote.Vote,
+			UpdatedAt: vote.UpdatedAt,
+		}
+		if vote.User != nil {
+			item.FirstName = vote.User.FirstName
+			item.LastName = vote.User.LastName
+		}
+		response.Votes = append(response.Votes, item)
+	}
+
+	return response
+}
@@ -0,0 +1,534 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ReimbursementService interface {
+	PayPersonally(userID, groupID uuid.UUID, req dto.PayPersonallyRequest) (*dto.ReimbursementResponse, error)
+	GetGroupReimbursements(userID, groupID uuid.UUID, filter dto.ReimbursementFilter, page, limit int) ([]dto.ReimbursementResponse, int64, int64, error)
+	SettleReimbursement(userID, groupID, reimbursementID uuid.UUID, req dto.SettleReimbursementRequest) (*dto.ReimbursementResponse, error)
+}
+
+type reimbursementService struct {
+	reimbursementRepo repositories.ReimbursementRepository
+	expenseRepo       repositories.PlannedExpenseRepository
+	userRepo          repositories.UserRepository
+	groupRepo         repositories.GroupRepository
+	notificationRepo  repositories.NotificationRepository
+	db                *gorm.DB
+}
+
+func NewReimbursementService(
+	reimbursementRepo repositories.ReimbursementRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+	notificationRepo repositories.NotificationRepository,
+	db *gorm.DB,
+) ReimbursementService {
+	return &reimbursementService{
+		reimbursementRepo: reimbursementRepo,
+		expenseRepo:       expenseRepo,
+		userRepo:          userRepo,
+		groupRepo:         groupRepo,
+		notificationRepo:  notificationRepo,
+		db:                db,
+	}
+}
+
+// PayPersonally records a member paying for a group expense themselves. The
+// group balance is untouched; instead the group owes the member the amount
+// until a manager settles the reimbursement.
+func (s *reimbursementService) PayPersonally(userID, groupID uuid.UUID, req dto.PayPersonallyRequest) (*dto.ReimbursementResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	// Get planned expense
+	expense, err := s.expenseRepo.FindByID(req.PlannedExpenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Planned expense not found"}
+	}
+
+	if expense.GroupID == nil || *expense.GroupID != groupID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Expense does not belong to this group"}
+	}
+
+	if err := checkPayable(expense); err != nil {
+		return nil, err
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	fundingSource := "external"
+	var personalTransaction *models.Transaction
+	if !req.External {
+		fundingSource = "personal"
+
+		// Get user and check balance
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+		}
+
+		personalAmount, err := convertTransferAmount(req.ActualPrice, group.Currency, user.Currency, req.ExchangeRate)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if user.Balance < personalAmount {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient personal balance"}
+		}
+
+		// Update user balance (debit)
+		user.Balance -= personalAmount
+
+		// Create personal transaction (debit)
+		personalTransaction = &models.Transaction{
+			OwnerType:        "USER",
+			OwnerID:          userID,
+			Type:             "DEBIT",
+			Amount:           personalAmount,
+			Currency:         user.Currency,
+			Balance:          user.Balance,
+			Category:         expense.Category,
+			Source:           "expense_payment",
+			Description:      req.Description,
+			GroupID:          &groupID,
+			PaidBy:           &userID,
+			PlannedExpenseID: &expense.ID,
+			UserID:           userID,
+			Metadata: map[string]interface{}{
+				"expense_payment": true,
+				"paid_for_group":  true,
+				"expense_id":      expense.ID.String(),
+			},
+		}
+		if user.Currency != group.Currency {
+			personalTransaction.Metadata["exchange_rate"] = req.ExchangeRate
+			personalTransaction.Metadata["original_amount"] = req.ActualPrice
+			personalTransaction.Metadata["original_currency"] = group.Currency
+		}
+
+		if err := tx.Create(personalTransaction).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to create personal transaction")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+		}
+
+		if err := tx.Save(user).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update user balance")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+		}
+	}
+
+	payment := &models.ExpensePayment{
+		PlannedExpenseID: expense.ID,
+		Amount:           req.ActualPrice,
+		PaidBy:           userID,
+		PaidAt:           time.Now(),
+		Description:      req.Description,
+	}
+	if personalTransaction != nil {
+		payment.TransactionID = &personalTransaction.ID
+	}
+
+	if err := recordPayment(tx, expense, payment, req.Partial); err != nil {
+		tx.Rollback()
+		if appErr, ok := err.(*errors.AppError); ok {
+			return nil, appErr
+		}
+		log.Error().Err(err).Msg("Failed to update planned expense")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+
+	// On a split expense the other members owe their shares instead of the group
+	status := "outstanding"
+	var splits int64
+	if err := tx.Model(&models.ExpenseSplit{}).Where("planned_expense_id = ?", expense.ID).Count(&splits).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to check expense split")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+	if splits > 0 {
+		status = "split"
+	}
+
+	reimbursement := &models.Reimbursement{
+		GroupID:          groupID,
+		UserID:           userID,
+		PlannedExpenseID: expense.ID,
+		PaymentID:        payment.ID,
+		Amount:           req.ActualPrice,
+		Currency:         group.Currency,
+		FundingSource:    fundingSource,
+		Status:           status,
+	}
+
+	if err := tx.Create(reimbursement).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create reimbursement")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+
+	// Paying off the latest occurrence of a recurring expense plans the next one
+	if expense.Status == "bought" {
+		if _, err := spawnAfter(tx, expense, userID); err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to spawn next recurring expense")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+		}
+	}
+
+	// Create audit logs
+	action := "record_payment"
+	if expense.Status == "bought" {
+		action = "mark_as_paid"
+	}
+	expenseAuditLog := &models.AuditLog{
+		Entity:   "planned_expense",
+		EntityID: expense.ID,
+		Action:   action,
+		Changes: map[string]interface{}{
+			"amount":         req.ActualPrice,
+			"paid_amount":    expense.PaidAmount,
+			"status":         expense.Status,
+			"paid_by":        userID.String(),
+			"funding_source": fundingSource,
+		},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	reimbursementAuditLog := &models.AuditLog{
+		Entity:      "reimbursement",
+		EntityID:    reimbursement.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"amount": req.ActualPrice, "member_id": userID.String(), "funding_source": fundingSource},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := tx.Create(expenseAuditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+
+	if err := tx.Create(reimbursementAuditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+	}
+
+	// Let the managers know the group owes money
+	members, err := s.groupRepo.FindMembers(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+	}
+	var managers []uuid.UUID
+	for _, member := range members {
+		if member.Status == "active" && member.Role == "manager" && member.UserID != userID {
+			managers = append(managers, member.UserID)
+		}
+	}
+	notifyUsers(s.notificationRepo, managers, "reimbursement_owed", "Member paid for a group expense",
+		fmt.Sprintf("%s was paid personally; the group owes %s", expense.Item, formatAmount(req.ActualPrice, group.Currency)),
+		map[string]interface{}{
+			"reimbursement_id": reimbursement.ID.String(),
+			"expense_id":       expense.ID.String(),
+			"group_id":         groupID.String(),
+		})
+
+	return s.getReimbursement(reimbursement.ID)
+}
+
+func (s *reimbursementService) GetGroupReimbursements(userID, groupID uuid.UUID, filter dto.ReimbursementFilter, page, limit int) ([]dto.ReimbursementResponse, int64, int64, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, 0, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	reimbursements, total, err := s.reimbursementRepo.FindByGroup(groupID, filter.Status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get reimbursements")
+		return nil, 0, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get reimbursements"}
+	}
+
+	outstanding, err := s.reimbursementRepo.SumOutstanding(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to sum outstanding reimbursements")
+		return nil, 0, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get reimbursements"}
+	}
+
+	response := []dto.ReimbursementResponse{}
+	for i := range reimbursements {
+		response = append(response, *mapReimbursementToResponse(&reimbursements[i]))
+	}
+
+	return response, total, outstanding, nil
+}
+
+// SettleReimbursement pays a member back from the group balance with a group
+// debit and a matching credit to the member's personal balance.
+func (s *reimbursementService) SettleReimbursement(userID, groupID, reimbursementID uuid.UUID, req dto.SettleReimbursementRequest) (*dto.ReimbursementResponse, error) {
+	// Check if user is a manager of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can settle reimbursements"}
+	}
+
+	reimbursement, err := s.reimbursementRepo.FindByID(reimbursementID)
+	if err != nil || reimbursement.GroupID != groupID {
+		return nil, &errors.AppError{Code: "REIMBURSEMENT_NOT_FOUND", Message: "Reimbursement not found"}
+	}
+
+	if reimbursement.Status == "split" {
+		return nil, &errors.AppError{Code: "SPLIT_EXPENSE", Message: "Payments for split expenses are settled between members"}
+	}
+	if reimbursement.Status != "outstanding" {
+		return nil, &errors.AppError{Code: "ALREADY_SETTLED", Message: "Reimbursement is already settled"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Get group and check balance
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	if group.Balance < reimbursement.Amount {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient group balance"}
+	}
+
+	member, err := s.userRepo.FindByID(reimbursement.UserID)
+	if err != nil {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
+	memberAmount, err := convertTransferAmount(reimbursement.Amount, group.Currency, member.Currency, req.ExchangeRate)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	description := "Reimbursement"
+	if reimbursement.PlannedExpense != nil {
+		description = "Reimbursement for " + reimbursement.PlannedExpense.Item
+	}
+
+	// Update group balance (debit)
+	group.Balance -= reimbursement.Amount
+
+	// Create group transaction (debit)
+	groupTransaction := &models.Transaction{
+		OwnerType:   "GROUP",
+		OwnerID:     groupID,
+		Type:        "DEBIT",
+		Amount:      reimbursement.Amount,
+		Currency:    group.Currency,
+		Balance:     group.Balance,
+		Category:    "reimbursement",
+		Source:      "reimbursement",
+		Description: description,
+		GroupID:     &groupID,
+		PaidBy:      &userID,
+		UserID:      userID,
+		Metadata: map[string]interface{}{
+			"reimbursement_id": reimbursement.ID.String(),
+			"member_id":        reimbursement.UserID.String(),
+		},
+	}
+
+	if err := tx.Create(groupTransaction).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create group transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	// Update member balance (credit)
+	member.Balance += memberAmount
+
+	// Create personal transaction (credit)
+	personalTransaction := &models.Transaction{
+		OwnerType:   "USER",
+		OwnerID:     member.ID,
+		Type:        "CREDIT",
+		Amount:      memberAmount,
+		Currency:    member.Currency,
+		Balance:     member.Balance,
+		Category:    "reimbursement",
+		Source:      "group_reimbursement",
+		Description: description,
+		GroupID:     &groupID,
+		UserID:      member.ID,
+		Metadata: map[string]interface{}{
+			"reimbursement_id": reimbursement.ID.String(),
+			"group_id":         groupID.String(),
+		},
+	}
+	if member.Currency != group.Currency {
+		personalTransaction.Metadata["exchange_rate"] = req.ExchangeRate
+		personalTransaction.Metadata["original_amount"] = reimbursement.Amount
+		personalTransaction.Metadata["original_currency"] = group.Currency
+	}
+
+	if err := tx.Create(personalTransaction).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create personal transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	// Only one settlement can win
+	now := time.Now()
+	result := tx.Model(&models.Reimbursement{}).
+		Where("id = ? AND status = 'outstanding'", reimbursement.ID).
+		Updates(map[string]interface{}{
+			"status":         "settled",
+			"settled_by":     userID,
+			"settled_at":     now,
+			"transaction_id": groupTransaction.ID,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to settle reimbursement")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "ALREADY_SETTLED", Message: "Reimbursement is already settled"}
+	}
+
+	// Save updated balances
+	if err := tx.Save(group).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update group balance")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	if err := tx.Save(member).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update user balance")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "reimbursement",
+		EntityID: reimbursement.ID,
+		Action:   "settle",
+		Changes: map[string]interface{}{
+			"amount":         reimbursement.Amount,
+			"member_id":      reimbursement.UserID.String(),
+			"transaction_id": groupTransaction.ID.String(),
+		},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to settle reimbursement"}
+	}
+
+	notifyUsers(s.notificationRepo, []uuid.UUID{reimbursement.UserID}, "reimbursement_settled", "Reimbursement received",
+		fmt.Sprintf("%s: %s was added to your balance", description, formatAmount(memberAmount, member.Currency)),
+		map[string]interface{}{
+			"reimbursement_id": reimbursement.ID.String(),
+			"group_id":         groupID.String(),
+		})
+
+	return s.getReimbursement(reimbursement.ID)
+}
+
+func (s *reimbursementService) getReimbursement(reimbursementID uuid.UUID) (*dto.ReimbursementResponse, error) {
+	reimbursement, err := s.reimbursementRepo.FindByID(reimbursementID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get reimbursement data"}
+	}
+	return mapReimbursementToResponse(reimbursement), nil
+}
+
+func mapReimbursementToResponse(reimbursement *models.Reimbursement) *dto.ReimbursementResponse {
+	response := &dto.ReimbursementResponse{
+		ID:                      reimbursement.ID,
+		GroupID:                 reimbursement.GroupID,
+		UserID:                  reimbursement.UserID,
+		ExpenseID:               reimbursement.PlannedExpenseID,
+		PaymentID:               reimbursement.PaymentID,
+		Amount:                  reimbursement.Amount,
+		FormattedAmount:         formatAmount(reimbursement.Amount, reimbursement.Currency),
+		Currency:                reimbursement.Currency,
+		FundingSource:           reimbursement.FundingSource,
+		Status:                  reimbursement.Status,
+		SettledBy:               reimbursement.SettledBy,
+		SettledAt:               reimbursement.SettledAt,
+		SettlementTransactionID: reimbursement.TransactionID,
+		CreatedAt:               reimbursement.CreatedAt,
+	}
+
+	if reimbursement.User != nil {
+		response.MemberName = reimbursement.User.FirstName + " " + reimbursement.User.LastName
+	}
+	if reimbursement.PlannedExpense != nil {
+		response.Item = reimbursement.PlannedExpense.Item
+	}
+	if reimbursement.Payment != nil {
+		response.PaymentTransactionID = reimbursement.Payment.TransactionID
+	}
+
+	return response
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	reminderUpcoming   = "upcoming"
+	reminderOverdue    = "overdue"
+	reminderEscalation = "escalation"
+)
+
+type ReminderService interface {
+	SendReminders() (int, error)
+}
+
+type reminderService struct {
+	reminderRepo   repositories.ReminderRepository
+	expenseRepo    repositories.PlannedExpenseRepository
+	groupRepo      repositories.GroupRepository
+	daysBefore     []int
+	daysAfter      []int
+	escalationDays int
+}
+
+func NewReminderService(
+	reminderRepo repositories.ReminderRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	groupRepo repositories.GroupRepository,
+	daysBefore, daysAfter []int,
+	escalationDays int,
+) ReminderService {
+	return &reminderService{
+		reminderRepo:   reminderRepo,
+		expenseRepo:    expenseRepo,
+		groupRepo:      groupRepo,
+		daysBefore:     daysBefore,
+		daysAfter:      daysAfter,
+		escalationDays: escalationDays,
+	}
+}
+
+// SendReminders notifies the owners of unpaid planned expenses as their due
+// date approaches and after it passes, and escalates group expenses still
+// unpaid after the grace period to the group managers. Each reminder is sent
+// once per due date; when a run is missed only the latest stage goes out. It
+// returns the number of notifications created.
+func (s *reminderService) SendReminders() (int, error) {
+	today := startOfDay(time.Now())
+
+	lookBack := s.escalationDays
+	for _, days := range s.daysAfter {
+		lookBack = max(lookBack, days)
+	}
+	lookAhead := 0
+	for _, days := range s.daysBefore {
+		lookAhead = max(lookAhead, days)
+	}
+
+	expenses, err := s.expenseRepo.FindDueBetween(today.AddDate(0, 0, -lookBack), today.AddDate(0, 0, lookAhead+1))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get expenses due for reminders")
+		return 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to send reminders"}
+	}
+	if len(expenses) == 0 {
+		return 0, nil
+	}
+
+	expenseIDs := make([]uuid.UUID, len(expenses))
+	for i, expense := range expenses {
+		expenseIDs[i] = expense.ID
+	}
+	sent, err := s.reminderRepo.FindByExpenses(expenseIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get sent reminders")
+		return 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to send reminders"}
+	}
+	alreadySent := make(map[string]bool, len(sent))
+	for _, reminder := range sent {
+		alreadySent[reminderKey(reminder)] = true
+	}
+
+	managers := make(map[uuid.UUID][]uuid.UUID)
+	sentCount := 0
+	for i := range expenses {
+		expense := &expenses[i]
+		for _, reminder := range s.dueReminders(expense, today) {
+			if alreadySent[reminderKey(reminder)] {
+				continue
+			}
+
+			recipients := []uuid.UUID{expense.UserID}
+			if reminder.Kind == reminderEscalation {
+				ids, ok := managers[*expense.GroupID]
+				if !ok {
+					ids = s.groupManagers(*expense.GroupID)
+					managers[*expense.GroupID] = ids
+				}
+				recipients = ids
+			}
+			if len(recipients) == 0 {
+				continue
+			}
+
+			title, message := reminderText(expense, reminder)
+			data := map[string]interface{}{
+				"expense_id": expense.ID,
+				"due_date":   expense.DueDate,
+			}
+			if expense.GroupID != nil {
+				data["group_id"] = expense.GroupID
+			}
+			notifications := make([]models.Notification, 0, len(recipients))
+			for _, userID := range recipients {
+				notifications = append(notifications, models.Notification{
+					UserID:  userID,
+					Type:    "expense_" + reminder.Kind,
+					Title:   title,
+					Message: message,
+					Data:    data,
+				})
+			}
+
+			// The reminder and its notifications are stored together, so a
+			// failure leaves the reminder to be sent on the next run
+			recorded, err := s.reminderRepo.Record(&reminder, notifications)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to record reminder")
+				continue
+			}
+			if recorded {
+				sentCount += len(notifications)
+			}
+		}
+	}
+
+	return sentCount, nil
+}
+
+// dueReminders returns the reminders the expense has reached today: the
+// nearest upcoming stage before the due date, or the latest overdue stage
+// after it, plus the escalation once the grace period is over.
+func (s *reminderService) dueReminders(expense *models.PlannedExpense, today time.Time) []models.ExpenseReminder {
+	daysUntil := int(startOfDay(*expense.DueDate).Sub(today).Hours() / 24)
+	reminder := func(kind string, days int) models.ExpenseReminder {
+		return models.ExpenseReminder{
+			PlannedExpenseID: expense.ID,
+			DueDate:          *expense.DueDate,
+			Kind:             kind,
+			Days:             days,
+		}
+	}
+
+	var reminders []models.ExpenseReminder
+	if daysUntil >= 0 {
+		stage := -1
+		for _, days := range s.daysBefore {
+			if days >= daysUntil && (stage < 0 || days < stage) {
+				stage = days
+			}
+		}
+		if stage >= 0 {
+			reminders = append(reminders, reminder(reminderUpcoming, stage))
+		}
+		return reminders
+	}
+
+	overdue := -daysUntil
+	stage := -1
+	for _, days := range s.daysAfter {
+		if days <= overdue && days > stage {
+			stage = days
+		}
+	}
+	if stage >= 0 {
+		reminders = append(reminders, reminder(reminderOverdue, stage))
+	}
+	if expense.GroupID != nil && s.escalationDays > 0 && overdue >= s.escalationDays {
+		reminders = append(reminders, reminder(reminderEscalation, s.escalationDays))
+	}
+	return reminders
+}
+
+// groupManagers returns the active managers of a group. A lookup failure is
+// logged and yields no recipients so the escalation is retried next run.
+func (s *reminderService) groupManagers(groupID uuid.UUID) []uuid.UUID {
+	members, err := s.groupRepo.FindMembers(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil
+	}
+
+	var managers []uuid.UUID
+	for _, member := range members {
+		if member.Status == "active" && member.Role == "manager" {
+			managers = append(managers, member.UserID)
+		}
+	}
+	return managers
+}
+
+func reminderText(expense *models.PlannedExpense, reminder models.ExpenseReminder) (string, string) {
+	currency := expense.User.Currency
+	groupName := ""
+	if expense.Group != nil {
+		currency = expense.Group.Currency
+		groupName = expense.Group.Name
+	}
+	amount := formatAmount(expense.EstimatedPrice-expense.PaidAmount, currency)
+
+	switch reminder.Kind {
+	case reminderUpcoming:
+		if reminder.Days == 0 {
+			return "Expense due today", fmt.Sprintf("%s (%s) is due today", expense.Item, amount)
+		}
+		return "Expense due soon", fmt.Sprintf("%s (%s) is due in %s", expense.Item, amount, pluralDays(reminder.Days))
+	case reminderOverdue:
+		return "Expense overdue", fmt.Sprintf("%s (%s) was due %s ago", expense.Item, amount, pluralDays(reminder.Days))
+	default:
+		return "Group expense overdue", fmt.Sprintf("%s (%s) in %s has been overdue for %s", expense.Item, amount, groupName, pluralDays(reminder.Days))
+	}
+}
+
+func reminderKey(reminder models.ExpenseReminder) string {
+	return fmt.Sprintf("%s|%d|%s|%d", reminder.PlannedExpenseID, reminder.DueDate.Unix(), reminder.Kind, reminder.Days)
+}
+
+func pluralDays(days int) string {
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
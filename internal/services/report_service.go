@@ -7,6 +7,7 @@ import (
 	"balanca/pkg/errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,7 +21,10 @@ type ReportService interface {
 	GetGroupDateRangeReport(userID, groupID uuid.UUID, startDate, endDate time.Time) (*dto.GroupReportResponse, error)
 	GetCategoryBreakdown(userID uuid.UUID, startDate, endDate time.Time) ([]dto.CategorySummary, error)
 	GetSourceBreakdown(userID uuid.UUID, startDate, endDate time.Time) ([]dto.SourceSummary, error)
+	GetTagBreakdown(userID uuid.UUID, startDate, endDate time.Time) ([]dto.TagSummary, error)
 	GetMemberContributions(groupID uuid.UUID, startDate, endDate time.Time) ([]dto.MemberContribution, error)
+	GetPersonalMonthlyStatement(userID uuid.UUID, year, month int) (*dto.MonthlyStatement, error)
+	GetGroupMonthlyStatement(userID, groupID uuid.UUID, year, month int) (*dto.GroupStatement, error)
 }
 
 type reportService struct {
@@ -42,6 +46,17 @@ func NewReportService(
 }
 
 func (s *reportService) GetPersonalMonthlyReport(userID uuid.UUID, year, month int) (*dto.MonthlyReportResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
+	return s.personalMonthlyReport(user, year, month)
+}
+
+func (s *reportService) personalMonthlyReport(user *models.User, year, month int) (*dto.MonthlyReportResponse, error) {
+	userID := user.ID
+
 	// Get date range for the month
 	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	endDate := startDate.AddDate(0, 1, 0).Add(-time.Nanosecond)
@@ -86,23 +101,16 @@ func (s *reportService) GetPersonalMonthlyReport(userID uuid.UUID, year, month i
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
-	// Map transactions to response
-	var transactionResponses []dto.TransactionResponse
-	for _, transaction := range transactions {
-		transactionResponses = append(transactionResponses, dto.TransactionResponse{
-			ID:          transaction.ID,
-			OwnerType:   transaction.OwnerType,
-			OwnerID:     transaction.OwnerID,
-			Type:        transaction.Type,
-			Amount:      transaction.Amount,
-			Balance:     transaction.Balance,
-			Category:    transaction.Category,
-			Source:      transaction.Source,
-			Description: transaction.Description,
-			CreatedAt:   transaction.CreatedAt.Format(time.RFC3339),
-		})
+	// Get tag breakdown
+	tags, err := s.transactionRepo.GetTagSummary("USER", userID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
+	// Map transactions to response
+	transactionResponses := mapReportTransactions(transactions)
+
 	// Map categories to response
 	var categoryResponses []dto.CategorySummary
 	totalExpensesFloat := float64(totalExpenses)
@@ -148,6 +156,7 @@ func (s *reportService) GetPersonalMonthlyReport(userID uuid.UUID, year, month i
 	return &dto.MonthlyReportResponse{
 		Month:           startDate.Month().String(),
 		Year:            year,
+		Currency:        user.Currency,
 		TotalIncome:     totalIncome,
 		TotalExpenses:   totalExpenses,
 		NetBalance:      totalIncome - totalExpenses,
@@ -156,10 +165,18 @@ func (s *reportService) GetPersonalMonthlyReport(userID uuid.UUID, year, month i
 		Transactions:    transactionResponses,
 		Categories:      categoryResponses,
 		Sources:         sourceResponses,
+		Tags:            mapTagSummaries(tags, totalExpenses),
+
+		ForeignCurrencies: summarizeForeignCurrencies(transactions),
 	}, nil
 }
 
 func (s *reportService) GetPersonalDateRangeReport(userID uuid.UUID, startDate, endDate time.Time) (*dto.MonthlyReportResponse, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
 	// Similar logic to monthly report but with custom date range
 	transactions, err := s.transactionRepo.FindByDateRange("USER", userID, startDate, endDate)
 	if err != nil {
@@ -200,23 +217,16 @@ func (s *reportService) GetPersonalDateRangeReport(userID uuid.UUID, startDate,
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
-	// Map transactions to response
-	var transactionResponses []dto.TransactionResponse
-	for _, transaction := range transactions {
-		transactionResponses = append(transactionResponses, dto.TransactionResponse{
-			ID:          transaction.ID,
-			OwnerType:   transaction.OwnerType,
-			OwnerID:     transaction.OwnerID,
-			Type:        transaction.Type,
-			Amount:      transaction.Amount,
-			Balance:     transaction.Balance,
-			Category:    transaction.Category,
-			Source:      transaction.Source,
-			Description: transaction.Description,
-			CreatedAt:   transaction.CreatedAt.Format(time.RFC3339),
-		})
+	// Get tag breakdown
+	tags, err := s.transactionRepo.GetTagSummary("USER", userID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
+	// Map transactions to response
+	transactionResponses := mapReportTransactions(transactions)
+
 	// Map categories to response
 	var categoryResponses []dto.CategorySummary
 	totalExpensesFloat := float64(totalExpenses)
@@ -262,6 +272,7 @@ func (s *reportService) GetPersonalDateRangeReport(userID uuid.UUID, startDate,
 	return &dto.MonthlyReportResponse{
 		Month:           fmt.Sprintf("%s to %s", startDate.Format("Jan 02"), endDate.Format("Jan 02, 2006")),
 		Year:            startDate.Year(),
+		Currency:        user.Currency,
 		TotalIncome:     totalIncome,
 		TotalExpenses:   totalExpenses,
 		NetBalance:      totalIncome - totalExpenses,
@@ -270,6 +281,9 @@ func (s *reportService) GetPersonalDateRangeReport(userID uuid.UUID, startDate,
 		Transactions:    transactionResponses,
 		Categories:      categoryResponses,
 		Sources:         sourceResponses,
+		Tags:            mapTagSummaries(tags, totalExpenses),
+
+		ForeignCurrencies: summarizeForeignCurrencies(transactions),
 	}, nil
 }
 
@@ -337,9 +351,24 @@ func (s *reportService) GetGroupMonthlyReport(userID, groupID uuid.UUID, year, m
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
+	// Get tag breakdown
+	tags, err := s.transactionRepo.GetTagSummary("GROUP", groupID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
+	// Get settlements between members
+	settlements, err := s.getSettlements(groupID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get settlements")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
 	return &dto.GroupReportResponse{
 		GroupID:         groupID,
 		GroupName:       group.Name,
+		Currency:        group.Currency,
 		Period:          fmt.Sprintf("%s %d", startDate.Month().String(), year),
 		TotalIncome:     totalIncome,
 		TotalExpenses:   totalExpenses,
@@ -349,6 +378,10 @@ func (s *reportService) GetGroupMonthlyReport(userID, groupID uuid.UUID, year, m
 		Members:         members,
 		ExternalSources: externalSources,
 		Expenses:        expenses,
+		Tags:            mapTagSummaries(tags, totalExpenses),
+		Settlements:     settlements,
+
+		ForeignCurrencies: summarizeForeignCurrencies(transactions),
 	}, nil
 }
 
@@ -412,9 +445,24 @@ func (s *reportService) GetGroupDateRangeReport(userID, groupID uuid.UUID, start
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
 	}
 
+	// Get tag breakdown
+	tags, err := s.transactionRepo.GetTagSummary("GROUP", groupID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
+	// Get settlements between members
+	settlements, err := s.getSettlements(groupID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get settlements")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
 	return &dto.GroupReportResponse{
 		GroupID:         groupID,
 		GroupName:       group.Name,
+		Currency:        group.Currency,
 		Period:          fmt.Sprintf("%s to %s", startDate.Format("Jan 02"), endDate.Format("Jan 02, 2006")),
 		TotalIncome:     totalIncome,
 		TotalExpenses:   totalExpenses,
@@ -424,6 +472,10 @@ func (s *reportService) GetGroupDateRangeReport(userID, groupID uuid.UUID, start
 		Members:         members,
 		ExternalSources: externalSources,
 		Expenses:        expenses,
+		Tags:            mapTagSummaries(tags, totalExpenses),
+		Settlements:     settlements,
+
+		ForeignCurrencies: summarizeForeignCurrencies(transactions),
 	}, nil
 }
 
@@ -497,10 +549,74 @@ func (s *reportService) GetSourceBreakdown(userID uuid.UUID, startDate, endDate
 	return response, nil
 }
 
+func (s *reportService) GetTagBreakdown(userID uuid.UUID, startDate, endDate time.Time) ([]dto.TagSummary, error) {
+	tags, err := s.transactionRepo.GetTagSummary("USER", userID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get tag breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get tag breakdown"}
+	}
+
+	// Percentages are relative to all expenses in the period, not to the
+	// tagged subset, since one transaction may carry several tags.
+	var totalExpenses int64
+	categories, err := s.transactionRepo.GetCategorySummary("USER", userID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get category breakdown")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get tag breakdown"}
+	}
+	for _, amount := range categories {
+		totalExpenses += amount
+	}
+
+	return mapTagSummaries(tags, totalExpenses), nil
+}
+
 func (s *reportService) GetMemberContributions(groupID uuid.UUID, startDate, endDate time.Time) ([]dto.MemberContribution, error) {
 	return s.getMemberContributions(groupID, startDate, endDate)
 }
 
+// GetPersonalMonthlyStatement returns what a printed personal statement
+// shows: the monthly report and the name of the account holder.
+func (s *reportService) GetPersonalMonthlyStatement(userID uuid.UUID, year, month int) (*dto.MonthlyStatement, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+	}
+
+	report, err := s.personalMonthlyReport(user, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MonthlyStatement{
+		AccountName: strings.TrimSpace(user.FirstName + " " + user.LastName),
+		Report:      *report,
+	}, nil
+}
+
+// GetGroupMonthlyStatement returns the group monthly report together with
+// the transactions of the month, which the report itself leaves out.
+func (s *reportService) GetGroupMonthlyStatement(userID, groupID uuid.UUID, year, month int) (*dto.GroupStatement, error) {
+	report, err := s.GetGroupMonthlyReport(userID, groupID, year, month)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	endDate := startDate.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+	transactions, err := s.transactionRepo.FindByDateRange("GROUP", groupID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get transactions for statement")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to generate report"}
+	}
+
+	return &dto.GroupStatement{
+		Report:       *report,
+		Transactions: mapReportTransactions(transactions),
+	}, nil
+}
+
 // Helper methods
 func (s *reportService) getBalanceBefore(ownerType string, ownerID uuid.UUID, date time.Time) (int64, error) {
 	// Get all transactions before the date
@@ -625,12 +741,14 @@ func (s *reportService) getGroupExpensesBreakdown(groupID uuid.UUID, startDate,
 		Count     int
 	}
 
+	// Split transactions are broken down by their line items
 	err := s.transactionRepo.GetDB().Model(&models.Transaction{}).
-		Select("transactions.category, transactions.paid_by, users.first_name, users.last_name, SUM(transactions.amount) as total, COUNT(*) as count").
+		Select("COALESCE(transaction_line_items.category, transactions.category) as category, transactions.paid_by, users.first_name, users.last_name, SUM(COALESCE(transaction_line_items.amount, transactions.amount)) as total, COUNT(DISTINCT transactions.id) as count").
+		Joins("LEFT JOIN transaction_line_items ON transaction_line_items.transaction_id = transactions.id AND transaction_line_items.deleted_at IS NULL").
 		Joins("LEFT JOIN users ON users.id = transactions.paid_by").
 		Where("transactions.owner_type = 'GROUP' AND transactions.owner_id = ? AND transactions.type = 'DEBIT' AND transactions.created_at BETWEEN ? AND ?",
 			groupID, startDate, endDate).
-		Group("transactions.category, transactions.paid_by, users.first_name, users.last_name").
+		Group("COALESCE(transaction_line_items.category, transactions.category), transactions.paid_by, users.first_name, users.last_name").
 		Scan(&expenses).Error
 
 	if err != nil {
@@ -655,3 +773,132 @@ func (s *reportService) getGroupExpensesBreakdown(groupID uuid.UUID, startDate,
 
 	return response, nil
 }
+
+// getSettlements totals the settle-up payments members confirmed in the
+// period, per payer and receiver.
+func (s *reportService) getSettlements(groupID uuid.UUID, startDate, endDate time.Time) ([]dto.SettlementSummary, error) {
+	var settlements []struct {
+		FromUserID    uuid.UUID
+		FromFirstName string
+		FromLastName  string
+		ToUserID      uuid.UUID
+		ToFirstName   string
+		ToLastName    string
+		Total         int64
+		Count         int
+	}
+
+	err := s.transactionRepo.GetDB().Model(&models.Settlement{}).
+		Select("settlements.from_user_id, payers.first_name as from_first_name, payers.last_name as from_last_name, settlements.to_user_id, receivers.first_name as to_first_name, receivers.last_name as to_last_name, SUM(settlements.amount) as total, COUNT(*) as count").
+		Joins("LEFT JOIN users payers ON payers.id = settlements.from_user_id").
+		Joins("LEFT JOIN users receivers ON receivers.id = settlements.to_user_id").
+		Where("settlements.group_id = ? AND settlements.status = 'confirmed' AND settlements.confirmed_at BETWEEN ? AND ?",
+			groupID, startDate, endDate).
+		Group("settlements.from_user_id, payers.first_name, payers.last_name, settlements.to_user_id, receivers.first_name, receivers.last_name").
+		Scan(&settlements).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	var response []dto.SettlementSummary
+	for _, settlement := range settlements {
+		response = append(response, dto.SettlementSummary{
+			FromUserID: settlement.FromUserID,
+			FromName:   settlement.FromFirstName + " " + settlement.FromLastName,
+			ToUserID:   settlement.ToUserID,
+			ToName:     settlement.ToFirstName + " " + settlement.ToLastName,
+			Amount:     settlement.Total,
+			Count:      settlement.Count,
+		})
+	}
+
+	// Sort by amount (descending)
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Amount > response[j].Amount
+	})
+
+	return response, nil
+}
+
+// mapReportTransactions converts the transactions listed on a statement.
+func mapReportTransactions(transactions []models.Transaction) []dto.TransactionResponse {
+	var response []dto.TransactionResponse
+	for _, transaction := range transactions {
+		response = append(response, dto.TransactionResponse{
+			ID:          transaction.ID,
+			OwnerType:   transaction.OwnerType,
+			OwnerID:     transaction.OwnerID,
+			Type:        transaction.Type,
+			Amount:      transaction.Amount,
+			Currency:    transaction.Currency,
+			Balance:     transaction.Balance,
+			Category:    transaction.Category,
+			Source:      transaction.Source,
+			Description: transaction.Description,
+			CreatedAt:   transaction.CreatedAt.Format(time.RFC3339),
+			LineItems:   mapLineItemsToResponse(transaction.LineItems),
+
+			FormattedAmount:  formatAmount(transaction.Amount, transaction.Currency),
+			FormattedBalance: formatAmount(transaction.Balance, transaction.Currency),
+			OriginalAmount:   transaction.OriginalAmount,
+			OriginalCurrency: transaction.OriginalCurrency,
+			ExchangeRate:     transaction.ExchangeRate,
+		})
+	}
+	return response
+}
+
+// mapTagSummaries converts per-tag totals into report rows sorted by amount.
+func mapTagSummaries(tags map[string]int64, totalExpenses int64) []dto.TagSummary {
+	var response []dto.TagSummary
+	for tag, amount := range tags {
+		percentage := 0.0
+		if totalExpenses > 0 {
+			percentage = float64(amount) / float64(totalExpenses) * 100
+		}
+		response = append(response, dto.TagSummary{
+			Tag:        tag,
+			Amount:     amount,
+			Percentage: percentage,
+		})
+	}
+
+	sort.Slice(response, func(i, j int) bool {
+		return response[i].Amount > response[j].Amount
+	})
+
+	return response
+}
+
+// summarizeForeignCurrencies totals the transactions entered in a foreign
+// currency, keeping both the original and the converted amounts.
+func summarizeForeignCurrencies(transactions []models.Transaction) []dto.ForeignCurrencySummary {
+	index := make(map[string]int)
+	var summaries []dto.ForeignCurrencySummary
+	for _, transaction := range transactions {
+		if transaction.OriginalAmount == nil || transaction.OriginalCurrency == "" {
+			continue
+		}
+
+		key := transaction.OriginalCurrency + transaction.Type
+		i, ok := index[key]
+		if !ok {
+			i = len(summaries)
+			index[key] = i
+			summaries = append(summaries, dto.ForeignCurrencySummary{
+				Currency: transaction.OriginalCurrency,
+				Type:     transaction.Type,
+			})
+		}
+		summaries[i].OriginalAmount += *transaction.OriginalAmount
+		summaries[i].Amount += transaction.Amount
+		summaries[i].Count++
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Amount > summaries[j].Amount
+	})
+
+	return summaries
+}
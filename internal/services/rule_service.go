@@ -0,0 +1,500 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const defaultRulePriority = 100
+
+type RuleService interface {
+	CreatePersonalRule(userID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error)
+	CreateGroupRule(userID, groupID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error)
+	GetPersonalRules(userID uuid.UUID) ([]dto.RuleResponse, error)
+	GetGroupRules(userID, groupID uuid.UUID) ([]dto.RuleResponse, error)
+	UpdateRule(userID, ruleID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error)
+	DeleteRule(userID, ruleID uuid.UUID) error
+	DryRunRule(userID, ruleID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error)
+	ApplyPersonalRules(userID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error)
+	ApplyGroupRules(userID, groupID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error)
+}
+
+type ruleService struct {
+	ruleRepo        repositories.RuleRepository
+	transactionRepo repositories.TransactionRepository
+	groupRepo       repositories.GroupRepository
+	tagRepo         repositories.TagRepository
+	categoryRepo    repositories.CategoryRepository
+	auditRepo       repositories.AuditLogRepository
+	db              *gorm.DB
+}
+
+func NewRuleService(
+	ruleRepo repositories.RuleRepository,
+	transactionRepo repositories.TransactionRepository,
+	groupRepo repositories.GroupRepository,
+	tagRepo repositories.TagRepository,
+	categoryRepo repositories.CategoryRepository,
+	auditRepo repositories.AuditLogRepository,
+	db *gorm.DB,
+) RuleService {
+	return &ruleService{
+		ruleRepo:        ruleRepo,
+		transactionRepo: transactionRepo,
+		groupRepo:       groupRepo,
+		tagRepo:         tagRepo,
+		categoryRepo:    categoryRepo,
+		auditRepo:       auditRepo,
+		db:              db,
+	}
+}
+
+func (s *ruleService) CreatePersonalRule(userID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error) {
+	rule := &models.CategorizationRule{
+		UserID:    &userID,
+		CreatedBy: userID,
+	}
+
+	return s.createRule(userID, rule, req)
+}
+
+func (s *ruleService) CreateGroupRule(userID, groupID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error) {
+	// Rules rewrite every member's transactions, so only managers set them
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group rules"}
+	}
+
+	rule := &models.CategorizationRule{
+		GroupID:   &groupID,
+		CreatedBy: userID,
+	}
+
+	return s.createRule(userID, rule, req)
+}
+
+func (s *ruleService) createRule(userID uuid.UUID, rule *models.CategorizationRule, req dto.RuleRequest) (*dto.RuleResponse, error) {
+	if err := s.fillRule(userID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(rule); err != nil {
+		log.Error().Err(err).Msg("Failed to create rule")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create rule"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "categorization_rule",
+		EntityID:    rule.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"name": rule.Name, "category": rule.Category},
+		PerformedBy: userID,
+		GroupID:     rule.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapRuleToResponse(rule), nil
+}
+
+func (s *ruleService) GetPersonalRules(userID uuid.UUID) ([]dto.RuleResponse, error) {
+	rules, err := s.ruleRepo.FindByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal rules")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get rules"}
+	}
+
+	return mapRulesToResponse(rules), nil
+}
+
+func (s *ruleService) GetGroupRules(userID, groupID uuid.UUID) ([]dto.RuleResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	rules, err := s.ruleRepo.FindByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group rules")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get rules"}
+	}
+
+	return mapRulesToResponse(rules), nil
+}
+
+func (s *ruleService) UpdateRule(userID, ruleID uuid.UUID, req dto.RuleRequest) (*dto.RuleResponse, error) {
+	rule, err := s.findRule(userID, ruleID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	old := map[string]interface{}{"name": rule.Name, "category": rule.Category, "priority": rule.Priority, "is_active": rule.IsActive}
+	if err := s.fillRule(userID, rule, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(rule); err != nil {
+		log.Error().Err(err).Msg("Failed to update rule")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update rule"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "categorization_rule",
+		EntityID: rule.ID,
+		Action:   "update",
+		Changes: map[string]interface{}{
+			"old": old,
+			"new": map[string]interface{}{"name": rule.Name, "category": rule.Category, "priority": rule.Priority, "is_active": rule.IsActive},
+		},
+		PerformedBy: userID,
+		GroupID:     rule.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapRuleToResponse(rule), nil
+}
+
+func (s *ruleService) DeleteRule(userID, ruleID uuid.UUID) error {
+	rule, err := s.findRule(userID, ruleID, true)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ruleRepo.Delete(ruleID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete rule")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete rule"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "categorization_rule",
+		EntityID:    ruleID,
+		Action:      "delete",
+		Changes:     map[string]interface{}{"name": rule.Name},
+		PerformedBy: userID,
+		GroupID:     rule.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+// DryRunRule shows which past transactions the rule would change on its own,
+// whether or not it is active and regardless of other rules. Nothing is
+// written.
+func (s *ruleService) DryRunRule(userID, ruleID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error) {
+	rule, err := s.findRule(userID, ruleID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.run(userID, rule.GroupID, []models.CategorizationRule{*rule}, req, false)
+}
+
+func (s *ruleService) ApplyPersonalRules(userID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error) {
+	rules, err := s.ruleRepo.FindActive(userID, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rules")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+	}
+
+	return s.run(userID, nil, rules, req, true)
+}
+
+func (s *ruleService) ApplyGroupRules(userID, groupID uuid.UUID, req dto.RuleRunRequest) (*dto.RuleRunResponse, error) {
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group rules"}
+	}
+
+	rules, err := s.ruleRepo.FindActive(userID, &groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get rules")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+	}
+
+	return s.run(userID, &groupID, rules, req, true)
+}
+
+// run matches past transactions of the personal or group ledger against the
+// rules and, when apply is set, rewrites the ones that change in a single
+// database transaction. Transactions written by the services themselves
+// (transfers, contributions, expense payments) are left alone, and so are
+// the ones split into line items, whose categories reports read instead.
+func (s *ruleService) run(userID uuid.UUID, groupID *uuid.UUID, rules []models.CategorizationRule, req dto.RuleRunRequest, apply bool) (*dto.RuleRunResponse, error) {
+	ownerType, ownerID := "USER", userID
+	if groupID != nil {
+		ownerType, ownerID = "GROUP", *groupID
+	}
+
+	var startDate time.Time
+	if req.StartDate != nil {
+		startDate = *req.StartDate
+	}
+	endDate := time.Now()
+	if req.EndDate != nil {
+		endDate = *req.EndDate
+	}
+	if endDate.Before(startDate) {
+		return nil, &errors.AppError{Code: "INVALID_DATE_RANGE", Message: "End date must be after start date"}
+	}
+
+	transactions, err := s.transactionRepo.FindByDateRange(ownerType, ownerID, startDate, endDate)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get transactions")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check transactions"}
+	}
+
+	type pendingChange struct {
+		transaction *models.Transaction
+		rule        *models.CategorizationRule
+		addTag      bool
+	}
+
+	response := &dto.RuleRunResponse{Applied: apply, Changes: []dto.RuleChangeResponse{}}
+	var pending []pendingChange
+	for i := range transactions {
+		transaction := &transactions[i]
+		if reservedCategoryNames[strings.ToLower(transaction.Category)] || transaction.PlannedExpenseID != nil ||
+			len(transaction.LineItems) > 0 {
+			continue
+		}
+		response.Checked++
+
+		rule := firstMatchingRule(rules, transaction.Type, transaction.Amount, transaction.Description)
+		if rule == nil {
+			continue
+		}
+
+		addTag := rule.Tag != nil && !hasTag(transaction.Tags, rule.Tag.ID)
+		if rule.Category == transaction.Category && !addTag {
+			continue
+		}
+
+		change := dto.RuleChangeResponse{
+			TransactionID:   transaction.ID,
+			RuleID:          rule.ID,
+			Description:     transaction.Description,
+			Type:            transaction.Type,
+			Amount:          transaction.Amount,
+			FormattedAmount: formatAmount(transaction.Amount, transaction.Currency),
+			CreatedAt:       transaction.CreatedAt.Format(time.RFC3339),
+			OldCategory:     transaction.Category,
+			NewCategory:     rule.Category,
+		}
+		if addTag {
+			change.AddedTag = rule.Tag.Name
+		}
+		response.Changes = append(response.Changes, change)
+		pending = append(pending, pendingChange{transaction: transaction, rule: rule, addTag: addTag})
+	}
+	response.Changed = len(response.Changes)
+
+	if !apply || len(pending) == 0 {
+		return response, nil
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for _, change := range pending {
+		changes := map[string]interface{}{"rule_id": change.rule.ID}
+
+		if change.rule.Category != change.transaction.Category {
+			if err := tx.Model(&models.Transaction{}).Where("id = ?", change.transaction.ID).
+				Update("category", change.rule.Category).Error; err != nil {
+				tx.Rollback()
+				log.Error().Err(err).Msg("Failed to update transaction category")
+				return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+			}
+			changes["category"] = map[string]interface{}{"old": change.transaction.Category, "new": change.rule.Category}
+		}
+
+		if change.addTag {
+			if err := tx.Model(change.transaction).Association("Tags").Append(change.rule.Tag); err != nil {
+				tx.Rollback()
+				log.Error().Err(err).Msg("Failed to tag transaction")
+				return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+			}
+			changes["tag"] = change.rule.Tag.Name
+		}
+
+		// Create audit log
+		auditLog := &models.AuditLog{
+			Entity:      "transaction",
+			EntityID:    change.transaction.ID,
+			Action:      "apply_rule",
+			Changes:     changes,
+			PerformedBy: userID,
+			GroupID:     groupID,
+		}
+
+		if err := tx.Create(auditLog).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to create audit log")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to apply rules"}
+	}
+
+	return response, nil
+}
+
+// findRule loads a rule the user may see. With manage set, group rules
+// additionally require the manager role.
+func (s *ruleService) findRule(userID, ruleID uuid.UUID, manage bool) (*models.CategorizationRule, error) {
+	rule, err := s.ruleRepo.FindByID(ruleID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "RULE_NOT_FOUND", Message: "Rule not found"}
+	}
+
+	if rule.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *rule.GroupID)
+		if err != nil || userGroup.Status != "active" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		if manage && userGroup.Role != "manager" {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can manage group rules"}
+		}
+	} else if rule.UserID == nil || *rule.UserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	return rule, nil
+}
+
+// fillRule validates a request and copies it onto the rule, resolving the
+// category against the catalog and the tag against the rule's scope.
+func (s *ruleService) fillRule(userID uuid.UUID, rule *models.CategorizationRule, req dto.RuleRequest) error {
+	description := strings.TrimSpace(req.DescriptionContains)
+	if description == "" && req.Type == "" && req.MinAmount == nil && req.MaxAmount == nil {
+		return &errors.AppError{Code: "INVALID_RULE", Message: "A rule needs at least one condition"}
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		return &errors.AppError{Code: "INVALID_RULE", Message: "Minimum amount cannot exceed maximum amount"}
+	}
+
+	category, err := resolveCategory(s.categoryRepo, "category", userID, rule.GroupID, req.Category)
+	if err != nil {
+		return err
+	}
+
+	var tag *models.Tag
+	if req.TagID != nil {
+		tags, err := resolveTags(s.tagRepo, userID, rule.GroupID, []uuid.UUID{*req.TagID})
+		if err != nil {
+			return err
+		}
+		tag = &tags[0]
+	}
+
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Priority = defaultRulePriority
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	rule.IsActive = req.IsActive == nil || *req.IsActive
+	rule.DescriptionContains = description
+	rule.Type = req.Type
+	rule.MinAmount = req.MinAmount
+	rule.MaxAmount = req.MaxAmount
+	rule.Category = category
+	rule.TagID = req.TagID
+	rule.Tag = tag
+
+	return nil
+}
+
+// firstMatchingRule returns the first of the rules, which must be in
+// evaluation order, that matches a transaction.
+func firstMatchingRule(rules []models.CategorizationRule, transactionType string, amount int64, description string) *models.CategorizationRule {
+	description = strings.ToLower(description)
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Type != "" && rule.Type != transactionType {
+			continue
+		}
+		if rule.MinAmount != nil && amount < *rule.MinAmount {
+			continue
+		}
+		if rule.MaxAmount != nil && amount > *rule.MaxAmount {
+			continue
+		}
+		if rule.DescriptionContains != "" && !strings.Contains(description, strings.ToLower(rule.DescriptionContains)) {
+			continue
+		}
+		return rule
+	}
+	return nil
+}
+
+func hasTag(tags []models.Tag, tagID uuid.UUID) bool {
+	for _, tag := range tags {
+		if tag.ID == tagID {
+			return true
+		}
+	}
+	return false
+}
+
+func mapRuleToResponse(rule *models.CategorizationRule) *dto.RuleResponse {
+	response := &dto.RuleResponse{
+		ID:                  rule.ID,
+		Name:                rule.Name,
+		UserID:              rule.UserID,
+		GroupID:             rule.GroupID,
+		Priority:            rule.Priority,
+		IsActive:            rule.IsActive,
+		DescriptionContains: rule.DescriptionContains,
+		Type:                rule.Type,
+		MinAmount:           rule.MinAmount,
+		MaxAmount:           rule.MaxAmount,
+		Category:            rule.Category,
+		CreatedBy:           rule.CreatedBy,
+		CreatedAt:           rule.CreatedAt.Format(time.RFC3339),
+	}
+
+	if rule.Tag != nil {
+		response.Tag = mapTagToResponse(rule.Tag)
+	}
+
+	return response
+}
+
+func mapRulesToResponse(rules []models.CategorizationRule) []dto.RuleResponse {
+	var response []dto.RuleResponse
+	for _, rule := range rules {
+		response = append(response, *mapRuleToResponse(&rule))
+	}
+	return response
+}
@@ -0,0 +1,539 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SettlementService interface {
+	RecordSettlement(userID, groupID uuid.UUID, req dto.RecordSettlementRequest) (*dto.SettlementResponse, error)
+	GetGroupSettlements(userID, groupID uuid.UUID, filter dto.SettlementFilter, page, limit int) ([]dto.SettlementResponse, int64, error)
+	GetSettlement(userID, groupID, settlementID uuid.UUID) (*dto.SettlementResponse, error)
+	ConfirmSettlement(userID, groupID, settlementID uuid.UUID, req dto.ConfirmSettlementRequest) (*dto.SettlementResponse, error)
+	RejectSettlement(userID, groupID, settlementID uuid.UUID, req dto.RejectSettlementRequest) (*dto.SettlementResponse, error)
+	CancelSettlement(userID, groupID, settlementID uuid.UUID) error
+}
+
+type settlementService struct {
+	settlementRepo   repositories.SettlementRepository
+	userRepo         repositories.UserRepository
+	groupRepo        repositories.GroupRepository
+	auditRepo        repositories.AuditLogRepository
+	notificationRepo repositories.NotificationRepository
+	db               *gorm.DB
+}
+
+func NewSettlementService(
+	settlementRepo repositories.SettlementRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+	notificationRepo repositories.NotificationRepository,
+	db *gorm.DB,
+) SettlementService {
+	return &settlementService{
+		settlementRepo:   settlementRepo,
+		userRepo:         userRepo,
+		groupRepo:        groupRepo,
+		auditRepo:        auditRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
+	}
+}
+
+// RecordSettlement records that the caller paid another member back. It
+// stays pending until the receiver confirms it.
+func (s *settlementService) RecordSettlement(userID, groupID uuid.UUID, req dto.RecordSettlementRequest) (*dto.SettlementResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	if req.ToUserID == userID {
+		return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "You cannot settle up with yourself"}
+	}
+
+	receiver, err := s.groupRepo.FindByUserAndGroup(req.ToUserID, groupID)
+	if err != nil || receiver.Status != "active" {
+		return nil, &errors.AppError{Code: "INVALID_MEMBER", Message: "You can only settle up with active members of the group"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	settlement := &models.Settlement{
+		GroupID:      groupID,
+		FromUserID:   userID,
+		ToUserID:     req.ToUserID,
+		Amount:       req.Amount,
+		Currency:     group.Currency,
+		Method:       req.Method,
+		Reference:    req.Reference,
+		Note:         req.Note,
+		MoveBalances: req.MoveBalances,
+		Status:       "pending",
+	}
+
+	// The payer's side is converted now so the rate they agreed to is kept
+	if req.MoveBalances {
+		payer, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+		}
+
+		fromAmount, err := convertTransferAmount(req.Amount, group.Currency, payer.Currency, req.ExchangeRate)
+		if err != nil {
+			return nil, err
+		}
+		if payer.Balance < fromAmount {
+			return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient personal balance"}
+		}
+
+		settlement.FromAmount = fromAmount
+		if payer.Currency != group.Currency {
+			settlement.FromExchangeRate = req.ExchangeRate
+		}
+	}
+
+	if err := s.db.Create(settlement).Error; err != nil {
+		log.Error().Err(err).Msg("Failed to create settlement")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to record settlement"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "settlement",
+		EntityID: settlement.ID,
+		Action:   "create",
+		Changes: map[string]interface{}{
+			"to_user_id":    req.ToUserID.String(),
+			"amount":        req.Amount,
+			"method":        req.Method,
+			"move_balances": req.MoveBalances,
+		},
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	result, err := s.getSettlement(settlement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyUsers(s.notificationRepo, []uuid.UUID{req.ToUserID}, "settlement_recorded", "Confirm a payment",
+		fmt.Sprintf("%s says they paid you %s. Please confirm you received it.", result.FromName, result.FormattedAmount),
+		map[string]interface{}{
+			"settlement_id": settlement.ID.String(),
+			"group_id":      groupID.String(),
+		})
+
+	return result, nil
+}
+
+func (s *settlementService) GetGroupSettlements(userID, groupID uuid.UUID, filter dto.SettlementFilter, page, limit int) ([]dto.SettlementResponse, int64, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	memberID, err := parseOptionalID(filter.MemberID)
+	if err != nil {
+		return nil, 0, &errors.AppError{Code: "INVALID_REQUEST", Message: "Invalid member ID"}
+	}
+
+	settlements, total, err := s.settlementRepo.FindByGroup(groupID, filter.Status, memberID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get settlements")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get settlements"}
+	}
+
+	response := []dto.SettlementResponse{}
+	for i := range settlements {
+		response = append(response, *mapSettlementToResponse(&settlements[i]))
+	}
+
+	return response, total, nil
+}
+
+func (s *settlementService) GetSettlement(userID, groupID, settlementID uuid.UUID) (*dto.SettlementResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	settlement, err := s.settlementRepo.FindByID(settlementID)
+	if err != nil || settlement.GroupID != groupID {
+		return nil, &errors.AppError{Code: "SETTLEMENT_NOT_FOUND", Message: "Settlement not found"}
+	}
+
+	return mapSettlementToResponse(settlement), nil
+}
+
+// ConfirmSettlement is the receiver acknowledging the payment. Settlements
+// that move balances debit the payer and credit the receiver at this point.
+func (s *settlementService) ConfirmSettlement(userID, groupID, settlementID uuid.UUID, req dto.ConfirmSettlementRequest) (*dto.SettlementResponse, error) {
+	settlement, err := s.pendingSettlement(userID, groupID, settlementID)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.ToUserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only the member who received the payment can confirm it"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	updates := map[string]interface{}{
+		"status":       "confirmed",
+		"confirmed_at": time.Now(),
+	}
+
+	var toAmount int64
+	var receiver *models.User
+	if settlement.MoveBalances {
+		payer, err := s.userRepo.FindByID(settlement.FromUserID)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+		}
+
+		receiver, err = s.userRepo.FindByID(settlement.ToUserID)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+		}
+
+		toAmount, err = convertTransferAmount(settlement.Amount, settlement.Currency, receiver.Currency, req.ExchangeRate)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if payer.Balance < settlement.FromAmount {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "The payer's personal balance is too low"}
+		}
+
+		// Update payer balance (debit)
+		payer.Balance -= settlement.FromAmount
+
+		// Create payer transaction (debit)
+		payerTransaction := &models.Transaction{
+			OwnerType:   "USER",
+			OwnerID:     payer.ID,
+			Type:        "DEBIT",
+			Amount:      settlement.FromAmount,
+			Currency:    payer.Currency,
+			Balance:     payer.Balance,
+			Category:    "settlement",
+			Source:      "settlement",
+			Description: "Settle up with " + receiver.FirstName + " " + receiver.LastName,
+			GroupID:     &groupID,
+			PaidBy:      &payer.ID,
+			UserID:      payer.ID,
+			Metadata: map[string]interface{}{
+				"settlement_id": settlement.ID.String(),
+				"to_user_id":    receiver.ID.String(),
+			},
+		}
+		if payer.Currency != settlement.Currency {
+			payerTransaction.Metadata["exchange_rate"] = settlement.FromExchangeRate
+			payerTransaction.Metadata["original_amount"] = settlement.Amount
+			payerTransaction.Metadata["original_currency"] = settlement.Currency
+		}
+
+		if err := tx.Create(payerTransaction).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to create payer transaction")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+		}
+
+		// Update receiver balance (credit)
+		receiver.Balance += toAmount
+
+		// Create receiver transaction (credit)
+		receiverTransaction := &models.Transaction{
+			OwnerType:   "USER",
+			OwnerID:     receiver.ID,
+			Type:        "CREDIT",
+			Amount:      toAmount,
+			Currency:    receiver.Currency,
+			Balance:     receiver.Balance,
+			Category:    "settlement",
+			Source:      "settlement",
+			Description: "Settle up from " + payer.FirstName + " " + payer.LastName,
+			GroupID:     &groupID,
+			UserID:      receiver.ID,
+			Metadata: map[string]interface{}{
+				"settlement_id": settlement.ID.String(),
+				"from_user_id":  payer.ID.String(),
+			},
+		}
+		if receiver.Currency != settlement.Currency {
+			receiverTransaction.Metadata["exchange_rate"] = req.ExchangeRate
+			receiverTransaction.Metadata["original_amount"] = settlement.Amount
+			receiverTransaction.Metadata["original_currency"] = settlement.Currency
+		}
+
+		if err := tx.Create(receiverTransaction).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to create receiver transaction")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+		}
+
+		// Save updated balances
+		if err := tx.Save(payer).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update payer balance")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+		}
+
+		if err := tx.Save(receiver).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update receiver balance")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+		}
+
+		updates["from_transaction_id"] = payerTransaction.ID
+		updates["to_transaction_id"] = receiverTransaction.ID
+	}
+
+	// Only one decision can win
+	result := tx.Model(&models.Settlement{}).
+		Where("id = ? AND status = 'pending'", settlement.ID).
+		Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to confirm settlement")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Settlement is no longer pending"}
+	}
+
+	// Create audit log
+	changes := map[string]interface{}{
+		"amount":        settlement.Amount,
+		"from_user_id":  settlement.FromUserID.String(),
+		"move_balances": settlement.MoveBalances,
+	}
+	if settlement.MoveBalances {
+		changes["from_amount"] = settlement.FromAmount
+		changes["to_amount"] = toAmount
+	}
+	auditLog := &models.AuditLog{
+		Entity:      "settlement",
+		EntityID:    settlement.ID,
+		Action:      "confirm",
+		Changes:     changes,
+		PerformedBy: userID,
+		GroupID:     &groupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to confirm settlement"}
+	}
+
+	response, err := s.getSettlement(settlement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyUsers(s.notificationRepo, []uuid.UUID{settlement.FromUserID}, "settlement_confirmed", "Payment confirmed",
+		fmt.Sprintf("%s confirmed receiving %s from you", response.ToName, response.FormattedAmount),
+		map[string]interface{}{
+			"settlement_id": settlement.ID.String(),
+			"group_id":      groupID.String(),
+		})
+
+	return response, nil
+}
+
+func (s *settlementService) RejectSettlement(userID, groupID, settlementID uuid.UUID, req dto.RejectSettlementRequest) (*dto.SettlementResponse, error) {
+	settlement, err := s.pendingSettlement(userID, groupID, settlementID)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.ToUserID != userID {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only the member who received the payment can reject it"}
+	}
+
+	if err := s.closeSettlement(userID, settlement, "rejected", req.Reason); err != nil {
+		return nil, err
+	}
+
+	response, err := s.getSettlement(settlement.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("%s did not confirm receiving %s from you", response.ToName, response.FormattedAmount)
+	if req.Reason != "" {
+		message += ": " + req.Reason
+	}
+	notifyUsers(s.notificationRepo, []uuid.UUID{settlement.FromUserID}, "settlement_rejected", "Payment not confirmed", message,
+		map[string]interface{}{
+			"settlement_id": settlement.ID.String(),
+			"group_id":      groupID.String(),
+		})
+
+	return response, nil
+}
+
+// CancelSettlement withdraws a settlement the caller recorded by mistake
+// before the receiver has answered.
+func (s *settlementService) CancelSettlement(userID, groupID, settlementID uuid.UUID) error {
+	settlement, err := s.pendingSettlement(userID, groupID, settlementID)
+	if err != nil {
+		return err
+	}
+	if settlement.FromUserID != userID {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Only the member who recorded the payment can cancel it"}
+	}
+
+	return s.closeSettlement(userID, settlement, "cancelled", "")
+}
+
+// pendingSettlement loads a settlement of the group that is still waiting for
+// the receiver.
+func (s *settlementService) pendingSettlement(userID, groupID, settlementID uuid.UUID) (*models.Settlement, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	settlement, err := s.settlementRepo.FindByID(settlementID)
+	if err != nil || settlement.GroupID != groupID {
+		return nil, &errors.AppError{Code: "SETTLEMENT_NOT_FOUND", Message: "Settlement not found"}
+	}
+
+	if settlement.Status != "pending" {
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Settlement is no longer pending"}
+	}
+
+	return settlement, nil
+}
+
+// closeSettlement ends a pending settlement without moving any money.
+func (s *settlementService) closeSettlement(userID uuid.UUID, settlement *models.Settlement, status, reason string) error {
+	action := "reject"
+	if status == "cancelled" {
+		action = "cancel"
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Model(&models.Settlement{}).
+		Where("id = ? AND status = 'pending'", settlement.ID).
+		Updates(map[string]interface{}{"status": status, "rejection_reason": reason})
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to update settlement")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to " + action + " settlement"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return &errors.AppError{Code: "INVALID_STATUS", Message: "Settlement is no longer pending"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "settlement",
+		EntityID:    settlement.ID,
+		Action:      action,
+		Changes:     map[string]interface{}{"amount": settlement.Amount, "reason": reason},
+		PerformedBy: userID,
+		GroupID:     &settlement.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to " + action + " settlement"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to " + action + " settlement"}
+	}
+
+	return nil
+}
+
+func (s *settlementService) getSettlement(settlementID uuid.UUID) (*dto.SettlementResponse, error) {
+	settlement, err := s.settlementRepo.FindByID(settlementID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get settlement data"}
+	}
+	return mapSettlementToResponse(settlement), nil
+}
+
+func mapSettlementToResponse(settlement *models.Settlement) *dto.SettlementResponse {
+	response := &dto.SettlementResponse{
+		ID:                settlement.ID,
+		GroupID:           settlement.GroupID,
+		FromUserID:        settlement.FromUserID,
+		ToUserID:          settlement.ToUserID,
+		Amount:            settlement.Amount,
+		FormattedAmount:   formatAmount(settlement.Amount, settlement.Currency),
+		Currency:          settlement.Currency,
+		Method:            settlement.Method,
+		Reference:         settlement.Reference,
+		Note:              settlement.Note,
+		MoveBalances:      settlement.MoveBalances,
+		Status:            settlement.Status,
+		RejectionReason:   settlement.RejectionReason,
+		ConfirmedAt:       settlement.ConfirmedAt,
+		FromTransactionID: settlement.FromTransactionID,
+		ToTransactionID:   settlement.ToTransactionID,
+		CreatedAt:         settlement.CreatedAt,
+	}
+	if settlement.FromUser != nil {
+		response.FromName = settlement.FromUser.FirstName + " " + settlement.FromUser.LastName
+	}
+	if settlement.ToUser != nil {
+		response.ToName = settlement.ToUser.FirstName + " " + settlement.ToUser.LastName
+	}
+	return response
+}
@@ -0,0 +1,772 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type ShoppingListService interface {
+	CreatePersonalList(userID uuid.UUID, req dto.CreateShoppingListRequest) (*dto.ShoppingListResponse, error)
+	CreateGroupList(userID, groupID uuid.UUID, req dto.CreateShoppingListRequest) (*dto.ShoppingListResponse, error)
+	GetPersonalLists(userID uuid.UUID, filter dto.ShoppingListFilter, page, limit int) ([]dto.ShoppingListResponse, int64, error)
+	GetGroupLists(userID, groupID uuid.UUID, filter dto.ShoppingListFilter, page, limit int) ([]dto.ShoppingListResponse, int64, error)
+	GetList(userID, listID uuid.UUID) (*dto.ShoppingListResponse, error)
+	UpdateList(userID, listID uuid.UUID, req dto.UpdateShoppingListRequest) (*dto.ShoppingListResponse, error)
+	DeleteList(userID, listID uuid.UUID) error
+	AddItem(userID, listID uuid.UUID, req dto.ShoppingListItemRequest) (*dto.ShoppingListResponse, error)
+	UpdateItem(userID, listID, itemID uuid.UUID, req dto.UpdateShoppingListItemRequest) (*dto.ShoppingListResponse, error)
+	DeleteItem(userID, listID, itemID uuid.UUID) error
+	CheckItem(userID, listID, itemID uuid.UUID, req dto.CheckItemRequest) (*dto.ShoppingListResponse, error)
+	Checkout(userID, listID uuid.UUID, req dto.CheckoutShoppingListRequest) (*dto.ShoppingListResponse, error)
+}
+
+type shoppingListService struct {
+	listRepo     repositories.ShoppingListRepository
+	expenseRepo  repositories.PlannedExpenseRepository
+	userRepo     repositories.UserRepository
+	groupRepo    repositories.GroupRepository
+	categoryRepo repositories.CategoryRepository
+	auditRepo    repositories.AuditLogRepository
+	db           *gorm.DB
+}
+
+func NewShoppingListService(
+	listRepo repositories.ShoppingListRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	userRepo repositories.UserRepository,
+	groupRepo repositories.GroupRepository,
+	categoryRepo repositories.CategoryRepository,
+	auditRepo repositories.AuditLogRepository,
+	db *gorm.DB,
+) ShoppingListService {
+	return &shoppingListService{
+		listRepo:     listRepo,
+		expenseRepo:  expenseRepo,
+		userRepo:     userRepo,
+		groupRepo:    groupRepo,
+		categoryRepo: categoryRepo,
+		auditRepo:    auditRepo,
+		db:           db,
+	}
+}
+
+func (s *shoppingListService) CreatePersonalList(userID uuid.UUID, req dto.CreateShoppingListRequest) (*dto.ShoppingListResponse, error) {
+	return s.createList(userID, nil, req)
+}
+
+func (s *shoppingListService) CreateGroupList(userID, groupID uuid.UUID, req dto.CreateShoppingListRequest) (*dto.ShoppingListResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	return s.createList(userID, &groupID, req)
+}
+
+func (s *shoppingListService) createList(userID uuid.UUID, groupID *uuid.UUID, req dto.CreateShoppingListRequest) (*dto.ShoppingListResponse, error) {
+	list := &models.ShoppingList{
+		Name:    strings.TrimSpace(req.Name),
+		UserID:  userID,
+		GroupID: groupID,
+		Status:  "open",
+	}
+
+	for _, itemReq := range req.Items {
+		item, err := s.buildItem(userID, list, itemReq)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, *item)
+	}
+
+	if err := s.listRepo.Create(list); err != nil {
+		log.Error().Err(err).Msg("Failed to create shopping list")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create shopping list"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "shopping_list",
+		EntityID:    list.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"name": list.Name, "items": len(list.Items)},
+		PerformedBy: userID,
+		GroupID:     groupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return s.getList(list.ID)
+}
+
+func (s *shoppingListService) GetPersonalLists(userID uuid.UUID, filter dto.ShoppingListFilter, page, limit int) ([]dto.ShoppingListResponse, int64, error) {
+	lists, total, err := s.listRepo.FindByUser(userID, filter.Status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get shopping lists")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get shopping lists"}
+	}
+
+	return mapShoppingListsToResponse(lists), total, nil
+}
+
+func (s *shoppingListService) GetGroupLists(userID, groupID uuid.UUID, filter dto.ShoppingListFilter, page, limit int) ([]dto.ShoppingListResponse, int64, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	lists, total, err := s.listRepo.FindByGroup(groupID, filter.Status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get shopping lists")
+		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get shopping lists"}
+	}
+
+	return mapShoppingListsToResponse(lists), total, nil
+}
+
+func (s *shoppingListService) GetList(userID, listID uuid.UUID) (*dto.ShoppingListResponse, error) {
+	list, _, err := s.accessList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	return mapShoppingListToResponse(list), nil
+}
+
+func (s *shoppingListService) UpdateList(userID, listID uuid.UUID, req dto.UpdateShoppingListRequest) (*dto.ShoppingListResponse, error) {
+	list, err := s.openList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	list.Name = strings.TrimSpace(req.Name)
+	if err := s.listRepo.Update(list); err != nil {
+		log.Error().Err(err).Msg("Failed to update shopping list")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update shopping list"}
+	}
+
+	return s.getList(listID)
+}
+
+// DeleteList deletes a list with its items. Group lists can be deleted by the
+// member who created them or a manager.
+func (s *shoppingListService) DeleteList(userID, listID uuid.UUID) error {
+	list, userGroup, err := s.accessList(userID, listID)
+	if err != nil {
+		return err
+	}
+
+	if list.UserID != userID && (userGroup == nil || userGroup.Role != "manager") {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Only the member who created the list or a manager can delete it"}
+	}
+
+	if err := s.listRepo.Delete(listID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete shopping list")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete shopping list"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "shopping_list",
+		EntityID:    listID,
+		Action:      "delete",
+		Changes:     map[string]interface{}{"name": list.Name, "status": list.Status},
+		PerformedBy: userID,
+		GroupID:     list.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+func (s *shoppingListService) AddItem(userID, listID uuid.UUID, req dto.ShoppingListItemRequest) (*dto.ShoppingListResponse, error) {
+	list, err := s.openList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.buildItem(userID, list, req)
+	if err != nil {
+		return nil, err
+	}
+	item.ListID = listID
+
+	if err := s.listRepo.CreateItem(item); err != nil {
+		log.Error().Err(err).Msg("Failed to add shopping list item")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to add item"}
+	}
+
+	return s.getList(listID)
+}
+
+func (s *shoppingListService) UpdateItem(userID, listID, itemID uuid.UUID, req dto.UpdateShoppingListItemRequest) (*dto.ShoppingListResponse, error) {
+	list, err := s.openList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.listRepo.FindItemByID(itemID)
+	if err != nil || item.ListID != listID {
+		return nil, &errors.AppError{Code: "ITEM_NOT_FOUND", Message: "Item not found"}
+	}
+
+	if req.Name != nil {
+		item.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+	}
+	if req.UnitPrice != nil {
+		item.UnitPrice = *req.UnitPrice
+	}
+	if req.Category != nil {
+		category, err := s.itemCategory(userID, list.GroupID, *req.Category)
+		if err != nil {
+			return nil, err
+		}
+		item.Category = category
+	}
+
+	if err := s.listRepo.UpdateItem(item); err != nil {
+		log.Error().Err(err).Msg("Failed to update shopping list item")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update item"}
+	}
+
+	return s.getList(listID)
+}
+
+func (s *shoppingListService) DeleteItem(userID, listID, itemID uuid.UUID) error {
+	if _, err := s.openList(userID, listID); err != nil {
+		return err
+	}
+
+	item, err := s.listRepo.FindItemByID(itemID)
+	if err != nil || item.ListID != listID {
+		return &errors.AppError{Code: "ITEM_NOT_FOUND", Message: "Item not found"}
+	}
+
+	if err := s.listRepo.DeleteItem(itemID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete shopping list item")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete item"}
+	}
+
+	return nil
+}
+
+// CheckItem checks an item off while shopping, or puts it back on the list.
+func (s *shoppingListService) CheckItem(userID, listID, itemID uuid.UUID, req dto.CheckItemRequest) (*dto.ShoppingListResponse, error) {
+	if _, err := s.openList(userID, listID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.listRepo.FindItemByID(itemID)
+	if err != nil || item.ListID != listID {
+		return nil, &errors.AppError{Code: "ITEM_NOT_FOUND", Message: "Item not found"}
+	}
+
+	item.Checked = req.Checked
+	if req.Checked {
+		now := time.Now()
+		item.CheckedBy = &userID
+		item.CheckedAt = &now
+	} else {
+		item.CheckedBy = nil
+		item.CheckedAt = nil
+	}
+	if req.ActualUnitPrice != nil {
+		item.ActualUnitPrice = req.ActualUnitPrice
+	}
+	if req.Quantity != nil {
+		item.Quantity = *req.Quantity
+	}
+
+	if err := s.listRepo.UpdateItem(item); err != nil {
+		log.Error().Err(err).Msg("Failed to check shopping list item")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update item"}
+	}
+
+	return s.getList(listID)
+}
+
+// Checkout pays for the checked items with a single debit from the list
+// owner's balance, one line item per item, and marks them bought together
+// with the planned expenses they were added from. Unchecked items stay on the
+// closed list as not bought.
+func (s *shoppingListService) Checkout(userID, listID uuid.UUID, req dto.CheckoutShoppingListRequest) (*dto.ShoppingListResponse, error) {
+	list, err := s.openList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	var checked []models.ShoppingListItem
+	for _, item := range list.Items {
+		if item.Checked {
+			checked = append(checked, item)
+		}
+	}
+	if len(checked) == 0 {
+		return nil, &errors.AppError{Code: "NOTHING_CHECKED", Message: "Check off the items you bought before checking out"}
+	}
+
+	// Build one line item per checked item
+	var lines []dto.TransactionLineItemRequest
+	var total int64
+	category := ""
+	for i, item := range checked {
+		amount := shoppingItemTotal(&item)
+		total += amount
+
+		itemCategory := item.Category
+		if itemCategory == "" {
+			itemCategory = "shopping"
+		}
+		if i == 0 {
+			category = itemCategory
+		} else if category != itemCategory {
+			category = "shopping"
+		}
+
+		if amount > 0 {
+			lines = append(lines, dto.TransactionLineItemRequest{
+				Category: itemCategory,
+				Amount:   amount,
+				Note:     fmt.Sprintf("%d x %s", item.Quantity, item.Name),
+			})
+		}
+	}
+	if total == 0 {
+		return nil, &errors.AppError{Code: "INVALID_AMOUNT", Message: "The checked items have no price"}
+	}
+
+	// Planned expenses bought through the list must still be payable. Items
+	// linked to the same expense are paid towards it in one payment.
+	var expenses []*models.PlannedExpense
+	amounts := make(map[uuid.UUID]int64)
+	for _, item := range checked {
+		if item.PlannedExpenseID == nil {
+			continue
+		}
+		if _, ok := amounts[*item.PlannedExpenseID]; !ok {
+			expense, err := s.expenseRepo.FindByID(*item.PlannedExpenseID)
+			if err != nil {
+				return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Planned expense for " + item.Name + " not found"}
+			}
+			if err := checkPayable(expense); err != nil {
+				return nil, err
+			}
+			expenses = append(expenses, expense)
+		}
+		amounts[*item.PlannedExpenseID] += shoppingItemTotal(&item)
+	}
+
+	description := req.Description
+	if description == "" {
+		description = list.Name
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	transaction := &models.Transaction{
+		Type:        "DEBIT",
+		Amount:      total,
+		Category:    category,
+		Source:      "shopping_list",
+		Description: description,
+		UserID:      userID,
+		Metadata: map[string]interface{}{
+			"shopping_list_id": list.ID.String(),
+		},
+	}
+
+	if list.GroupID != nil {
+		// Get group and check balance
+		group, err := s.groupRepo.FindByID(*list.GroupID)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+		}
+
+		if group.Balance < total {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient group balance"}
+		}
+
+		group.Balance -= total
+
+		transaction.OwnerType = "GROUP"
+		transaction.OwnerID = group.ID
+		transaction.Currency = group.Currency
+		transaction.Balance = group.Balance
+		transaction.GroupID = list.GroupID
+		transaction.PaidBy = &userID
+
+		if err := tx.Save(group).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update group balance")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+		}
+	} else {
+		// Get user and check balance
+		user, err := s.userRepo.FindByID(userID)
+		if err != nil {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
+		}
+
+		if user.Balance < total {
+			tx.Rollback()
+			return nil, &errors.AppError{Code: "INSUFFICIENT_BALANCE", Message: "Insufficient balance"}
+		}
+
+		user.Balance -= total
+
+		transaction.OwnerType = "USER"
+		transaction.OwnerID = userID
+		transaction.Currency = user.Currency
+		transaction.Balance = user.Balance
+		transaction.Metadata["personal"] = true
+
+		if err := tx.Save(user).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to update user balance")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+		}
+	}
+
+	if err := tx.Create(transaction).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+
+	// Create line items
+	if err := createLineItems(tx, transaction.ID, lines); err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create transaction line items")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+
+	// Mark the planned expenses on the list bought
+	now := time.Now()
+	for _, expense := range expenses {
+		amount := amounts[expense.ID]
+		payment := &models.ExpensePayment{
+			PlannedExpenseID: expense.ID,
+			Amount:           amount,
+			PaidBy:           userID,
+			PaidAt:           now,
+			TransactionID:    &transaction.ID,
+			Description:      description,
+		}
+
+		// Paying less than the expense still owes, as when its price does not
+		// divide evenly between the units on the list, leaves the rest open
+		partial := amount < expense.EstimatedPrice-expense.PaidAmount
+		if err := recordPayment(tx, expense, payment, partial); err != nil {
+			tx.Rollback()
+			if appErr, ok := err.(*errors.AppError); ok {
+				return nil, appErr
+			}
+			log.Error().Err(err).Msg("Failed to update planned expense")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+		}
+
+		// Buying the latest occurrence of a recurring expense plans the next one
+		if _, err := spawnAfter(tx, expense, userID); err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to spawn next recurring expense")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+		}
+
+		action := "mark_as_bought"
+		if expense.GroupID != nil {
+			action = "mark_as_paid"
+		}
+		auditLog := &models.AuditLog{
+			Entity:   "planned_expense",
+			EntityID: expense.ID,
+			Action:   action,
+			Changes: map[string]interface{}{
+				"amount":           amount,
+				"paid_amount":      expense.PaidAmount,
+				"status":           expense.Status,
+				"shopping_list_id": list.ID.String(),
+			},
+			PerformedBy: userID,
+			GroupID:     expense.GroupID,
+		}
+
+		if err := tx.Create(auditLog).Error; err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to create audit log")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+		}
+	}
+
+	if err := tx.Model(&models.ShoppingListItem{}).
+		Where("list_id = ? AND checked = ?", list.ID, true).
+		Update("status", "bought").Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update shopping list items")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+
+	// Only one checkout can win
+	result := tx.Model(&models.ShoppingList{}).
+		Where("id = ? AND status = 'open'", list.ID).
+		Updates(map[string]interface{}{
+			"status":         "checked_out",
+			"transaction_id": transaction.ID,
+			"checked_out_by": userID,
+			"checked_out_at": now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		log.Error().Err(result.Error).Msg("Failed to check out shopping list")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return nil, &errors.AppError{Code: "LIST_CLOSED", Message: "Shopping list has already been checked out"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:   "shopping_list",
+		EntityID: list.ID,
+		Action:   "checkout",
+		Changes: map[string]interface{}{
+			"amount":         total,
+			"items":          len(checked),
+			"transaction_id": transaction.ID.String(),
+		},
+		PerformedBy: userID,
+		GroupID:     list.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to check out shopping list"}
+	}
+
+	return s.getList(listID)
+}
+
+// accessList loads a list the user can see: their own personal lists and the
+// lists of groups they are an active member of. The membership is returned
+// for group lists.
+func (s *shoppingListService) accessList(userID, listID uuid.UUID) (*models.ShoppingList, *models.UserGroup, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		return nil, nil, &errors.AppError{Code: "LIST_NOT_FOUND", Message: "Shopping list not found"}
+	}
+
+	if list.GroupID == nil {
+		if list.UserID != userID {
+			return nil, nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+		}
+		return list, nil, nil
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *list.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	return list, userGroup, nil
+}
+
+// openList loads a list the user can change; checked out lists are final.
+func (s *shoppingListService) openList(userID, listID uuid.UUID) (*models.ShoppingList, error) {
+	list, _, err := s.accessList(userID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Status != "open" {
+		return nil, &errors.AppError{Code: "LIST_CLOSED", Message: "Shopping list has already been checked out"}
+	}
+
+	return list, nil
+}
+
+// buildItem validates an item request for a list. An item added from a
+// planned expense must belong to the same owner as the list, can only appear
+// once on it, and defaults to the expense's name, category and remaining
+// price.
+func (s *shoppingListService) buildItem(userID uuid.UUID, list *models.ShoppingList, req dto.ShoppingListItemRequest) (*models.ShoppingListItem, error) {
+	item := &models.ShoppingListItem{
+		Name:             strings.TrimSpace(req.Name),
+		Quantity:         req.Quantity,
+		UnitPrice:        req.UnitPrice,
+		Status:           "pending",
+		PlannedExpenseID: req.PlannedExpenseID,
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+
+	category := req.Category
+	if req.PlannedExpenseID != nil {
+		expense, err := s.expenseRepo.FindByID(*req.PlannedExpenseID)
+		if err != nil {
+			return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Planned expense not found"}
+		}
+
+		sameOwner := expense.GroupID == nil && list.GroupID == nil && expense.UserID == list.UserID
+		if expense.GroupID != nil && list.GroupID != nil {
+			sameOwner = *expense.GroupID == *list.GroupID
+		}
+		if !sameOwner {
+			return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Expense does not belong to the owner of this list"}
+		}
+
+		if err := checkPayable(expense); err != nil {
+			return nil, err
+		}
+
+		// Each expense is paid through a single item
+		for _, existing := range list.Items {
+			if existing.PlannedExpenseID != nil && *existing.PlannedExpenseID == expense.ID {
+				return nil, &errors.AppError{Code: "DUPLICATE_ITEM", Message: expense.Item + " is already on this list"}
+			}
+		}
+
+		if item.Name == "" {
+			item.Name = expense.Item
+		}
+		if req.UnitPrice == 0 {
+			item.UnitPrice = (expense.EstimatedPrice - expense.PaidAmount) / int64(item.Quantity)
+		}
+		if category == "" {
+			item.Category = expense.Category
+		}
+	}
+
+	if category != "" {
+		resolved, err := s.itemCategory(userID, list.GroupID, category)
+		if err != nil {
+			return nil, err
+		}
+		item.Category = resolved
+	}
+
+	return item, nil
+}
+
+func (s *shoppingListService) itemCategory(userID uuid.UUID, groupID *uuid.UUID, name string) (string, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil
+	}
+	return resolveCategory(s.categoryRepo, "category", userID, groupID, name)
+}
+
+func (s *shoppingListService) getList(listID uuid.UUID) (*dto.ShoppingListResponse, error) {
+	list, err := s.listRepo.FindByID(listID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get shopping list data"}
+	}
+	return mapShoppingListToResponse(list), nil
+}
+
+// shoppingItemTotal prices an item at the shelf price when one was noted.
+func shoppingItemTotal(item *models.ShoppingListItem) int64 {
+	price := item.UnitPrice
+	if item.ActualUnitPrice != nil {
+		price = *item.ActualUnitPrice
+	}
+	return price * int64(item.Quantity)
+}
+
+func mapShoppingListsToResponse(lists []models.ShoppingList) []dto.ShoppingListResponse {
+	response := []dto.ShoppingListResponse{}
+	for i := range lists {
+		response = append(response, *mapShoppingListToResponse(&lists[i]))
+	}
+	return response
+}
+
+func mapShoppingListToResponse(list *models.ShoppingList) *dto.ShoppingListResponse {
+	response := &dto.ShoppingListResponse{
+		ID:            list.ID,
+		Name:          list.Name,
+		UserID:        list.UserID,
+		GroupID:       list.GroupID,
+		Status:        list.Status,
+		Items:         []dto.ShoppingListItemResponse{},
+		ItemCount:     len(list.Items),
+		TransactionID: list.TransactionID,
+		CheckedOutBy:  list.CheckedOutBy,
+		CheckedOutAt:  list.CheckedOutAt,
+		CreatedAt:     list.CreatedAt,
+		UpdatedAt:     list.UpdatedAt,
+	}
+	if list.User != nil {
+		response.CreatorName = list.User.FirstName + " " + list.User.LastName
+		response.Currency = list.User.Currency
+	}
+	if list.Group != nil {
+		response.Currency = list.Group.Currency
+	}
+
+	for i := range list.Items {
+		item := &list.Items[i]
+		total := shoppingItemTotal(item)
+
+		response.EstimatedTotal += item.UnitPrice * int64(item.Quantity)
+		if item.Checked {
+			response.CheckedCount++
+			response.CheckedTotal += total
+		}
+
+		response.Items = append(response.Items, dto.ShoppingListItemResponse{
+			ID:               item.ID,
+			Name:             item.Name,
+			Quantity:         item.Quantity,
+			UnitPrice:        item.UnitPrice,
+			ActualUnitPrice:  item.ActualUnitPrice,
+			Total:            total,
+			FormattedTotal:   formatAmount(total, response.Currency),
+			Category:         item.Category,
+			Checked:          item.Checked,
+			CheckedBy:        item.CheckedBy,
+			CheckedAt:        item.CheckedAt,
+			Status:           item.Status,
+			PlannedExpenseID: item.PlannedExpenseID,
+		})
+	}
+	response.FormattedCheckedTotal = formatAmount(response.CheckedTotal, response.Currency)
+
+	return response
+}
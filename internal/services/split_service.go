@@ -0,0 +1,611 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SplitService interface {
+	SetSplit(userID, expenseID uuid.UUID, req dto.SetSplitRequest) (*dto.SplitResponse, error)
+	GetSplit(userID, expenseID uuid.UUID) (*dto.SplitResponse, error)
+	DeleteSplit(userID, expenseID uuid.UUID) error
+	GetGroupBalances(userID, groupID uuid.UUID) (*dto.GroupBalancesResponse, error)
+}
+
+type splitService struct {
+	splitRepo         repositories.SplitRepository
+	expenseRepo       repositories.PlannedExpenseRepository
+	reimbursementRepo repositories.ReimbursementRepository
+	settlementRepo    repositories.SettlementRepository
+	groupRepo         repositories.GroupRepository
+	db                *gorm.DB
+}
+
+func NewSplitService(
+	splitRepo repositories.SplitRepository,
+	expenseRepo repositories.PlannedExpenseRepository,
+	reimbursementRepo repositories.ReimbursementRepository,
+	settlementRepo repositories.SettlementRepository,
+	groupRepo repositories.GroupRepository,
+	db *gorm.DB,
+) SplitService {
+	return &splitService{
+		splitRepo:         splitRepo,
+		expenseRepo:       expenseRepo,
+		reimbursementRepo: reimbursementRepo,
+		settlementRepo:    settlementRepo,
+		groupRepo:         groupRepo,
+		db:                db,
+	}
+}
+
+func (s *splitService) SetSplit(userID, expenseID uuid.UUID, req dto.SetSplitRequest) (*dto.SplitResponse, error) {
+	expense, err := s.splitExpense(userID, expenseID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if expense.Status == "cancelled" || expense.Status == "rejected" {
+		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense can no longer be split"}
+	}
+
+	// Money the group already paid back cannot be owed between members too
+	reimbursements, err := s.reimbursementRepo.FindByExpense(expenseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get reimbursements")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+	for _, reimbursement := range reimbursements {
+		if reimbursement.Status == "settled" {
+			return nil, &errors.AppError{Code: "SPLIT_CONFLICT", Message: "The group has already reimbursed a payment for this expense"}
+		}
+	}
+
+	members, err := s.groupRepo.FindMembers(*expense.GroupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	shares, err := buildSplitShares(req, members, splitTotal(expense))
+	if err != nil {
+		return nil, err
+	}
+
+	split := &models.ExpenseSplit{
+		PlannedExpenseID: expenseID,
+		GroupID:          *expense.GroupID,
+		Method:           req.Method,
+		CreatedBy:        userID,
+		Shares:           shares,
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Replace any previous split
+	if err := tx.Unscoped().Where("split_id IN (?)", tx.Model(&models.ExpenseSplit{}).Select("id").Where("planned_expense_id = ?", expenseID)).
+		Delete(&models.ExpenseSplitShare{}).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to delete split shares")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	if err := tx.Unscoped().Where("planned_expense_id = ?", expenseID).Delete(&models.ExpenseSplit{}).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to delete split")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	if err := tx.Create(split).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create split")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	// Members who paid out of pocket are now repaid through the split
+	if err := tx.Model(&models.Reimbursement{}).
+		Where("planned_expense_id = ? AND status = 'outstanding'", expenseID).
+		Update("status", "split").Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update reimbursements")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	// Create audit log
+	var memberIDs []string
+	for _, share := range shares {
+		memberIDs = append(memberIDs, share.UserID.String())
+	}
+	auditLog := &models.AuditLog{
+		Entity:      "planned_expense",
+		EntityID:    expenseID,
+		Action:      "set_split",
+		Changes:     map[string]interface{}{"method": req.Method, "members": memberIDs},
+		PerformedBy: userID,
+		GroupID:     expense.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to split expense"}
+	}
+
+	return s.GetSplit(userID, expenseID)
+}
+
+func (s *splitService) GetSplit(userID, expenseID uuid.UUID) (*dto.SplitResponse, error) {
+	expense, err := s.splitExpense(userID, expenseID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	split, err := s.splitRepo.FindByExpense(expenseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get split")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get split"}
+	}
+	if split == nil {
+		return nil, &errors.AppError{Code: "SPLIT_NOT_FOUND", Message: "Expense is not split"}
+	}
+
+	total := splitTotal(expense)
+	weights := make([]int64, len(split.Shares))
+	for i, share := range split.Shares {
+		weights[i] = share.Weight
+	}
+	amounts := allocateByWeight(total, weights)
+
+	response := &dto.SplitResponse{
+		ExpenseID: expenseID,
+		GroupID:   split.GroupID,
+		Method:    split.Method,
+		Total:     total,
+		Shares:    []dto.SplitShareResponse{},
+	}
+	if expense.Group != nil {
+		response.Currency = expense.Group.Currency
+	}
+
+	for i, share := range split.Shares {
+		item := dto.SplitShareResponse{
+			UserID: share.UserID,
+			Weight: share.Weight,
+			Amount: amounts[i],
+		}
+		if share.User != nil {
+			item.FirstName = share.User.FirstName
+			item.LastName = share.User.LastName
+		}
+		response.Shares = append(response.Shares, item)
+	}
+
+	return response, nil
+}
+
+func (s *splitService) DeleteSplit(userID, expenseID uuid.UUID) error {
+	expense, err := s.splitExpense(userID, expenseID, true)
+	if err != nil {
+		return err
+	}
+
+	split, err := s.splitRepo.FindByExpense(expenseID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get split")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+	if split == nil {
+		return &errors.AppError{Code: "SPLIT_NOT_FOUND", Message: "Expense is not split"}
+	}
+
+	// Start transaction
+	tx := s.db.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Unscoped().Where("split_id = ?", split.ID).Delete(&models.ExpenseSplitShare{}).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to delete split shares")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+
+	if err := tx.Unscoped().Delete(&models.ExpenseSplit{}, "id = ?", split.ID).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to delete split")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+
+	// Without a split the group owes the members who paid
+	if err := tx.Model(&models.Reimbursement{}).
+		Where("planned_expense_id = ? AND status = 'split'", expenseID).
+		Update("status", "outstanding").Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to update reimbursements")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "planned_expense",
+		EntityID:    expenseID,
+		Action:      "delete_split",
+		Changes:     map[string]interface{}{"method": split.Method},
+		PerformedBy: userID,
+		GroupID:     expense.GroupID,
+	}
+
+	if err := tx.Create(auditLog).Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create audit log")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+
+	// Commit transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to commit transaction")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete split"}
+	}
+
+	return nil
+}
+
+// GetGroupBalances nets what each member paid out of pocket for split
+// expenses against their shares of those payments, adjusts for confirmed
+// settlements between members, and suggests the transfers that settle the
+// group.
+func (s *splitService) GetGroupBalances(userID, groupID uuid.UUID) (*dto.GroupBalancesResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	group, err := s.groupRepo.FindByID(groupID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
+	}
+
+	members, err := s.groupRepo.FindMembers(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group members")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get balances"}
+	}
+
+	splits, err := s.splitRepo.FindByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get splits")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get balances"}
+	}
+
+	payments, err := s.reimbursementRepo.FindByGroupAndStatus(groupID, "split")
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get split payments")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get balances"}
+	}
+
+	settlements, err := s.settlementRepo.FindConfirmedByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get settlements")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get balances"}
+	}
+
+	positions := splitPositions(splits, payments)
+	for _, settlement := range settlements {
+		positionOf(positions, settlement.FromUserID).sent += settlement.Amount
+		positionOf(positions, settlement.ToUserID).received += settlement.Amount
+	}
+	return mapBalancesToResponse(groupID, group.Currency, positions, members), nil
+}
+
+// splitExpense loads a group expense for the split endpoints. Changing a split
+// is up to the member who planned the expense or a manager.
+func (s *splitService) splitExpense(userID, expenseID uuid.UUID, change bool) (*models.PlannedExpense, error) {
+	expense, err := s.expenseRepo.FindByID(expenseID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "EXPENSE_NOT_FOUND", Message: "Expense not found"}
+	}
+	if expense.GroupID == nil {
+		return nil, &errors.AppError{Code: "NOT_GROUP_EXPENSE", Message: "Only group expenses can be split"}
+	}
+
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *expense.GroupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+	if change && expense.UserID != userID && userGroup.Role != "manager" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Only the member who planned the expense or a manager can change its split"}
+	}
+
+	return expense, nil
+}
+
+// memberPosition is what a member paid for the group and what they owe,
+// along with what they have since paid to or received from other members.
+type memberPosition struct {
+	paid     int64
+	owed     int64
+	sent     int64
+	received int64
+}
+
+func positionOf(positions map[uuid.UUID]*memberPosition, userID uuid.UUID) *memberPosition {
+	if positions[userID] == nil {
+		positions[userID] = &memberPosition{}
+	}
+	return positions[userID]
+}
+
+// splitPositions credits each member with their out-of-pocket payments for
+// split expenses and charges every member their share of those payments.
+func splitPositions(splits []models.ExpenseSplit, payments []models.Reimbursement) map[uuid.UUID]*memberPosition {
+	positions := make(map[uuid.UUID]*memberPosition)
+
+	paid := make(map[uuid.UUID]int64)
+	for _, payment := range payments {
+		paid[payment.PlannedExpenseID] += payment.Amount
+		positionOf(positions, payment.UserID).paid += payment.Amount
+	}
+
+	for _, split := range splits {
+		total := paid[split.PlannedExpenseID]
+		if total == 0 || len(split.Shares) == 0 {
+			continue
+		}
+
+		weights := make([]int64, len(split.Shares))
+		for i, share := range split.Shares {
+			weights[i] = share.Weight
+		}
+		for i, amount := range allocateByWeight(total, weights) {
+			positionOf(positions, split.Shares[i].UserID).owed += amount
+		}
+	}
+
+	return positions
+}
+
+// buildSplitShares validates the members and values of a split request and
+// converts the values to weights.
+func buildSplitShares(req dto.SetSplitRequest, members []models.UserGroup, total int64) ([]models.ExpenseSplitShare, error) {
+	active := make(map[uuid.UUID]bool)
+	for _, member := range members {
+		if member.Status == "active" {
+			active[member.UserID] = true
+		}
+	}
+
+	requested := req.Shares
+	if len(requested) == 0 {
+		if req.Method != "equal" {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Shares are required for this split method"}
+		}
+		for _, member := range members {
+			if member.Status == "active" {
+				requested = append(requested, dto.SplitShareRequest{UserID: member.UserID})
+			}
+		}
+	}
+
+	var shares []models.ExpenseSplitShare
+	var sum int64
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range requested {
+		if !active[item.UserID] {
+			return nil, &errors.AppError{Code: "INVALID_MEMBER", Message: "Expenses can only be split between active members"}
+		}
+		if seen[item.UserID] {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Each member can only appear once in a split"}
+		}
+		seen[item.UserID] = true
+
+		weight := int64(1)
+		switch req.Method {
+		case "exact":
+			if item.Value != math.Trunc(item.Value) {
+				return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Exact amounts must be whole minor units"}
+			}
+			weight = int64(item.Value)
+		case "percentage", "shares":
+			weight = int64(math.Round(item.Value * 100))
+		}
+		if weight <= 0 {
+			return nil, &errors.AppError{Code: "INVALID_REQUEST", Message: "Every member needs a positive value"}
+		}
+
+		sum += weight
+		shares = append(shares, models.ExpenseSplitShare{UserID: item.UserID, Weight: weight})
+	}
+
+	switch {
+	case req.Method == "exact" && sum != total:
+		return nil, &errors.AppError{Code: "SPLIT_MISMATCH", Message: "Exact amounts must add up to the expense price"}
+	case req.Method == "percentage" && sum != 10000:
+		return nil, &errors.AppError{Code: "SPLIT_MISMATCH", Message: "Percentages must add up to 100"}
+	}
+
+	return shares, nil
+}
+
+// splitTotal is the amount a split divides: the actual price once bought,
+// the estimated price before.
+func splitTotal(expense *models.PlannedExpense) int64 {
+	if expense.ActualPrice != nil {
+		return *expense.ActualPrice
+	}
+	return expense.EstimatedPrice
+}
+
+// allocateByWeight divides total in proportion to weights, giving the cents
+// lost to rounding to the largest remainders so the parts add up to total.
+func allocateByWeight(total int64, weights []int64) []int64 {
+	amounts := make([]int64, len(weights))
+	var sum int64
+	for _, weight := range weights {
+		sum += weight
+	}
+	if sum == 0 {
+		return amounts
+	}
+
+	remainders := make([]int64, len(weights))
+	allocated := int64(0)
+	for i, weight := range weights {
+		amounts[i] = total * weight / sum
+		remainders[i] = total * weight % sum
+		allocated += amounts[i]
+	}
+
+	order := make([]int, len(weights))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; allocated < total; i++ {
+		amounts[order[i%len(order)]]++
+		allocated++
+	}
+
+	return amounts
+}
+
+// simplifyDebts turns net positions into transfers from debtors to
+// creditors. Debts that cancel exactly are paired first; the rest are settled
+// greedily between the largest debtor and the largest creditor, which needs at
+// most one transfer fewer than the number of members involved.
+func simplifyDebts(nets map[uuid.UUID]int64) []settleTransfer {
+	var creditors, debtors []settleParty
+	for userID, net := range nets {
+		if net > 0 {
+			creditors = append(creditors, settleParty{userID: userID, amount: net})
+		} else if net < 0 {
+			debtors = append(debtors, settleParty{userID: userID, amount: -net})
+		}
+	}
+	byAmount := func(parties []settleParty) {
+		sort.Slice(parties, func(i, j int) bool {
+			if parties[i].amount != parties[j].amount {
+				return parties[i].amount > parties[j].amount
+			}
+			return parties[i].userID.String() < parties[j].userID.String()
+		})
+	}
+	byAmount(creditors)
+	byAmount(debtors)
+
+	var transfers []settleTransfer
+	for i := range debtors {
+		for j := range creditors {
+			if creditors[j].amount > 0 && debtors[i].amount == creditors[j].amount {
+				transfers = append(transfers, settleTransfer{from: debtors[i].userID, to: creditors[j].userID, amount: debtors[i].amount})
+				debtors[i].amount, creditors[j].amount = 0, 0
+				break
+			}
+		}
+	}
+
+	for {
+		byAmount(creditors)
+		byAmount(debtors)
+		if len(creditors) == 0 || len(debtors) == 0 || creditors[0].amount == 0 || debtors[0].amount == 0 {
+			break
+		}
+
+		amount := debtors[0].amount
+		if creditors[0].amount < amount {
+			amount = creditors[0].amount
+		}
+		transfers = append(transfers, settleTransfer{from: debtors[0].userID, to: creditors[0].userID, amount: amount})
+		debtors[0].amount -= amount
+		creditors[0].amount -= amount
+	}
+
+	return transfers
+}
+
+type settleParty struct {
+	userID uuid.UUID
+	amount int64
+}
+
+type settleTransfer struct {
+	from   uuid.UUID
+	to     uuid.UUID
+	amount int64
+}
+
+func mapBalancesToResponse(groupID uuid.UUID, currency string, positions map[uuid.UUID]*memberPosition, members []models.UserGroup) *dto.GroupBalancesResponse {
+	users := make(map[uuid.UUID]models.User)
+	for _, member := range members {
+		users[member.UserID] = member.User
+	}
+
+	response := &dto.GroupBalancesResponse{
+		GroupID:   groupID,
+		Currency:  currency,
+		Members:   []dto.MemberBalance{},
+		Transfers: []dto.SettleTransfer{},
+	}
+
+	nets := make(map[uuid.UUID]int64)
+	for userID, position := range positions {
+		net := position.paid - position.owed + position.sent - position.received
+		nets[userID] = net
+		response.Members = append(response.Members, dto.MemberBalance{
+			UserID:       userID,
+			FirstName:    users[userID].FirstName,
+			LastName:     users[userID].LastName,
+			Paid:         position.paid,
+			Owed:         position.owed,
+			Sent:         position.sent,
+			Received:     position.received,
+			Net:          net,
+			FormattedNet: formatAmount(net, currency),
+		})
+	}
+	sort.Slice(response.Members, func(i, j int) bool {
+		if response.Members[i].Net != response.Members[j].Net {
+			return response.Members[i].Net > response.Members[j].Net
+		}
+		return response.Members[i].UserID.String() < response.Members[j].UserID.String()
+	})
+
+	for _, transfer := range simplifyDebts(nets) {
+		from, to := users[transfer.from], users[transfer.to]
+		response.Transfers = append(response.Transfers, dto.SettleTransfer{
+			FromUserID:      transfer.from,
+			FromName:        from.FirstName + " " + from.LastName,
+			ToUserID:        transfer.to,
+			ToName:          to.FirstName + " " + to.LastName,
+			Amount:          transfer.amount,
+			FormattedAmount: formatAmount(transfer.amount, currency),
+		})
+	}
+
+	return response
+}
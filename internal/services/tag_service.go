@@ -0,0 +1,303 @@
+package services
+
+import (
+	"balanca/internal/dto"
+	"balanca/internal/models"
+	"balanca/internal/repositories"
+	"balanca/pkg/errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type TagService interface {
+	CreatePersonalTag(userID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error)
+	CreateGroupTag(userID, groupID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error)
+	GetPersonalTags(userID uuid.UUID) ([]dto.TagResponse, error)
+	GetGroupTags(userID, groupID uuid.UUID) ([]dto.TagResponse, error)
+	UpdateTag(userID, tagID uuid.UUID, req dto.UpdateTagRequest) (*dto.TagResponse, error)
+	DeleteTag(userID, tagID uuid.UUID) error
+}
+
+type tagService struct {
+	tagRepo   repositories.TagRepository
+	groupRepo repositories.GroupRepository
+	auditRepo repositories.AuditLogRepository
+}
+
+func NewTagService(
+	tagRepo repositories.TagRepository,
+	groupRepo repositories.GroupRepository,
+	auditRepo repositories.AuditLogRepository,
+) TagService {
+	return &tagService{
+		tagRepo:   tagRepo,
+		groupRepo: groupRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+func (s *tagService) CreatePersonalTag(userID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error) {
+	tag := &models.Tag{
+		Name:      strings.TrimSpace(req.Name),
+		Color:     req.Color,
+		UserID:    &userID,
+		CreatedBy: userID,
+	}
+
+	return s.createTag(userID, tag)
+}
+
+func (s *tagService) CreateGroupTag(userID, groupID uuid.UUID, req dto.CreateTagRequest) (*dto.TagResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	tag := &models.Tag{
+		Name:      strings.TrimSpace(req.Name),
+		Color:     req.Color,
+		GroupID:   &groupID,
+		CreatedBy: userID,
+	}
+
+	return s.createTag(userID, tag)
+}
+
+func (s *tagService) createTag(userID uuid.UUID, tag *models.Tag) (*dto.TagResponse, error) {
+	existing, err := s.tagRepo.FindByName(tag.UserID, tag.GroupID, tag.Name)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to check tag name")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create tag"}
+	}
+	if existing != nil {
+		return nil, &errors.AppError{Code: "TAG_EXISTS", Message: "A tag with this name already exists"}
+	}
+
+	if err := s.tagRepo.Create(tag); err != nil {
+		log.Error().Err(err).Msg("Failed to create tag")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create tag"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "tag",
+		EntityID:    tag.ID,
+		Action:      "create",
+		Changes:     map[string]interface{}{"name": tag.Name},
+		PerformedBy: userID,
+		GroupID:     tag.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return mapTagToResponse(tag), nil
+}
+
+func (s *tagService) GetPersonalTags(userID uuid.UUID) ([]dto.TagResponse, error) {
+	tags, err := s.tagRepo.FindByUser(userID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal tags")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get tags"}
+	}
+
+	return mapTagsToResponse(tags), nil
+}
+
+func (s *tagService) GetGroupTags(userID, groupID uuid.UUID) ([]dto.TagResponse, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	tags, err := s.tagRepo.FindByGroup(groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group tags")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get tags"}
+	}
+
+	return mapTagsToResponse(tags), nil
+}
+
+func (s *tagService) UpdateTag(userID, tagID uuid.UUID, req dto.UpdateTagRequest) (*dto.TagResponse, error) {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "TAG_NOT_FOUND", Message: "Tag not found"}
+	}
+
+	if !s.canUseTag(userID, tag) {
+		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	changes := make(map[string]interface{})
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name != tag.Name {
+			existing, err := s.tagRepo.FindByName(tag.UserID, tag.GroupID, name)
+			if err != nil {
+				log.Error().Err(err).Msg("Failed to check tag name")
+				return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update tag"}
+			}
+			if existing != nil && existing.ID != tag.ID {
+				return nil, &errors.AppError{Code: "TAG_EXISTS", Message: "A tag with this name already exists"}
+			}
+			changes["name"] = map[string]interface{}{"old": tag.Name, "new": name}
+			tag.Name = name
+		}
+	}
+
+	if req.Color != nil && *req.Color != tag.Color {
+		changes["color"] = map[string]interface{}{"old": tag.Color, "new": *req.Color}
+		tag.Color = *req.Color
+	}
+
+	if err := s.tagRepo.Update(tag); err != nil {
+		log.Error().Err(err).Msg("Failed to update tag")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update tag"}
+	}
+
+	if len(changes) > 0 {
+		auditLog := &models.AuditLog{
+			Entity:      "tag",
+			EntityID:    tag.ID,
+			Action:      "update",
+			Changes:     changes,
+			PerformedBy: userID,
+			GroupID:     tag.GroupID,
+		}
+
+		if err := s.auditRepo.Create(auditLog); err != nil {
+			log.Error().Err(err).Msg("Failed to create audit log")
+		}
+	}
+
+	return mapTagToResponse(tag), nil
+}
+
+func (s *tagService) DeleteTag(userID, tagID uuid.UUID) error {
+	tag, err := s.tagRepo.FindByID(tagID)
+	if err != nil {
+		return &errors.AppError{Code: "TAG_NOT_FOUND", Message: "Tag not found"}
+	}
+
+	if tag.GroupID != nil {
+		// For group tags, check if user is a manager
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *tag.GroupID)
+		if err != nil || userGroup.Status != "active" || userGroup.Role != "manager" {
+			return &errors.AppError{Code: "FORBIDDEN", Message: "Only managers can delete group tags"}
+		}
+	} else if tag.UserID == nil || *tag.UserID != userID {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "Access denied"}
+	}
+
+	if err := s.tagRepo.Delete(tagID); err != nil {
+		log.Error().Err(err).Msg("Failed to delete tag")
+		return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to delete tag"}
+	}
+
+	// Create audit log
+	auditLog := &models.AuditLog{
+		Entity:      "tag",
+		EntityID:    tagID,
+		Action:      "delete",
+		Changes:     map[string]interface{}{"name": tag.Name},
+		PerformedBy: userID,
+		GroupID:     tag.GroupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	return nil
+}
+
+func (s *tagService) canUseTag(userID uuid.UUID, tag *models.Tag) bool {
+	if tag.GroupID != nil {
+		userGroup, err := s.groupRepo.FindByUserAndGroup(userID, *tag.GroupID)
+		return err == nil && userGroup.Status == "active"
+	}
+	return tag.UserID != nil && *tag.UserID == userID
+}
+
+// resolveTags loads the requested tags and makes sure they all belong to the
+// scope of the record being tagged: the user's personal tags for personal
+// records, or the group's tags for group records.
+func resolveTags(tagRepo repositories.TagRepository, userID uuid.UUID, groupID *uuid.UUID, tagIDs []uuid.UUID) ([]models.Tag, error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+
+	tags, err := tagRepo.FindByIDs(tagIDs)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load tags")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to load tags"}
+	}
+
+	if len(tags) != len(uniqueIDs(tagIDs)) {
+		return nil, &errors.AppError{Code: "TAG_NOT_FOUND", Message: "Tag not found"}
+	}
+
+	for _, tag := range tags {
+		if groupID != nil {
+			if tag.GroupID == nil || *tag.GroupID != *groupID {
+				return nil, &errors.AppError{Code: "INVALID_TAG", Message: "Tag does not belong to this group"}
+			}
+		} else if tag.GroupID != nil || tag.UserID == nil || *tag.UserID != userID {
+			return nil, &errors.AppError{Code: "INVALID_TAG", Message: "Tag does not belong to you"}
+		}
+	}
+
+	return tags, nil
+}
+
+// parseOptionalID parses an optional UUID query parameter.
+func parseOptionalID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var unique []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+func mapTagToResponse(tag *models.Tag) *dto.TagResponse {
+	return &dto.TagResponse{
+		ID:        tag.ID,
+		Name:      tag.Name,
+		Color:     tag.Color,
+		UserID:    tag.UserID,
+		GroupID:   tag.GroupID,
+		CreatedBy: tag.CreatedBy,
+		CreatedAt: tag.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapTagsToResponse(tags []models.Tag) []dto.TagResponse {
+	var response []dto.TagResponse
+	for _, tag := range tags {
+		response = append(response, *mapTagToResponse(&tag))
+	}
+	return response
+}
@@ -5,6 +5,9 @@ import (
 	"balanca/internal/models"
 	"balanca/internal/repositories"
 	"balanca/pkg/errors"
+	"balanca/pkg/money"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,24 +15,38 @@ import (
 	"gorm.io/gorm"
 )
 
+// exportPageSize is the number of transactions loaded at a time by exports.
+const exportPageSize = 500
+
 type TransactionService interface {
 	CreatePersonalTransaction(userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
 	CreateGroupTransaction(userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error)
-	GetPersonalTransactions(userID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error)
-	GetGroupTransactions(userID, groupID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error)
+	GetPersonalTransactions(userID uuid.UUID, filter dto.TransactionFilter, page, limit int) ([]dto.TransactionResponse, int64, error)
+	GetGroupTransactions(userID, groupID uuid.UUID, filter dto.TransactionFilter, page, limit int) ([]dto.TransactionResponse, int64, error)
+	GetPersonalTransactionsAfter(userID uuid.UUID, filter dto.TransactionFilter, cursor string, limit int) ([]dto.TransactionResponse, string, error)
+	GetGroupTransactionsAfter(userID, groupID uuid.UUID, filter dto.TransactionFilter, cursor string, limit int) ([]dto.TransactionResponse, string, error)
+	ExportPersonalTransactions(userID uuid.UUID, filter dto.TransactionFilter, write func(*dto.TransactionResponse) error) error
+	ExportGroupTransactions(userID, groupID uuid.UUID, filter dto.TransactionFilter, write func(*dto.TransactionResponse) error) error
 	GetTransaction(userID, transactionID uuid.UUID) (*dto.TransactionResponse, error)
+	SetTransactionTags(userID, transactionID uuid.UUID, req dto.SetTagsRequest) (*dto.TransactionResponse, error)
 	TransferToGroup(userID uuid.UUID, req dto.TransferToGroupRequest) (*dto.TransactionResponse, error)
 	PayGroupExpense(userID, groupID uuid.UUID, req dto.PayGroupExpenseRequest) (*dto.TransactionResponse, error)
 	RecordExternalIncome(userID, groupID uuid.UUID, amount int64, source string) (*dto.TransactionResponse, error)
 }
 
 type transactionService struct {
-	transactionRepo repositories.TransactionRepository
-	userRepo        repositories.UserRepository
-	groupRepo       repositories.GroupRepository
-	expenseRepo     repositories.PlannedExpenseRepository
-	auditRepo       repositories.AuditLogRepository
-	db              *gorm.DB
+	transactionRepo  repositories.TransactionRepository
+	userRepo         repositories.UserRepository
+	groupRepo        repositories.GroupRepository
+	expenseRepo      repositories.PlannedExpenseRepository
+	auditRepo        repositories.AuditLogRepository
+	tagRepo          repositories.TagRepository
+	categoryRepo     repositories.CategoryRepository
+	rateRepo         repositories.ExchangeRateRepository
+	ruleRepo         repositories.RuleRepository
+	anomalyRepo      repositories.AnomalyRepository
+	notificationRepo repositories.NotificationRepository
+	db               *gorm.DB
 }
 
 func NewTransactionService(
@@ -38,19 +55,36 @@ func NewTransactionService(
 	groupRepo repositories.GroupRepository,
 	expenseRepo repositories.PlannedExpenseRepository,
 	auditRepo repositories.AuditLogRepository,
+	tagRepo repositories.TagRepository,
+	categoryRepo repositories.CategoryRepository,
+	rateRepo repositories.ExchangeRateRepository,
+	ruleRepo repositories.RuleRepository,
+	anomalyRepo repositories.AnomalyRepository,
+	notificationRepo repositories.NotificationRepository,
 	db *gorm.DB,
 ) TransactionService {
 	return &transactionService{
-		transactionRepo: transactionRepo,
-		userRepo:        userRepo,
-		groupRepo:       groupRepo,
-		expenseRepo:     expenseRepo,
-		auditRepo:       auditRepo,
-		db:              db,
+		transactionRepo:  transactionRepo,
+		userRepo:         userRepo,
+		groupRepo:        groupRepo,
+		expenseRepo:      expenseRepo,
+		auditRepo:        auditRepo,
+		tagRepo:          tagRepo,
+		categoryRepo:     categoryRepo,
+		rateRepo:         rateRepo,
+		ruleRepo:         ruleRepo,
+		anomalyRepo:      anomalyRepo,
+		notificationRepo: notificationRepo,
+		db:               db,
 	}
 }
 
 func (s *transactionService) CreatePersonalTransaction(userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error) {
+	tags, err := resolveTags(s.tagRepo, userID, nil, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction
 	tx := s.db.Begin()
 	defer func() {
@@ -66,6 +100,35 @@ func (s *transactionService) CreatePersonalTransaction(userID uuid.UUID, req dto
 		return nil, &errors.AppError{Code: "USER_NOT_FOUND", Message: "User not found"}
 	}
 
+	if err := checkTransactionCurrency(req.Currency, user.Currency); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	exchangeRate, err := s.convertOriginalAmount(&req, user.Currency)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Rules see the amount in the owner's currency and may replace a
+	// category that is not in the catalog, as imported ones often are
+	tags, err = s.applyRules(userID, nil, &req, tags)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.resolveCategories(userID, nil, &req); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := validateLineItems(req.Amount, req.LineItems); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Calculate new balance
 	var newBalance int64
 	if req.Type == "CREDIT" {
@@ -84,15 +147,27 @@ func (s *transactionService) CreatePersonalTransaction(userID uuid.UUID, req dto
 		OwnerID:     userID,
 		Type:        req.Type,
 		Amount:      req.Amount,
+		Currency:    user.Currency,
 		Balance:     newBalance,
 		Category:    req.Category,
 		Source:      req.Source,
 		Description: req.Description,
 		UserID:      userID,
+		Tags:        tags,
+
+		OriginalAmount:   originalAmount(req),
+		OriginalCurrency: req.OriginalCurrency,
+		ExchangeRate:     exchangeRate,
 		Metadata: map[string]interface{}{
 			"personal": true,
 		},
 	}
+	for key, value := range req.Metadata {
+		transaction.Metadata[key] = value
+	}
+	if req.Date != nil {
+		transaction.CreatedAt = *req.Date
+	}
 
 	if err := tx.Create(transaction).Error; err != nil {
 		tx.Rollback()
@@ -100,6 +175,13 @@ func (s *transactionService) CreatePersonalTransaction(userID uuid.UUID, req dto
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create transaction"}
 	}
 
+	// Create line items
+	if err := createLineItems(tx, transaction.ID, req.LineItems); err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create transaction line items")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create transaction"}
+	}
+
 	// Update user balance
 	user.Balance = newBalance
 	if err := tx.Save(user).Error; err != nil {
@@ -136,7 +218,10 @@ func (s *transactionService) CreatePersonalTransaction(userID uuid.UUID, req dto
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transaction data"}
 	}
 
-	return s.mapTransactionToResponse(fullTransaction), nil
+	response := s.mapTransactionToResponse(fullTransaction)
+	response.Anomaly = s.flagAnomaly(transaction)
+
+	return response, nil
 }
 
 func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.CreateTransactionRequest) (*dto.TransactionResponse, error) {
@@ -150,6 +235,11 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
 	}
 
+	tags, err := resolveTags(s.tagRepo, userID, req.GroupID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	// Start transaction
 	tx := s.db.Begin()
 	defer func() {
@@ -165,6 +255,35 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
 	}
 
+	if err := checkTransactionCurrency(req.Currency, group.Currency); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	exchangeRate, err := s.convertOriginalAmount(&req, group.Currency)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Rules see the amount in the owner's currency and may replace a
+	// category that is not in the catalog, as imported ones often are
+	tags, err = s.applyRules(userID, req.GroupID, &req, tags)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := s.resolveCategories(userID, req.GroupID, &req); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := validateLineItems(req.Amount, req.LineItems); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Calculate new balance
 	var newBalance int64
 	if req.Type == "CREDIT" {
@@ -183,6 +302,7 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		OwnerID:     *req.GroupID,
 		Type:        req.Type,
 		Amount:      req.Amount,
+		Currency:    group.Currency,
 		Balance:     newBalance,
 		Category:    req.Category,
 		Source:      req.Source,
@@ -190,10 +310,21 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		GroupID:     req.GroupID,
 		PaidBy:      req.PaidBy,
 		UserID:      userID,
+		Tags:        tags,
+
+		OriginalAmount:   originalAmount(req),
+		OriginalCurrency: req.OriginalCurrency,
+		ExchangeRate:     exchangeRate,
 		Metadata: map[string]interface{}{
 			"group": true,
 		},
 	}
+	for key, value := range req.Metadata {
+		transaction.Metadata[key] = value
+	}
+	if req.Date != nil {
+		transaction.CreatedAt = *req.Date
+	}
 
 	if req.PlannedExpenseID != nil {
 		transaction.PlannedExpenseID = req.PlannedExpenseID
@@ -205,6 +336,13 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create transaction"}
 	}
 
+	// Create line items
+	if err := createLineItems(tx, transaction.ID, req.LineItems); err != nil {
+		tx.Rollback()
+		log.Error().Err(err).Msg("Failed to create transaction line items")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create transaction"}
+	}
+
 	// Update group balance
 	group.Balance = newBalance
 	if err := tx.Save(group).Error; err != nil {
@@ -242,7 +380,10 @@ func (s *transactionService) CreateGroupTransaction(userID uuid.UUID, req dto.Cr
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transaction data"}
 	}
 
-	return s.mapTransactionToResponse(fullTransaction), nil
+	response := s.mapTransactionToResponse(fullTransaction)
+	response.Anomaly = s.flagAnomaly(transaction)
+
+	return response, nil
 }
 
 func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferToGroupRequest) (*dto.TransactionResponse, error) {
@@ -279,6 +420,23 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 		return nil, &errors.AppError{Code: "GROUP_NOT_FOUND", Message: "Group not found"}
 	}
 
+	// Convert when the user and the group hold different currencies
+	groupAmount, err := convertTransferAmount(req.Amount, user.Currency, group.Currency, req.ExchangeRate)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Both sides keep the rate so either record explains the conversion
+	var conversion map[string]interface{}
+	if user.Currency != group.Currency {
+		conversion = map[string]interface{}{
+			"exchange_rate":     req.ExchangeRate,
+			"original_amount":   req.Amount,
+			"original_currency": user.Currency,
+		}
+	}
+
 	// Update user balance (debit)
 	user.Balance -= req.Amount
 
@@ -288,6 +446,7 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 		OwnerID:     userID,
 		Type:        "DEBIT",
 		Amount:      req.Amount,
+		Currency:    user.Currency,
 		Balance:     user.Balance,
 		Category:    "transfer",
 		Source:      "group_transfer",
@@ -299,6 +458,9 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 			"group_id":          req.GroupID.String(),
 		},
 	}
+	for key, value := range conversion {
+		personalTransaction.Metadata[key] = value
+	}
 
 	if err := tx.Create(personalTransaction).Error; err != nil {
 		tx.Rollback()
@@ -307,14 +469,15 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 	}
 
 	// Update group balance (credit)
-	group.Balance += req.Amount
+	group.Balance += groupAmount
 
 	// Create group transaction (credit)
 	groupTransaction := &models.Transaction{
 		OwnerType:   "GROUP",
 		OwnerID:     req.GroupID,
 		Type:        "CREDIT",
-		Amount:      req.Amount,
+		Amount:      groupAmount,
+		Currency:    group.Currency,
 		Balance:     group.Balance,
 		Category:    "member_contribution",
 		Source:      "member",
@@ -327,6 +490,9 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 			"member_id":   userID.String(),
 		},
 	}
+	for key, value := range conversion {
+		groupTransaction.Metadata[key] = value
+	}
 
 	if err := tx.Create(groupTransaction).Error; err != nil {
 		tx.Rollback()
@@ -360,7 +526,7 @@ func (s *transactionService) TransferToGroup(userID uuid.UUID, req dto.TransferT
 		Entity:      "transaction",
 		EntityID:    groupTransaction.ID,
 		Action:      "receive_from_member",
-		Changes:     map[string]interface{}{"amount": req.Amount, "member_id": userID.String()},
+		Changes:     map[string]interface{}{"amount": groupAmount, "member_id": userID.String()},
 		PerformedBy: userID,
 		GroupID:     &req.GroupID,
 	}
@@ -410,8 +576,8 @@ func (s *transactionService) PayGroupExpense(userID, groupID uuid.UUID, req dto.
 		return nil, &errors.AppError{Code: "FORBIDDEN", Message: "Expense does not belong to this group"}
 	}
 
-	if expense.Status != "planned" {
-		return nil, &errors.AppError{Code: "INVALID_STATUS", Message: "Expense is not in planned status"}
+	if err := checkPayable(expense); err != nil {
+		return nil, err
 	}
 
 	// Start transaction
@@ -443,6 +609,7 @@ func (s *transactionService) PayGroupExpense(userID, groupID uuid.UUID, req dto.
 		OwnerID:          groupID,
 		Type:             "DEBIT",
 		Amount:           req.ActualPrice,
+		Currency:         group.Currency,
 		Balance:          group.Balance,
 		Category:         expense.Category,
 		Source:           "expense_payment",
@@ -464,18 +631,33 @@ func (s *transactionService) PayGroupExpense(userID, groupID uuid.UUID, req dto.
 	}
 
 	// Update planned expense
-	now := time.Now()
-	expense.Status = "bought"
-	expense.ActualPrice = &req.ActualPrice
-	expense.PaidBy = &userID
-	expense.PaidAt = &now
+	payment := &models.ExpensePayment{
+		PlannedExpenseID: expense.ID,
+		Amount:           req.ActualPrice,
+		PaidBy:           userID,
+		PaidAt:           time.Now(),
+		TransactionID:    &transaction.ID,
+		Description:      req.Description,
+	}
 
-	if err := tx.Save(expense).Error; err != nil {
+	if err := recordPayment(tx, expense, payment, req.Partial); err != nil {
 		tx.Rollback()
+		if appErr, ok := err.(*errors.AppError); ok {
+			return nil, appErr
+		}
 		log.Error().Err(err).Msg("Failed to update planned expense")
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
 	}
 
+	// Paying off the latest occurrence of a recurring expense plans the next one
+	if expense.Status == "bought" {
+		if _, err := spawnAfter(tx, expense, userID); err != nil {
+			tx.Rollback()
+			log.Error().Err(err).Msg("Failed to spawn next recurring expense")
+			return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to pay expense"}
+		}
+	}
+
 	// Save updated group balance
 	if err := tx.Save(group).Error; err != nil {
 		tx.Rollback()
@@ -484,11 +666,20 @@ func (s *transactionService) PayGroupExpense(userID, groupID uuid.UUID, req dto.
 	}
 
 	// Create audit log
+	action := "record_payment"
+	if expense.Status == "bought" {
+		action = "mark_as_paid"
+	}
 	auditLog := &models.AuditLog{
-		Entity:      "planned_expense",
-		EntityID:    expense.ID,
-		Action:      "mark_as_paid",
-		Changes:     map[string]interface{}{"actual_price": req.ActualPrice, "paid_by": userID.String()},
+		Entity:   "planned_expense",
+		EntityID: expense.ID,
+		Action:   action,
+		Changes: map[string]interface{}{
+			"amount":      req.ActualPrice,
+			"paid_amount": expense.PaidAmount,
+			"status":      expense.Status,
+			"paid_by":     userID.String(),
+		},
 		PerformedBy: userID,
 		GroupID:     &groupID,
 	}
@@ -546,6 +737,7 @@ func (s *transactionService) RecordExternalIncome(userID, groupID uuid.UUID, amo
 		OwnerID:     groupID,
 		Type:        "CREDIT",
 		Amount:      amount,
+		Currency:    group.Currency,
 		Balance:     group.Balance,
 		Category:    "external_income",
 		Source:      source,
@@ -603,8 +795,13 @@ func (s *transactionService) RecordExternalIncome(userID, groupID uuid.UUID, amo
 	return s.mapTransactionToResponse(fullTransaction), nil
 }
 
-func (s *transactionService) GetPersonalTransactions(userID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error) {
-	transactions, total, err := s.transactionRepo.FindByUser(userID, page, limit)
+func (s *transactionService) GetPersonalTransactions(userID uuid.UUID, filter dto.TransactionFilter, page, limit int) ([]dto.TransactionResponse, int64, error) {
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.transactionRepo.FindByUser(userID, repoFilter, page, limit)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get personal transactions")
 		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transactions"}
@@ -618,14 +815,19 @@ func (s *transactionService) GetPersonalTransactions(userID uuid.UUID, page, lim
 	return response, total, nil
 }
 
-func (s *transactionService) GetGroupTransactions(userID, groupID uuid.UUID, page, limit int) ([]dto.TransactionResponse, int64, error) {
+func (s *transactionService) GetGroupTransactions(userID, groupID uuid.UUID, filter dto.TransactionFilter, page, limit int) ([]dto.TransactionResponse, int64, error) {
 	// Check if user is a member of the group
 	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
 	if err != nil || userGroup.Status != "active" {
 		return nil, 0, &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
 	}
 
-	transactions, total, err := s.transactionRepo.FindByGroup(groupID, page, limit)
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	transactions, total, err := s.transactionRepo.FindByGroup(groupID, repoFilter, page, limit)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to get group transactions")
 		return nil, 0, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transactions"}
@@ -639,6 +841,118 @@ func (s *transactionService) GetGroupTransactions(userID, groupID uuid.UUID, pag
 	return response, total, nil
 }
 
+func (s *transactionService) GetPersonalTransactionsAfter(userID uuid.UUID, filter dto.TransactionFilter, cursor string, limit int) ([]dto.TransactionResponse, string, error) {
+	after, err := repositories.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", &errors.AppError{Code: "INVALID_CURSOR", Message: "Invalid cursor"}
+	}
+
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transactions, next, err := s.transactionRepo.FindByUserAfter(userID, repoFilter, after, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get personal transactions")
+		return nil, "", &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transactions"}
+	}
+
+	var response []dto.TransactionResponse
+	for _, transaction := range transactions {
+		response = append(response, *s.mapTransactionToResponse(&transaction))
+	}
+
+	return response, encodeCursor(next), nil
+}
+
+func (s *transactionService) GetGroupTransactionsAfter(userID, groupID uuid.UUID, filter dto.TransactionFilter, cursor string, limit int) ([]dto.TransactionResponse, string, error) {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return nil, "", &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	after, err := repositories.DecodeCursor(cursor)
+	if err != nil {
+		return nil, "", &errors.AppError{Code: "INVALID_CURSOR", Message: "Invalid cursor"}
+	}
+
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	transactions, next, err := s.transactionRepo.FindByGroupAfter(groupID, repoFilter, after, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get group transactions")
+		return nil, "", &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transactions"}
+	}
+
+	var response []dto.TransactionResponse
+	for _, transaction := range transactions {
+		response = append(response, *s.mapTransactionToResponse(&transaction))
+	}
+
+	return response, encodeCursor(next), nil
+}
+
+// ExportPersonalTransactions passes every transaction of the personal listing
+// to write, newest first, loading one page at a time.
+func (s *transactionService) ExportPersonalTransactions(userID uuid.UUID, filter dto.TransactionFilter, write func(*dto.TransactionResponse) error) error {
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return s.exportTransactions(func(cursor *repositories.Cursor) ([]models.Transaction, *repositories.Cursor, error) {
+		return s.transactionRepo.FindByUserAfter(userID, repoFilter, cursor, exportPageSize)
+	}, write)
+}
+
+// ExportGroupTransactions is ExportPersonalTransactions for a group ledger.
+// Membership is checked before anything is written.
+func (s *transactionService) ExportGroupTransactions(userID, groupID uuid.UUID, filter dto.TransactionFilter, write func(*dto.TransactionResponse) error) error {
+	// Check if user is a member of the group
+	userGroup, err := s.groupRepo.FindByUserAndGroup(userID, groupID)
+	if err != nil || userGroup.Status != "active" {
+		return &errors.AppError{Code: "FORBIDDEN", Message: "You are not a member of this group"}
+	}
+
+	repoFilter, err := buildTransactionFilter(filter)
+	if err != nil {
+		return err
+	}
+
+	return s.exportTransactions(func(cursor *repositories.Cursor) ([]models.Transaction, *repositories.Cursor, error) {
+		return s.transactionRepo.FindByGroupAfter(groupID, repoFilter, cursor, exportPageSize)
+	}, write)
+}
+
+// exportTransactions walks the keyset pages returned by fetch until the last
+// one. Errors from write stop the export and are returned as is.
+func (s *transactionService) exportTransactions(fetch func(cursor *repositories.Cursor) ([]models.Transaction, *repositories.Cursor, error), write func(*dto.TransactionResponse) error) error {
+	var cursor *repositories.Cursor
+	for {
+		transactions, next, err := fetch(cursor)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to export transactions")
+			return &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to export transactions"}
+		}
+
+		for i := range transactions {
+			if err := write(s.mapTransactionToResponse(&transactions[i])); err != nil {
+				return err
+			}
+		}
+
+		if next == nil {
+			return nil
+		}
+		cursor = next
+	}
+}
+
 func (s *transactionService) GetTransaction(userID, transactionID uuid.UUID) (*dto.TransactionResponse, error) {
 	transaction, err := s.transactionRepo.FindByID(transactionID)
 	if err != nil {
@@ -661,6 +975,59 @@ func (s *transactionService) GetTransaction(userID, transactionID uuid.UUID) (*d
 	return s.mapTransactionToResponse(transaction), nil
 }
 
+func (s *transactionService) SetTransactionTags(userID, transactionID uuid.UUID, req dto.SetTagsRequest) (*dto.TransactionResponse, error) {
+	// Reuse the read access rules before touching the tags
+	if _, err := s.GetTransaction(userID, transactionID); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.transactionRepo.FindByID(transactionID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "TRANSACTION_NOT_FOUND", Message: "Transaction not found"}
+	}
+
+	var groupID *uuid.UUID
+	if transaction.OwnerType == "GROUP" {
+		groupID = transaction.GroupID
+	}
+
+	tags, err := resolveTags(s.tagRepo, userID, groupID, req.TagIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.transactionRepo.ReplaceTags(transaction, tags); err != nil {
+		log.Error().Err(err).Msg("Failed to update transaction tags")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update tags"}
+	}
+
+	// Create audit log
+	var tagNames []string
+	for _, tag := range tags {
+		tagNames = append(tagNames, tag.Name)
+	}
+
+	auditLog := &models.AuditLog{
+		Entity:      "transaction",
+		EntityID:    transaction.ID,
+		Action:      "set_tags",
+		Changes:     map[string]interface{}{"tags": tagNames},
+		PerformedBy: userID,
+		GroupID:     groupID,
+	}
+
+	if err := s.auditRepo.Create(auditLog); err != nil {
+		log.Error().Err(err).Msg("Failed to create audit log")
+	}
+
+	updated, err := s.transactionRepo.FindByID(transactionID)
+	if err != nil {
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to get transaction data"}
+	}
+
+	return s.mapTransactionToResponse(updated), nil
+}
+
 func (s *transactionService) mapTransactionToResponse(transaction *models.Transaction) *dto.TransactionResponse {
 	response := &dto.TransactionResponse{
 		ID:               transaction.ID,
@@ -668,14 +1035,26 @@ func (s *transactionService) mapTransactionToResponse(transaction *models.Transa
 		OwnerID:          transaction.OwnerID,
 		Type:             transaction.Type,
 		Amount:           transaction.Amount,
+		Currency:         transaction.Currency,
 		Balance:          transaction.Balance,
 		Category:         transaction.Category,
 		Source:           transaction.Source,
 		Description:      transaction.Description,
 		CreatedAt:        transaction.CreatedAt.Format(time.RFC3339),
+		FormattedAmount:  formatAmount(transaction.Amount, transaction.Currency),
+		FormattedBalance: formatAmount(transaction.Balance, transaction.Currency),
+		OriginalAmount:   transaction.OriginalAmount,
+		OriginalCurrency: transaction.OriginalCurrency,
+		ExchangeRate:     transaction.ExchangeRate,
 		GroupID:          transaction.GroupID,
 		PaidBy:           transaction.PaidBy,
 		PlannedExpenseID: transaction.PlannedExpenseID,
+		LineItems:        mapLineItemsToResponse(transaction.LineItems),
+		Tags:             mapTagsToResponse(transaction.Tags),
+	}
+
+	if transaction.OriginalAmount != nil {
+		response.FormattedOriginalAmount = formatAmount(*transaction.OriginalAmount, transaction.OriginalCurrency)
 	}
 
 	// Add user info if available
@@ -687,6 +1066,7 @@ func (s *transactionService) mapTransactionToResponse(transaction *models.Transa
 			FirstName:   transaction.User.FirstName,
 			LastName:    transaction.User.LastName,
 			Balance:     transaction.User.Balance,
+			Currency:    transaction.User.Currency,
 			IsActive:    transaction.User.IsActive,
 			CreatedAt:   transaction.User.CreatedAt.Format(time.RFC3339),
 		}
@@ -700,6 +1080,7 @@ func (s *transactionService) mapTransactionToResponse(transaction *models.Transa
 			Description: transaction.Group.Description,
 			Balance:     transaction.Group.Balance,
 			CreatedBy:   transaction.Group.CreatedBy,
+			Currency:    transaction.Group.Currency,
 			IsActive:    transaction.Group.IsActive,
 			CreatedAt:   transaction.Group.CreatedAt.Format(time.RFC3339),
 		}
@@ -707,3 +1088,316 @@ func (s *transactionService) mapTransactionToResponse(transaction *models.Transa
 
 	return response
 }
+
+// validateLineItems checks that a split transaction is fully allocated.
+func validateLineItems(amount int64, items []dto.TransactionLineItemRequest) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Amount
+	}
+
+	if total != amount {
+		return &errors.AppError{Code: "INVALID_LINE_ITEMS", Message: "Line item amounts must add up to the transaction amount"}
+	}
+
+	return nil
+}
+
+// resolveCategories validates the category, source and line item categories
+// of a request against the catalog and replaces them with the catalog spelling.
+func (s *transactionService) resolveCategories(userID uuid.UUID, groupID *uuid.UUID, req *dto.CreateTransactionRequest) error {
+	category, err := resolveCategory(s.categoryRepo, "category", userID, groupID, req.Category)
+	if err != nil {
+		return err
+	}
+	req.Category = category
+
+	source, err := resolveCategory(s.categoryRepo, "source", userID, groupID, req.Source)
+	if err != nil {
+		return err
+	}
+	req.Source = source
+
+	items := make([]dto.TransactionLineItemRequest, len(req.LineItems))
+	for i, item := range req.LineItems {
+		item.Category, err = resolveCategory(s.categoryRepo, "category", userID, groupID, item.Category)
+		if err != nil {
+			return err
+		}
+		items[i] = item
+	}
+	req.LineItems = items
+
+	return nil
+}
+
+// applyRules lets the first matching categorization rule of the ledger set
+// the category of a new transaction and add its tag. The rule is recorded in
+// the transaction metadata.
+func (s *transactionService) applyRules(userID uuid.UUID, groupID *uuid.UUID, req *dto.CreateTransactionRequest, tags []models.Tag) ([]models.Tag, error) {
+	if req.SkipRules {
+		return tags, nil
+	}
+
+	rules, err := s.ruleRepo.FindActive(userID, groupID)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load categorization rules")
+		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to create transaction"}
+	}
+
+	rule := firstMatchingRule(rules, req.Type, req.Amount, req.Description)
+	if rule == nil {
+		return tags, nil
+	}
+
+	req.Category = rule.Category
+	if rule.Tag != nil && !hasTag(tags, rule.Tag.ID) {
+		tags = append(tags, *rule.Tag)
+	}
+
+	metadata := map[string]interface{}{"categorization_rule_id": rule.ID.String()}
+	for key, value := range req.Metadata {
+		metadata[key] = value
+	}
+	req.Metadata = metadata
+
+	return tags, nil
+}
+
+// flagAnomaly records a new DEBIT as a spending anomaly when it is far above
+// the owner's usual spending in its category, and notifies the user or every
+// active group member. Imported statement lines are historical and skipped.
+// The transaction is already committed, so failures are only logged.
+func (s *transactionService) flagAnomaly(transaction *models.Transaction) *dto.AnomalyResponse {
+	if transaction.Type != "DEBIT" {
+		return nil
+	}
+	if _, imported := transaction.Metadata["import_batch_id"]; imported {
+		return nil
+	}
+
+	startDate := transaction.CreatedAt.AddDate(0, 0, -anomalyWindowDays)
+	history, err := s.transactionRepo.FindDebitAmounts(transaction.OwnerType, transaction.OwnerID, transaction.Category, startDate, transaction.CreatedAt)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load spending history")
+		return nil
+	}
+
+	median, mad, score, outlier := scoreDebit(history, transaction.Amount)
+	if !outlier {
+		return nil
+	}
+
+	anomaly := &models.SpendingAnomaly{
+		OwnerType:     transaction.OwnerType,
+		OwnerID:       transaction.OwnerID,
+		TransactionID: transaction.ID,
+		Category:      transaction.Category,
+		Amount:        transaction.Amount,
+		Currency:      transaction.Currency,
+		Median:        median,
+		MAD:           mad,
+		Score:         score,
+		SampleSize:    len(history),
+		WindowDays:    anomalyWindowDays,
+		RecordedBy:    transaction.UserID,
+	}
+
+	if err := s.anomalyRepo.Create(anomaly); err != nil {
+		log.Error().Err(err).Msg("Failed to record spending anomaly")
+		return nil
+	}
+
+	recipients := []uuid.UUID{transaction.UserID}
+	if transaction.OwnerType == "GROUP" {
+		members, err := s.groupRepo.FindMembers(transaction.OwnerID)
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to get group members")
+		}
+		recipients = recipients[:0]
+		for _, member := range members {
+			if member.Status == "active" {
+				recipients = append(recipients, member.UserID)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("%s spent on %s, against a usual %s over the last %d days",
+		formatAmount(transaction.Amount, transaction.Currency), transaction.Category,
+		formatAmount(median, transaction.Currency), anomalyWindowDays)
+	data := map[string]interface{}{
+		"anomaly_id":     anomaly.ID.String(),
+		"transaction_id": transaction.ID.String(),
+	}
+	if transaction.GroupID != nil {
+		data["group_id"] = transaction.GroupID.String()
+	}
+
+	var notifications []models.Notification
+	for _, recipient := range recipients {
+		notifications = append(notifications, models.Notification{
+			UserID:  recipient,
+			Type:    "spending_anomaly",
+			Title:   "Unusually large expense",
+			Message: message,
+			Data:    data,
+		})
+	}
+
+	if err := s.notificationRepo.CreateBatch(notifications); err != nil {
+		log.Error().Err(err).Msg("Failed to create anomaly notifications")
+	}
+
+	return mapAnomalyToResponse(anomaly)
+}
+
+func createLineItems(tx *gorm.DB, transactionID uuid.UUID, items []dto.TransactionLineItemRequest) error {
+	for _, item := range items {
+		lineItem := &models.TransactionLineItem{
+			TransactionID: transactionID,
+			Category:      item.Category,
+			Amount:        item.Amount,
+			Note:          item.Note,
+		}
+		if err := tx.Create(lineItem).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mapLineItemsToResponse(items []models.TransactionLineItem) []dto.TransactionLineItemResponse {
+	var response []dto.TransactionLineItemResponse
+	for _, item := range items {
+		response = append(response, dto.TransactionLineItemResponse{
+			ID:       item.ID,
+			Category: item.Category,
+			Amount:   item.Amount,
+			Note:     item.Note,
+		})
+	}
+	return response
+}
+
+// formatAmount renders an amount for display in the configured locale.
+func formatAmount(amount int64, currency string) string {
+	return money.Money{Amount: amount, Currency: currency}.Format(money.DefaultLocale)
+}
+
+// checkTransactionCurrency rejects a request currency that differs from the
+// owner's; amounts in other currencies must be converted before posting.
+func checkTransactionCurrency(requested, ownerCurrency string) error {
+	if requested != "" && !strings.EqualFold(requested, ownerCurrency) {
+		return &errors.AppError{Code: "CURRENCY_MISMATCH", Message: "Transactions must be in the owner's currency (" + ownerCurrency + ")"}
+	}
+	return nil
+}
+
+// convertOriginalAmount fills in the amount of a request entered in a foreign
+// currency, converting it at the rate effective on the request's rate date.
+// It returns the rate used, or an empty string when none was needed.
+func (s *transactionService) convertOriginalAmount(req *dto.CreateTransactionRequest, ownerCurrency string) (string, error) {
+	if req.OriginalAmount == 0 {
+		return "", nil
+	}
+
+	originalCurrency := money.NormalizeCode(req.OriginalCurrency)
+	if originalCurrency == ownerCurrency {
+		// Nothing to convert; keep the transaction in its plain form
+		if req.Amount == 0 {
+			req.Amount = req.OriginalAmount
+		}
+		req.OriginalAmount = 0
+		req.OriginalCurrency = ""
+		return "", nil
+	}
+	req.OriginalCurrency = originalCurrency
+
+	// Both amounts given: the member knows what was actually charged, so the
+	// rate is whatever the two amounts imply
+	if req.Amount > 0 {
+		rate, err := money.RateBetween(
+			money.Money{Amount: req.OriginalAmount, Currency: originalCurrency},
+			money.Money{Amount: req.Amount, Currency: ownerCurrency},
+		)
+		if err != nil {
+			return "", &errors.AppError{Code: "INVALID_AMOUNT", Message: "Original and converted amounts must both be positive"}
+		}
+		return formatRate(rate), nil
+	}
+
+	date := time.Now().UTC()
+	if req.RateDate != nil {
+		date = *req.RateDate
+	}
+
+	rate, _, _, err := lookupRate(s.rateRepo, originalCurrency, ownerCurrency, date)
+	if err != nil {
+		return "", err
+	}
+
+	converted, err := money.Convert(money.Money{Amount: req.OriginalAmount, Currency: originalCurrency}, ownerCurrency, rate)
+	if err != nil || converted.Amount <= 0 {
+		return "", &errors.AppError{Code: "INVALID_AMOUNT", Message: "Original amount is too small to convert"}
+	}
+
+	req.Amount = converted.Amount
+	return formatRate(rate), nil
+}
+
+func originalAmount(req dto.CreateTransactionRequest) *int64 {
+	if req.OriginalAmount == 0 {
+		return nil
+	}
+	amount := req.OriginalAmount
+	return &amount
+}
+
+// convertTransferAmount returns the amount credited to the receiving side of
+// a transfer. Different currencies require an explicit exchange rate, quoted
+// as units of the target currency per unit of the source currency.
+func convertTransferAmount(amount int64, fromCurrency, toCurrency, exchangeRate string) (int64, error) {
+	if fromCurrency == toCurrency {
+		return amount, nil
+	}
+
+	if exchangeRate == "" {
+		return 0, &errors.AppError{Code: "EXCHANGE_RATE_REQUIRED", Message: "An exchange rate is required to transfer from " + fromCurrency + " to " + toCurrency}
+	}
+
+	rate, err := money.ParseRate(exchangeRate)
+	if err != nil {
+		return 0, &errors.AppError{Code: "INVALID_EXCHANGE_RATE", Message: "Invalid exchange rate"}
+	}
+
+	converted, err := money.Convert(money.Money{Amount: amount, Currency: fromCurrency}, toCurrency, rate)
+	if err != nil || converted.Amount <= 0 {
+		return 0, &errors.AppError{Code: "INVALID_EXCHANGE_RATE", Message: "Transfer amount cannot be converted at this rate"}
+	}
+
+	return converted.Amount, nil
+}
+
+// buildTransactionFilter converts the query parameters of a listing into a
+// repository filter.
+func buildTransactionFilter(filter dto.TransactionFilter) (repositories.TransactionFilter, error) {
+	tagID, err := parseOptionalID(filter.TagID)
+	if err != nil {
+		return repositories.TransactionFilter{}, &errors.AppError{Code: "INVALID_REQUEST", Message: "Invalid tag ID"}
+	}
+
+	return repositories.TransactionFilter{TagID: tagID}, nil
+}
+
+// encodeCursor turns the repository cursor into the opaque next_cursor value;
+// an empty string tells the client there are no more pages.
+func encodeCursor(cursor *repositories.Cursor) string {
+	if cursor == nil {
+		return ""
+	}
+	return cursor.Encode()
+}
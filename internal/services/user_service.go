@@ -5,6 +5,7 @@ import (
 	"balanca/internal/repositories"
 	"balanca/internal/utils"
 	"balanca/pkg/errors"
+	"balanca/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
@@ -44,6 +45,7 @@ func (s *userService) GetProfile(userID uuid.UUID) (*dto.UserResponse, error) {
 		FirstName:   user.FirstName,
 		LastName:    user.LastName,
 		Balance:     user.Balance,
+		Currency:    user.Currency,
 		IsActive:    user.IsActive,
 		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 	}, nil
@@ -73,6 +75,17 @@ func (s *userService) UpdateProfile(userID uuid.UUID, req dto.UpdateUserRequest)
 		user.LastName = req.LastName
 	}
 
+	if req.Currency != "" && money.NormalizeCode(req.Currency) != user.Currency {
+		// Existing balances are not converted, so only an empty wallet can switch
+		if user.Balance != 0 {
+			return nil, &errors.AppError{Code: "CURRENCY_CHANGE_NOT_ALLOWED", Message: "Currency can only be changed while the balance is zero"}
+		}
+		if !money.IsSupported(req.Currency) {
+			return nil, &errors.AppError{Code: "UNSUPPORTED_CURRENCY", Message: "Unsupported currency"}
+		}
+		user.Currency = money.NormalizeCode(req.Currency)
+	}
+
 	if err := s.userRepo.Update(user); err != nil {
 		log.Error().Err(err).Msg("Failed to update user profile")
 		return nil, &errors.AppError{Code: "SERVER_ERROR", Message: "Failed to update profile"}
@@ -85,6 +98,7 @@ func (s *userService) UpdateProfile(userID uuid.UUID, req dto.UpdateUserRequest)
 		FirstName:   user.FirstName,
 		LastName:    user.LastName,
 		Balance:     user.Balance,
+		Currency:    user.Currency,
 		IsActive:    user.IsActive,
 		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 	}, nil
@@ -163,6 +177,7 @@ func (s *userService) GetUserGroups(userID uuid.UUID) ([]dto.GroupResponse, erro
 					FirstName:   member.User.FirstName,
 					LastName:    member.User.LastName,
 					Balance:     member.User.Balance,
+					Currency:    member.User.Currency,
 					IsActive:    member.User.IsActive,
 					CreatedAt:   member.User.CreatedAt.Format(time.RFC3339),
 				},
@@ -175,6 +190,7 @@ func (s *userService) GetUserGroups(userID uuid.UUID) ([]dto.GroupResponse, erro
 			Description: group.Description,
 			Balance:     group.Balance,
 			CreatedBy:   group.CreatedBy,
+			Currency:    group.Currency,
 			IsActive:    group.IsActive,
 			CreatedAt:   group.CreatedAt.Format(time.RFC3339),
 			Members:     members,
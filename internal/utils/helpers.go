@@ -1,9 +1,8 @@
 package utils
 
 import (
+	"balanca/pkg/money"
 	"fmt"
-	"strconv"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,27 +12,23 @@ func ParseUUID(id string) (uuid.UUID, error) {
 	return uuid.Parse(id)
 }
 
-func FormatCurrency(amount int64) string {
-	// Convert cents to dollars/pounds/euros
-	dollars := float64(amount) / 100
-	return fmt.Sprintf("%.2f", dollars)
+// FormatCurrency renders an amount stored in minor units with the number of
+// decimals of its currency, e.g. 1050 USD -> "10.50" and 1050 RWF -> "1050".
+func FormatCurrency(amount int64, currency string) string {
+	return money.Money{Amount: amount, Currency: money.NormalizeCode(currency)}.Decimal()
 }
 
-func ParseCurrency(amountStr string) (int64, error) {
-	// Remove any currency symbols and whitespace
-	amountStr = strings.TrimSpace(amountStr)
-	amountStr = strings.ReplaceAll(amountStr, "$", "")
-	amountStr = strings.ReplaceAll(amountStr, "€", "")
-	amountStr = strings.ReplaceAll(amountStr, "£", "")
-	amountStr = strings.ReplaceAll(amountStr, ",", "")
-
-	amount, err := strconv.ParseFloat(amountStr, 64)
+// ParseCurrency reads a user-entered amount such as "$1,234.56" or
+// "1.234,56 €" in the currency it names, or in the default currency when it
+// names none. Separators are guessed; use money.Parse with a locale when the
+// input's locale is known.
+func ParseCurrency(amountStr string) (money.Money, error) {
+	amount, err := money.Parse(amountStr, "", nil)
 	if err != nil {
-		return 0, fmt.Errorf("invalid currency format: %w", err)
+		return money.Money{}, fmt.Errorf("invalid currency format: %w", err)
 	}
 
-	// Convert to cents
-	return int64(amount * 100), nil
+	return amount, nil
 }
 
 func BeginningOfMonth(t time.Time) time.Time {
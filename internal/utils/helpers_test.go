@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"balanca/pkg/money"
+	"errors"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    money.Money
+		wantErr error
+	}{
+		{input: "0.29", want: money.Money{Amount: 29, Currency: money.DefaultCurrency}},
+		{input: "10", want: money.Money{Amount: 1000, Currency: money.DefaultCurrency}},
+		{input: "$1,234.56", want: money.Money{Amount: 123456, Currency: "USD"}},
+		{input: "1.234,56 €", want: money.Money{Amount: 123456, Currency: "EUR"}},
+		{input: "USD 5.", want: money.Money{Amount: 500, Currency: "USD"}},
+		{input: "-12.50", want: money.Money{Amount: -1250, Currency: money.DefaultCurrency}},
+		{input: "+12.50", want: money.Money{Amount: 1250, Currency: money.DefaultCurrency}},
+		{input: "(3.00)", want: money.Money{Amount: -300, Currency: money.DefaultCurrency}},
+		{input: "RWF 1 500", want: money.Money{Amount: 1500, Currency: "RWF"}},
+		{input: "1,500 JPY", want: money.Money{Amount: 1500, Currency: "JPY"}},
+		{input: "12.345 USD", want: money.Money{Amount: 1234500, Currency: "USD"}}, // a single "." before three digits groups them
+		{input: "12.3456 USD", wantErr: money.ErrInvalidAmount},
+		{input: "12 XYZ", wantErr: money.ErrUnknownCurrency},
+		{input: "", wantErr: money.ErrInvalidAmount},
+		{input: "ten", wantErr: money.ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCurrency(tt.input)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseCurrency(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCurrency(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCurrency(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{amount: 1050, currency: "USD", want: "10.50"},
+		{amount: 1050, currency: "RWF", want: "1050"},
+		{amount: -29, currency: "EUR", want: "-0.29"},
+		{amount: 1250, currency: "KWD", want: "1.250"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatCurrency(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatCurrency(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
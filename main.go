@@ -7,7 +7,10 @@ import (
 	"balanca/internal/middleware"
 	"balanca/internal/repositories"
 	"balanca/internal/services"
+	"balanca/internal/storage"
+	"balanca/pkg/money"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -25,6 +28,19 @@ func main() {
 		log.Fatal("Failed to load configuration:", err)
 	}
 
+	// Amounts of users and groups created without a currency use this one
+	if !money.IsSupported(cfg.Money.DefaultCurrency) {
+		log.Fatal("Unsupported DEFAULT_CURRENCY:", cfg.Money.DefaultCurrency)
+	}
+	money.DefaultCurrency = money.NormalizeCode(cfg.Money.DefaultCurrency)
+
+	// Formatted amounts in responses use this locale
+	locale, ok := money.LookupLocale(cfg.Money.DefaultLocale)
+	if !ok {
+		log.Fatal("Unsupported DEFAULT_LOCALE:", cfg.Money.DefaultLocale)
+	}
+	money.DefaultLocale = locale
+
 	// Initialize database
 	if err := database.Connect(&cfg.Database); err != nil {
 		log.Fatal("Failed to connect to database:", err)
@@ -42,14 +58,96 @@ func main() {
 	transactionRepo := repositories.NewTransactionRepository(db)
 	expenseRepo := repositories.NewPlannedExpenseRepository(db)
 	auditRepo := repositories.NewAuditLogRepository(db)
+	tagRepo := repositories.NewTagRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	attachmentRepo := repositories.NewAttachmentRepository(db)
+	rateRepo := repositories.NewExchangeRateRepository(db)
+	importRepo := repositories.NewImportRepository(db)
+	ruleRepo := repositories.NewRuleRepository(db)
+	budgetRepo := repositories.NewBudgetRepository(db)
+	anomalyRepo := repositories.NewAnomalyRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	approvalRepo := repositories.NewApprovalRepository(db)
+	proposalRepo := repositories.NewProposalRepository(db)
+	reimbursementRepo := repositories.NewReimbursementRepository(db)
+	splitRepo := repositories.NewSplitRepository(db)
+	settlementRepo := repositories.NewSettlementRepository(db)
+	shoppingListRepo := repositories.NewShoppingListRepository(db)
+	reminderRepo := repositories.NewReminderRepository(db)
+
+	// Initialize storage
+	blobStore, err := storage.NewLocalBlobStore(cfg.Storage.Path)
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
 
 	// Initialize services
 	authService := services.NewAuthService(userRepo, cfg.JWT.Secret, cfg.JWT.Expiration, cfg.JWT.RefreshTokenExpiration)
 	userService := services.NewUserService(userRepo, groupRepo)
-	groupService := services.NewGroupService(groupRepo, userRepo, auditRepo, db)
-	transactionService := services.NewTransactionService(transactionRepo, userRepo, groupRepo, expenseRepo, auditRepo, db)
-	expenseService := services.NewPlannedExpenseService(expenseRepo, userRepo, groupRepo, auditRepo, db)
+	approvalService := services.NewApprovalService(approvalRepo, expenseRepo, groupRepo, auditRepo, notificationRepo, db)
+	groupService := services.NewGroupService(groupRepo, userRepo, auditRepo, approvalService, db)
+	transactionService := services.NewTransactionService(transactionRepo, userRepo, groupRepo, expenseRepo, auditRepo, tagRepo, categoryRepo, rateRepo, ruleRepo, anomalyRepo, notificationRepo, db)
+	expenseService := services.NewPlannedExpenseService(expenseRepo, userRepo, groupRepo, auditRepo, tagRepo, categoryRepo, approvalRepo, notificationRepo, db)
 	reportService := services.NewReportService(transactionRepo, userRepo, groupRepo)
+	tagService := services.NewTagService(tagRepo, groupRepo, auditRepo)
+	categoryService := services.NewCategoryService(categoryRepo, groupRepo, auditRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, transactionRepo, expenseRepo, groupRepo, auditRepo,
+		blobStore, cfg.Storage.MaxUploadSize, cfg.Storage.URLSecret, cfg.Storage.URLExpiration)
+	exchangeRateService := services.NewExchangeRateService(rateRepo)
+	importService := services.NewImportService(importRepo, transactionRepo, userRepo, groupRepo, categoryRepo, auditRepo, transactionService)
+	ruleService := services.NewRuleService(ruleRepo, transactionRepo, groupRepo, tagRepo, categoryRepo, auditRepo, db)
+	budgetService := services.NewBudgetService(budgetRepo, transactionRepo, userRepo, groupRepo, categoryRepo, auditRepo)
+	anomalyService := services.NewAnomalyService(anomalyRepo, groupRepo)
+	proposalService := services.NewProposalService(proposalRepo, expenseRepo, groupRepo, auditRepo, notificationRepo, db)
+	reimbursementService := services.NewReimbursementService(reimbursementRepo, expenseRepo, userRepo, groupRepo, notificationRepo, db)
+	splitService := services.NewSplitService(splitRepo, expenseRepo, reimbursementRepo, settlementRepo, groupRepo, db)
+	settlementService := services.NewSettlementService(settlementRepo, userRepo, groupRepo, auditRepo, notificationRepo, db)
+	shoppingListService := services.NewShoppingListService(shoppingListRepo, expenseRepo, userRepo, groupRepo, categoryRepo, auditRepo, db)
+	reminderService := services.NewReminderService(reminderRepo, expenseRepo, groupRepo,
+		cfg.Reminders.DaysBefore, cfg.Reminders.DaysAfter, cfg.Reminders.EscalationDays)
+	forecastService := services.NewForecastService(transactionRepo, expenseRepo, userRepo, groupRepo)
+	auditLogService := services.NewAuditLogService(auditRepo, groupRepo)
+
+	// Seed system categories
+	if err := categoryService.SeedSystemCategories(); err != nil {
+		log.Println("Failed to seed system categories:", err)
+	}
+
+	// Plan the next occurrence of recurring expenses when it falls due
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if _, err := expenseService.SpawnDueOccurrences(); err != nil {
+				log.Println("Failed to spawn recurring expenses:", err)
+			}
+			<-ticker.C
+		}
+	}()
+
+	// Close votes on proposed purchases once their deadline passes
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			if _, err := proposalService.CloseDueProposals(); err != nil {
+				log.Println("Failed to close proposals:", err)
+			}
+			<-ticker.C
+		}
+	}()
+
+	// Remind owners of upcoming and overdue expenses, escalating to managers
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			if _, err := reminderService.SendReminders(); err != nil {
+				log.Println("Failed to send expense reminders:", err)
+			}
+			<-ticker.C
+		}
+	}()
 
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService)
@@ -58,6 +156,22 @@ func main() {
 	transactionHandler := handlers.NewTransactionHandler(transactionService)
 	expenseHandler := handlers.NewPlannedExpenseHandler(expenseService)
 	reportHandler := handlers.NewReportHandler(reportService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	categoryHandler := handlers.NewCategoryHandler(categoryService)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentService, cfg.Storage.MaxUploadSize)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateService, cfg.Money.RatesImportToken)
+	importHandler := handlers.NewImportHandler(importService, cfg.Storage.MaxUploadSize)
+	ruleHandler := handlers.NewRuleHandler(ruleService)
+	budgetHandler := handlers.NewBudgetHandler(budgetService)
+	forecastHandler := handlers.NewForecastHandler(forecastService)
+	auditLogHandler := handlers.NewAuditLogHandler(auditLogService)
+	anomalyHandler := handlers.NewAnomalyHandler(anomalyService)
+	approvalHandler := handlers.NewApprovalHandler(approvalService)
+	proposalHandler := handlers.NewProposalHandler(proposalService)
+	reimbursementHandler := handlers.NewReimbursementHandler(reimbursementService)
+	splitHandler := handlers.NewSplitHandler(splitService)
+	settlementHandler := handlers.NewSettlementHandler(settlementService)
+	shoppingListHandler := handlers.NewShoppingListHandler(shoppingListService)
 
 	// Setup Gin router
 	router := gin.Default()
@@ -80,6 +194,7 @@ func main() {
 		public.POST("/auth/register", authHandler.Register)
 		public.POST("/auth/login", authHandler.Login)
 		public.POST("/auth/refresh", authHandler.RefreshToken)
+		public.GET("/attachments/:attachmentId/download", attachmentHandler.Download)
 	}
 
 	// Protected routes
@@ -112,13 +227,21 @@ func main() {
 		// Personal Transactions
 		protected.POST("/transactions/personal", transactionHandler.CreatePersonalTransaction)
 		protected.GET("/transactions/personal", transactionHandler.GetPersonalTransactions)
+		protected.GET("/transactions/personal/export", transactionHandler.ExportPersonalTransactions)
+		protected.GET("/transactions/personal/anomalies", anomalyHandler.GetPersonalAnomalies)
 		protected.GET("/transactions/:transactionId", transactionHandler.GetTransaction)
 
 		// Group Transactions
 		protected.POST("/groups/:groupId/transactions", transactionHandler.CreateGroupTransaction)
 		protected.GET("/groups/:groupId/transactions", transactionHandler.GetGroupTransactions)
+		protected.GET("/groups/:groupId/transactions/export", transactionHandler.ExportGroupTransactions)
+		protected.GET("/groups/:groupId/transactions/anomalies", anomalyHandler.GetGroupAnomalies)
 		protected.POST("/transactions/transfer", transactionHandler.TransferToGroup)
 		protected.POST("/groups/:groupId/expenses/pay", transactionHandler.PayGroupExpense)
+		protected.POST("/groups/:groupId/expenses/pay-personally", reimbursementHandler.PayPersonally)
+		protected.GET("/groups/:groupId/reimbursements", reimbursementHandler.GetGroupReimbursements)
+		protected.POST("/groups/:groupId/reimbursements/:reimbursementId/settle", reimbursementHandler.SettleReimbursement)
+		protected.PUT("/transactions/:transactionId/tags", transactionHandler.SetTransactionTags)
 
 		// Personal Expenses
 		protected.POST("/expenses/personal", expenseHandler.CreatePersonalExpense)
@@ -134,13 +257,128 @@ func main() {
 		protected.POST("/groups/:groupId/expenses", expenseHandler.CreateGroupExpense)
 		protected.GET("/groups/:groupId/expenses", expenseHandler.GetGroupExpenses)
 
+		// Expense approvals
+		protected.GET("/groups/:groupId/approval-policy", approvalHandler.GetPolicy)
+		protected.PUT("/groups/:groupId/approval-policy", approvalHandler.SetPolicy)
+		protected.DELETE("/groups/:groupId/approval-policy", approvalHandler.DeletePolicy)
+		protected.POST("/expenses/:expenseId/approve", approvalHandler.ApproveExpense)
+		protected.POST("/expenses/:expenseId/reject", approvalHandler.RejectExpense)
+		protected.GET("/expenses/:expenseId/approvals", approvalHandler.GetApprovals)
+
+		// Purchase proposals
+		protected.POST("/expenses/:expenseId/proposal", proposalHandler.CreateProposal)
+		protected.GET("/expenses/:expenseId/proposal", proposalHandler.GetProposal)
+		protected.DELETE("/expenses/:expenseId/proposal", proposalHandler.CancelProposal)
+		protected.POST("/expenses/:expenseId/proposal/votes", proposalHandler.CastVote)
+		protected.GET("/groups/:groupId/proposals", proposalHandler.GetGroupProposals)
+
+		// Expense splits
+		protected.PUT("/expenses/:expenseId/split", splitHandler.SetSplit)
+		protected.GET("/expenses/:expenseId/split", splitHandler.GetSplit)
+		protected.DELETE("/expenses/:expenseId/split", splitHandler.DeleteSplit)
+		protected.GET("/groups/:groupId/balances", splitHandler.GetGroupBalances)
+
+		// Settlements between members
+		protected.POST("/groups/:groupId/settlements", settlementHandler.RecordSettlement)
+		protected.GET("/groups/:groupId/settlements", settlementHandler.GetGroupSettlements)
+		protected.GET("/groups/:groupId/settlements/:settlementId", settlementHandler.GetSettlement)
+		protected.POST("/groups/:groupId/settlements/:settlementId/confirm", settlementHandler.ConfirmSettlement)
+		protected.POST("/groups/:groupId/settlements/:settlementId/reject", settlementHandler.RejectSettlement)
+		protected.DELETE("/groups/:groupId/settlements/:settlementId", settlementHandler.CancelSettlement)
+
+		// Shopping lists
+		protected.POST("/shopping-lists", shoppingListHandler.CreatePersonalList)
+		protected.GET("/shopping-lists", shoppingListHandler.GetPersonalLists)
+		protected.POST("/groups/:groupId/shopping-lists", shoppingListHandler.CreateGroupList)
+		protected.GET("/groups/:groupId/shopping-lists", shoppingListHandler.GetGroupLists)
+		protected.GET("/shopping-lists/:listId", shoppingListHandler.GetList)
+		protected.PUT("/shopping-lists/:listId", shoppingListHandler.UpdateList)
+		protected.DELETE("/shopping-lists/:listId", shoppingListHandler.DeleteList)
+		protected.POST("/shopping-lists/:listId/items", shoppingListHandler.AddItem)
+		protected.PUT("/shopping-lists/:listId/items/:itemId", shoppingListHandler.UpdateItem)
+		protected.DELETE("/shopping-lists/:listId/items/:itemId", shoppingListHandler.DeleteItem)
+		protected.POST("/shopping-lists/:listId/items/:itemId/check", shoppingListHandler.CheckItem)
+		protected.POST("/shopping-lists/:listId/checkout", shoppingListHandler.Checkout)
+
+		// Tags
+		protected.POST("/tags", tagHandler.CreatePersonalTag)
+		protected.GET("/tags", tagHandler.GetPersonalTags)
+		protected.PUT("/tags/:tagId", tagHandler.UpdateTag)
+		protected.DELETE("/tags/:tagId", tagHandler.DeleteTag)
+		protected.POST("/groups/:groupId/tags", tagHandler.CreateGroupTag)
+		protected.GET("/groups/:groupId/tags", tagHandler.GetGroupTags)
+
+		// Categories
+		protected.POST("/categories", categoryHandler.CreatePersonalCategory)
+		protected.GET("/categories", categoryHandler.GetPersonalCategories)
+		protected.PUT("/categories/:categoryId", categoryHandler.UpdateCategory)
+		protected.DELETE("/categories/:categoryId", categoryHandler.DeleteCategory)
+		protected.POST("/categories/:categoryId/merge", categoryHandler.MergeCategories)
+		protected.POST("/groups/:groupId/categories", categoryHandler.CreateGroupCategory)
+		protected.GET("/groups/:groupId/categories", categoryHandler.GetGroupCategories)
+
+		// Attachments
+		protected.POST("/transactions/:transactionId/attachments", attachmentHandler.UploadTransactionAttachment)
+		protected.GET("/transactions/:transactionId/attachments", attachmentHandler.GetTransactionAttachments)
+		protected.POST("/expenses/:expenseId/attachments", attachmentHandler.UploadExpenseAttachment)
+		protected.GET("/expenses/:expenseId/attachments", attachmentHandler.GetExpenseAttachments)
+		protected.GET("/attachments/:attachmentId/url", attachmentHandler.GetDownloadURL)
+		protected.DELETE("/attachments/:attachmentId", attachmentHandler.DeleteAttachment)
+
+		// Statement imports
+		protected.POST("/import-profiles", importHandler.CreatePersonalProfile)
+		protected.GET("/import-profiles", importHandler.GetPersonalProfiles)
+		protected.DELETE("/import-profiles/:profileId", importHandler.DeleteProfile)
+		protected.POST("/groups/:groupId/import-profiles", importHandler.CreateGroupProfile)
+		protected.GET("/groups/:groupId/import-profiles", importHandler.GetGroupProfiles)
+		protected.POST("/imports", importHandler.PreviewPersonalImport)
+		protected.POST("/groups/:groupId/imports", importHandler.PreviewGroupImport)
+		protected.GET("/imports/:importId", importHandler.GetImport)
+		protected.POST("/imports/:importId/commit", importHandler.CommitImport)
+
+		// Categorization rules
+		protected.POST("/rules", ruleHandler.CreatePersonalRule)
+		protected.GET("/rules", ruleHandler.GetPersonalRules)
+		protected.PUT("/rules/:ruleId", ruleHandler.UpdateRule)
+		protected.DELETE("/rules/:ruleId", ruleHandler.DeleteRule)
+		protected.POST("/rules/:ruleId/dry-run", ruleHandler.DryRunRule)
+		protected.POST("/rules/apply", ruleHandler.ApplyPersonalRules)
+		protected.POST("/groups/:groupId/rules", ruleHandler.CreateGroupRule)
+		protected.GET("/groups/:groupId/rules", ruleHandler.GetGroupRules)
+		protected.POST("/groups/:groupId/rules/apply", ruleHandler.ApplyGroupRules)
+
+		// Budgets
+		protected.POST("/budgets", budgetHandler.CreatePersonalBudget)
+		protected.GET("/budgets", budgetHandler.GetPersonalBudgets)
+		protected.GET("/budgets/report", budgetHandler.GetPersonalBudgetReport)
+		protected.PUT("/budgets/:budgetId", budgetHandler.UpdateBudget)
+		protected.DELETE("/budgets/:budgetId", budgetHandler.DeleteBudget)
+		protected.POST("/groups/:groupId/budgets", budgetHandler.CreateGroupBudget)
+		protected.GET("/groups/:groupId/budgets", budgetHandler.GetGroupBudgets)
+		protected.GET("/groups/:groupId/budgets/report", budgetHandler.GetGroupBudgetReport)
+
+		// Forecasts
+		protected.GET("/forecast", forecastHandler.GetPersonalForecast)
+		protected.GET("/groups/:groupId/forecast", forecastHandler.GetGroupForecast)
+
+		// Audit logs
+		protected.GET("/audit-logs", auditLogHandler.GetPersonalAuditLogs)
+		protected.GET("/groups/:groupId/audit-logs", auditLogHandler.GetGroupAuditLogs)
+
+		// Exchange rates
+		protected.GET("/exchange-rates", exchangeRateHandler.GetRate)
+		protected.POST("/exchange-rates/import", exchangeRateHandler.ImportRates)
+
 		// Reports
 		protected.GET("/reports/personal/monthly", reportHandler.GetPersonalMonthlyReport)
+		protected.GET("/reports/personal/monthly.pdf", reportHandler.GetPersonalMonthlyStatementPDF)
 		protected.POST("/reports/personal/range", reportHandler.GetPersonalDateRangeReport)
 		protected.GET("/groups/:groupId/reports/monthly", reportHandler.GetGroupMonthlyReport)
+		protected.GET("/groups/:groupId/reports/monthly.pdf", reportHandler.GetGroupMonthlyStatementPDF)
 		protected.POST("/groups/:groupId/reports/range", reportHandler.GetGroupDateRangeReport)
 		protected.POST("/reports/categories", reportHandler.GetCategoryBreakdown)
 		protected.POST("/reports/sources", reportHandler.GetSourceBreakdown)
+		protected.POST("/reports/tags", reportHandler.GetTagBreakdown)
 	}
 
 	// Start server
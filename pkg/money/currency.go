@@ -0,0 +1,110 @@
+package money
+
+import "strings"
+
+// Currency describes an ISO 4217 currency. MinorUnits is the number of
+// decimal places amounts are stored with: 2 for USD (cents), 0 for RWF or
+// JPY, 3 for BHD.
+type Currency struct {
+	Code       string
+	MinorUnits int
+	Symbol     string
+}
+
+// DefaultCurrency is used for users and groups that do not pick one. It is
+// USD so amounts stored before currencies existed keep their cent semantics;
+// main overrides it from the configuration.
+var DefaultCurrency = "USD"
+
+var currencies = map[string]Currency{
+	"AED": {Code: "AED", MinorUnits: 2, Symbol: "د.إ"},
+	"AUD": {Code: "AUD", MinorUnits: 2, Symbol: "A$"},
+	"BHD": {Code: "BHD", MinorUnits: 3, Symbol: "BD"},
+	"BIF": {Code: "BIF", MinorUnits: 0, Symbol: "FBu"},
+	"BRL": {Code: "BRL", MinorUnits: 2, Symbol: "R$"},
+	"CAD": {Code: "CAD", MinorUnits: 2, Symbol: "CA$"},
+	"CDF": {Code: "CDF", MinorUnits: 2, Symbol: "FC"},
+	"CHF": {Code: "CHF", MinorUnits: 2, Symbol: "CHF"},
+	"CLP": {Code: "CLP", MinorUnits: 0, Symbol: "CLP$"},
+	"CNY": {Code: "CNY", MinorUnits: 2, Symbol: "CN¥"},
+	"CZK": {Code: "CZK", MinorUnits: 2, Symbol: "Kč"},
+	"DJF": {Code: "DJF", MinorUnits: 0, Symbol: "Fdj"},
+	"DKK": {Code: "DKK", MinorUnits: 2, Symbol: "kr"},
+	"EGP": {Code: "EGP", MinorUnits: 2, Symbol: "E£"},
+	"ETB": {Code: "ETB", MinorUnits: 2, Symbol: "Br"},
+	"EUR": {Code: "EUR", MinorUnits: 2, Symbol: "€"},
+	"GBP": {Code: "GBP", MinorUnits: 2, Symbol: "£"},
+	"GHS": {Code: "GHS", MinorUnits: 2, Symbol: "GH₵"},
+	"GNF": {Code: "GNF", MinorUnits: 0, Symbol: "FG"},
+	"HKD": {Code: "HKD", MinorUnits: 2, Symbol: "HK$"},
+	"HUF": {Code: "HUF", MinorUnits: 2, Symbol: "Ft"},
+	"IDR": {Code: "IDR", MinorUnits: 2, Symbol: "Rp"},
+	"ILS": {Code: "ILS", MinorUnits: 2, Symbol: "₪"},
+	"INR": {Code: "INR", MinorUnits: 2, Symbol: "₹"},
+	"IQD": {Code: "IQD", MinorUnits: 3, Symbol: "ع.د"},
+	"ISK": {Code: "ISK", MinorUnits: 0, Symbol: "kr"},
+	"JOD": {Code: "JOD", MinorUnits: 3, Symbol: "JD"},
+	"JPY": {Code: "JPY", MinorUnits: 0, Symbol: "¥"},
+	"KES": {Code: "KES", MinorUnits: 2, Symbol: "KSh"},
+	"KMF": {Code: "KMF", MinorUnits: 0, Symbol: "CF"},
+	"KRW": {Code: "KRW", MinorUnits: 0, Symbol: "₩"},
+	"KWD": {Code: "KWD", MinorUnits: 3, Symbol: "KD"},
+	"LYD": {Code: "LYD", MinorUnits: 3, Symbol: "LD"},
+	"MAD": {Code: "MAD", MinorUnits: 2, Symbol: "DH"},
+	"MGA": {Code: "MGA", MinorUnits: 2, Symbol: "Ar"},
+	"MWK": {Code: "MWK", MinorUnits: 2, Symbol: "MK"},
+	"MXN": {Code: "MXN", MinorUnits: 2, Symbol: "MX$"},
+	"MZN": {Code: "MZN", MinorUnits: 2, Symbol: "MT"},
+	"NGN": {Code: "NGN", MinorUnits: 2, Symbol: "₦"},
+	"NOK": {Code: "NOK", MinorUnits: 2, Symbol: "kr"},
+	"NZD": {Code: "NZD", MinorUnits: 2, Symbol: "NZ$"},
+	"OMR": {Code: "OMR", MinorUnits: 3, Symbol: "ر.ع."},
+	"PHP": {Code: "PHP", MinorUnits: 2, Symbol: "₱"},
+	"PKR": {Code: "PKR", MinorUnits: 2, Symbol: "Rs"},
+	"PLN": {Code: "PLN", MinorUnits: 2, Symbol: "zł"},
+	"PYG": {Code: "PYG", MinorUnits: 0, Symbol: "₲"},
+	"QAR": {Code: "QAR", MinorUnits: 2, Symbol: "QR"},
+	"RUB": {Code: "RUB", MinorUnits: 2, Symbol: "₽"},
+	"RWF": {Code: "RWF", MinorUnits: 0, Symbol: "FRw"},
+	"SAR": {Code: "SAR", MinorUnits: 2, Symbol: "SR"},
+	"SEK": {Code: "SEK", MinorUnits: 2, Symbol: "kr"},
+	"SGD": {Code: "SGD", MinorUnits: 2, Symbol: "S$"},
+	"SOS": {Code: "SOS", MinorUnits: 2, Symbol: "Sh"},
+	"SSP": {Code: "SSP", MinorUnits: 2, Symbol: "SSP"},
+	"THB": {Code: "THB", MinorUnits: 2, Symbol: "฿"},
+	"TND": {Code: "TND", MinorUnits: 3, Symbol: "DT"},
+	"TRY": {Code: "TRY", MinorUnits: 2, Symbol: "₺"},
+	"TZS": {Code: "TZS", MinorUnits: 2, Symbol: "TSh"},
+	"UAH": {Code: "UAH", MinorUnits: 2, Symbol: "₴"},
+	"UGX": {Code: "UGX", MinorUnits: 0, Symbol: "USh"},
+	"USD": {Code: "USD", MinorUnits: 2, Symbol: "$"},
+	"UYU": {Code: "UYU", MinorUnits: 2, Symbol: "$U"},
+	"VND": {Code: "VND", MinorUnits: 0, Symbol: "₫"},
+	"VUV": {Code: "VUV", MinorUnits: 0, Symbol: "VT"},
+	"XAF": {Code: "XAF", MinorUnits: 0, Symbol: "FCFA"},
+	"XOF": {Code: "XOF", MinorUnits: 0, Symbol: "CFA"},
+	"XPF": {Code: "XPF", MinorUnits: 0, Symbol: "CFPF"},
+	"ZAR": {Code: "ZAR", MinorUnits: 2, Symbol: "R"},
+	"ZMW": {Code: "ZMW", MinorUnits: 2, Symbol: "ZK"},
+}
+
+// LookupCurrency returns the currency for an ISO 4217 code, ignoring case.
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return currency, ok
+}
+
+// IsSupported reports whether amounts in the currency can be stored.
+func IsSupported(code string) bool {
+	_, ok := LookupCurrency(code)
+	return ok
+}
+
+// NormalizeCode returns the upper-case code, or DefaultCurrency when empty.
+func NormalizeCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return DefaultCurrency
+	}
+	return code
+}
@@ -0,0 +1,346 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// Parse reads an amount written by a person or a bank, such as "$1,234.56",
+// "1.234,56 €", "(12.00)", "-5 USD" or "RWF 1 500", into exact minor units.
+//
+// With a locale the decimal and grouping separators are taken from it. A nil
+// locale guesses them: when both '.' and ',' appear the last one is the
+// decimal separator, a separator that repeats groups digits, and a single
+// one followed by exactly three digits is read as grouping unless the
+// currency has three decimals.
+//
+// The currency may be given by a code or a symbol in the input. It must then
+// match currency; when currency is empty the input's currency is used, or
+// DefaultCurrency if there is none.
+func Parse(input, currency string, locale *Locale) (Money, error) {
+	text := strings.TrimSpace(input)
+	if text == "" {
+		return Money{}, fmt.Errorf("%w: empty", ErrInvalidAmount)
+	}
+
+	negative := false
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		// Accounting style negative
+		negative = true
+		text = strings.TrimSpace(text[1 : len(text)-1])
+	}
+
+	first := strings.IndexFunc(text, isDigit)
+	last := strings.LastIndexFunc(text, isDigit)
+	if first < 0 {
+		return Money{}, fmt.Errorf("%w: %q has no digits", ErrInvalidAmount, input)
+	}
+	// Include a leading separator as in ".50"
+	if first > 0 && (text[first-1] == '.' || text[first-1] == ',') {
+		first--
+	}
+	// and a trailing decimal separator with no decimals as in "5."
+	if last+1 < len(text) {
+		next := rune(text[last+1])
+		if (locale == nil && (next == '.' || next == ',')) || (locale != nil && next == locale.Decimal) {
+			last++
+		}
+	}
+
+	body := text[first : last+1]
+	sign, affix, err := splitAffixes(text[:first], text[last+1:])
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", err, input)
+	}
+	if sign < 0 {
+		if negative {
+			return Money{}, fmt.Errorf("%w: %q has two signs", ErrInvalidAmount, input)
+		}
+		negative = true
+	}
+
+	code, err := resolveCurrency(affix, currency)
+	if err != nil {
+		return Money{}, err
+	}
+	c, _ := LookupCurrency(code)
+
+	if locale == nil {
+		guessed := guessSeparators(body, c.MinorUnits)
+		locale = &guessed
+	}
+
+	intPart, fracPart, err := splitNumber(body, locale)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", err, input)
+	}
+
+	// Extra decimals are only allowed when they are zeros
+	if len(fracPart) > c.MinorUnits {
+		if strings.Trim(fracPart[c.MinorUnits:], "0") != "" {
+			return Money{}, fmt.Errorf("%w: %q has more than %d decimals for %s", ErrInvalidAmount, input, c.MinorUnits, c.Code)
+		}
+		fracPart = fracPart[:c.MinorUnits]
+	}
+	fracPart += strings.Repeat("0", c.MinorUnits-len(fracPart))
+
+	value, ok := new(big.Int).SetString(intPart+fracPart, 10)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, input)
+	}
+	if negative {
+		value.Neg(value)
+	}
+	if !value.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+
+	return Money{Amount: value.Int64(), Currency: c.Code}, nil
+}
+
+// Format renders the amount for display in the locale, e.g. "$1,234.56" for
+// en-US or "1.234,56 €" for de-DE.
+func (m Money) Format(locale Locale) string {
+	symbol := m.Currency
+	if c, ok := LookupCurrency(m.Currency); ok && c.Symbol != "" {
+		symbol = c.Symbol
+	}
+
+	number := Money{Amount: m.Amount, Currency: m.Currency}
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+		number.Amount = -m.Amount
+	}
+
+	space := ""
+	if locale.SymbolSpace {
+		space = " "
+	}
+
+	if locale.SymbolFirst {
+		return sign + symbol + space + number.FormatNumber(locale)
+	}
+	return sign + number.FormatNumber(locale) + space + symbol
+}
+
+// FormatNumber renders the amount with the locale's separators but without a
+// currency symbol, e.g. "1 234,56" for fr-FR.
+func (m Money) FormatNumber(locale Locale) string {
+	decimal := m.Decimal()
+
+	sign := ""
+	if strings.HasPrefix(decimal, "-") {
+		sign = "-"
+		decimal = decimal[1:]
+	}
+
+	intPart, fracPart, hasFraction := strings.Cut(decimal, ".")
+
+	var grouped strings.Builder
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 && locale.Group != 0 {
+			grouped.WriteRune(locale.Group)
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if !hasFraction {
+		return sign + grouped.String()
+	}
+
+	separator := locale.Decimal
+	if separator == 0 {
+		separator = '.'
+	}
+	return sign + grouped.String() + string(separator) + fracPart
+}
+
+// splitAffixes separates the text around the number into a sign and at most
+// one currency code or symbol.
+func splitAffixes(prefix, suffix string) (int, string, error) {
+	sign := 0
+	var tokens []string
+
+	for _, part := range []string{prefix, suffix} {
+		var token strings.Builder
+		for _, r := range part {
+			switch {
+			case r == '-' || r == '−':
+				if sign != 0 {
+					return 0, "", ErrInvalidAmount
+				}
+				sign = -1
+			case r == '+':
+				if sign != 0 {
+					return 0, "", ErrInvalidAmount
+				}
+				sign = 1
+			case unicode.IsSpace(r):
+				continue
+			default:
+				token.WriteRune(r)
+			}
+		}
+		if token.Len() > 0 {
+			tokens = append(tokens, token.String())
+		}
+	}
+
+	switch len(tokens) {
+	case 0:
+		return sign, "", nil
+	case 1:
+		return sign, tokens[0], nil
+	default:
+		return 0, "", ErrInvalidAmount
+	}
+}
+
+// resolveCurrency matches the code or symbol found in the input against the
+// expected currency.
+func resolveCurrency(affix, expected string) (string, error) {
+	if expected != "" {
+		c, ok := LookupCurrency(expected)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrUnknownCurrency, expected)
+		}
+		expected = c.Code
+
+		// A bare "$" or "¥" is fine for any currency written with it
+		if affix == "" || strings.EqualFold(affix, c.Code) || affix == c.Symbol ||
+			((affix == "$" || affix == "¥") && strings.HasSuffix(c.Symbol, affix)) {
+			return expected, nil
+		}
+	}
+
+	if affix == "" {
+		return NormalizeCode(""), nil
+	}
+
+	found := ""
+	if c, ok := LookupCurrency(affix); ok && len(affix) == 3 {
+		found = c.Code
+	} else {
+		for _, c := range currencies {
+			if c.Symbol == affix {
+				if found != "" {
+					return "", fmt.Errorf("%w: ambiguous symbol %q", ErrUnknownCurrency, affix)
+				}
+				found = c.Code
+			}
+		}
+	}
+
+	if found == "" {
+		return "", fmt.Errorf("%w: %q", ErrUnknownCurrency, affix)
+	}
+	if expected != "" && found != expected {
+		return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, found, expected)
+	}
+	return found, nil
+}
+
+// guessSeparators infers the separators of a number written in an unknown
+// locale.
+func guessSeparators(body string, minorUnits int) Locale {
+	lastDot := strings.LastIndex(body, ".")
+	lastComma := strings.LastIndex(body, ",")
+
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastDot > lastComma {
+			return Locale{Decimal: '.', Group: ','}
+		}
+		return Locale{Decimal: ',', Group: '.'}
+	case lastDot < 0 && lastComma < 0:
+		return Locale{Decimal: '.', Group: ','}
+	}
+
+	separator, other := '.', ','
+	index := lastDot
+	if lastComma >= 0 {
+		separator, other = ',', '.'
+		index = lastComma
+	}
+
+	repeated := strings.Count(body, string(separator)) > 1
+	leadingZero := strings.TrimLeft(body[:index], " ") == "0"
+	threeDigits := len(body)-index-1 == 3
+	if repeated || (threeDigits && minorUnits != 3 && index > 0 && !leadingZero) {
+		return Locale{Decimal: other, Group: separator}
+	}
+	return Locale{Decimal: separator, Group: other}
+}
+
+// splitNumber returns the integer and fraction digits of a number, checking
+// that grouping separators only appear every three digits.
+func splitNumber(body string, locale *Locale) (string, string, error) {
+	var intPart, fracPart strings.Builder
+	var groups []int
+	seenDecimal := false
+	run := 0
+
+	for _, r := range body {
+		switch {
+		case isDigit(r):
+			if seenDecimal {
+				fracPart.WriteRune(r)
+			} else {
+				intPart.WriteRune(r)
+				run++
+			}
+		case r == locale.Decimal:
+			if seenDecimal {
+				return "", "", ErrInvalidAmount
+			}
+			seenDecimal = true
+		case r != locale.Decimal && isGroupSeparator(r, locale):
+			if seenDecimal || run == 0 {
+				return "", "", ErrInvalidAmount
+			}
+			groups = append(groups, run)
+			run = 0
+		default:
+			return "", "", ErrInvalidAmount
+		}
+	}
+
+	if len(groups) > 0 {
+		if groups[0] > 3 || run != 3 {
+			return "", "", ErrInvalidAmount
+		}
+		for _, size := range groups[1:] {
+			if size != 3 {
+				return "", "", ErrInvalidAmount
+			}
+		}
+	}
+
+	digits := intPart.String()
+	if digits == "" {
+		digits = "0"
+	}
+	return digits, fracPart.String(), nil
+}
+
+// isGroupSeparator accepts the locale's separator, any kind of space and,
+// next to a '.' decimal separator, the Swiss apostrophe.
+func isGroupSeparator(r rune, locale *Locale) bool {
+	switch r {
+	case locale.Group, ' ', '\u00a0', '\u202f':
+		return true
+	case '\'', '’':
+		return locale.Decimal == '.'
+	}
+	return false
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
@@ -0,0 +1,179 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	enUS := locales["en-US"]
+	deDE := locales["de-DE"]
+	deCH := locales["de-CH"]
+	dotOnly := Locale{Decimal: '.'}
+
+	tests := []struct {
+		name     string
+		input    string
+		currency string
+		locale   *Locale
+		want     Money
+		wantErr  error
+	}{
+		{name: "cents", input: "0.29", currency: "USD", want: Money{29, "USD"}},
+		{name: "leading separator", input: ".50", currency: "USD", want: Money{50, "USD"}},
+		{name: "trailing separator", input: "5.", currency: "USD", locale: &dotOnly, want: Money{500, "USD"}},
+		{name: "trailing separator guessed", input: "5.", currency: "USD", want: Money{500, "USD"}},
+		{name: "trailing comma separator", input: "5,", currency: "EUR", locale: &deDE, want: Money{500, "EUR"}},
+		{name: "trailing group separator", input: "5,", currency: "USD", locale: &enUS, wantErr: ErrUnknownCurrency},
+
+		{name: "dot decimal guessed", input: "1,234.56", currency: "USD", want: Money{123456, "USD"}},
+		{name: "dot decimal en-US", input: "1,234.56", currency: "USD", locale: &enUS, want: Money{123456, "USD"}},
+		{name: "dot decimal de-DE", input: "1,234.56", currency: "USD", locale: &deDE, wantErr: ErrInvalidAmount},
+		{name: "comma decimal guessed", input: "1.234,56", currency: "EUR", want: Money{123456, "EUR"}},
+		{name: "comma decimal de-DE", input: "1.234,56", currency: "EUR", locale: &deDE, want: Money{123456, "EUR"}},
+		{name: "comma decimal en-US", input: "1.234,56", currency: "EUR", locale: &enUS, wantErr: ErrInvalidAmount},
+		{name: "swiss apostrophe", input: "CHF 1'234.50", currency: "CHF", locale: &deCH, want: Money{123450, "CHF"}},
+		{name: "repeated group separator", input: "1.234.567", currency: "EUR", want: Money{123456700, "EUR"}},
+		{name: "single separator read as grouping", input: "1.234", currency: "USD", want: Money{123400, "USD"}},
+		{name: "single separator with three decimals", input: "1.234", currency: "KWD", want: Money{1234, "KWD"}},
+		{name: "misplaced group separator", input: "12,34.56", currency: "USD", locale: &enUS, wantErr: ErrInvalidAmount},
+
+		{name: "symbol prefix", input: "$1,234.56", want: Money{123456, "USD"}},
+		{name: "symbol suffix", input: "1.234,56 €", want: Money{123456, "EUR"}},
+		{name: "code prefix", input: "USD 12.50", want: Money{1250, "USD"}},
+		{name: "code suffix", input: "12.50 EUR", want: Money{1250, "EUR"}},
+		{name: "code matches currency", input: "KES 100", currency: "KES", want: Money{10000, "KES"}},
+		{name: "bare dollar for other dollar", input: "$10", currency: "CAD", want: Money{1000, "CAD"}},
+		{name: "code mismatch", input: "12.50 EUR", currency: "USD", wantErr: ErrCurrencyMismatch},
+		{name: "unknown code", input: "12 XYZ", wantErr: ErrUnknownCurrency},
+		{name: "two affixes", input: "USD 12 EUR", wantErr: ErrInvalidAmount},
+		{name: "default currency", input: "7", want: Money{700, DefaultCurrency}},
+
+		{name: "minus sign", input: "-5 USD", want: Money{-500, "USD"}},
+		{name: "minus after symbol", input: "$-5.25", want: Money{-525, "USD"}},
+		{name: "unicode minus", input: "−3", currency: "USD", want: Money{-300, "USD"}},
+		{name: "plus sign", input: "+5", currency: "USD", want: Money{500, "USD"}},
+		{name: "accounting negative", input: "(12.00)", currency: "USD", want: Money{-1200, "USD"}},
+		{name: "two signs", input: "-+5", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "accounting and minus", input: "(-5)", currency: "USD", wantErr: ErrInvalidAmount},
+
+		{name: "too many decimals", input: "12.345", currency: "USD", locale: &enUS, wantErr: ErrInvalidAmount},
+		{name: "leading zero keeps decimals", input: "0.125", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "extra zero decimals", input: "12.3400", currency: "USD", locale: &enUS, want: Money{1234, "USD"}},
+		{name: "three decimal currency", input: "1.250", currency: "BHD", locale: &enUS, want: Money{1250, "BHD"}},
+
+		{name: "zero decimal currency", input: "RWF 1 500", want: Money{1500, "RWF"}},
+		{name: "zero decimal grouped", input: "1,500", currency: "JPY", want: Money{1500, "JPY"}},
+		{name: "zero decimal with zeros", input: "1500.00", currency: "JPY", locale: &enUS, want: Money{1500, "JPY"}},
+		{name: "zero decimal with decimals", input: "1500.5", currency: "UGX", locale: &enUS, wantErr: ErrInvalidAmount},
+
+		{name: "empty", input: "  ", currency: "USD", wantErr: ErrInvalidAmount},
+		{name: "no digits", input: "USD", wantErr: ErrInvalidAmount},
+		{name: "out of range", input: "99999999999999999999", currency: "USD", wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.currency, tt.locale)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		money  Money
+		locale string
+		want   string
+	}{
+		{name: "en-US", money: Money{123456, "USD"}, locale: "en-US", want: "$1,234.56"},
+		{name: "en-US negative", money: Money{-500, "USD"}, locale: "en-US", want: "-$5.00"},
+		{name: "en-US zero", money: Money{0, "USD"}, locale: "en-US", want: "$0.00"},
+		{name: "en-US cents", money: Money{29, "USD"}, locale: "en-US", want: "$0.29"},
+		{name: "de-DE", money: Money{123456, "EUR"}, locale: "de-DE", want: "1.234,56 €"},
+		{name: "fr-FR", money: Money{123456, "EUR"}, locale: "fr-FR", want: "1 234,56 €"},
+		{name: "de-CH", money: Money{123450, "CHF"}, locale: "de-CH", want: "CHF 1'234.50"},
+		{name: "en-RW zero decimals", money: Money{1500, "RWF"}, locale: "en-RW", want: "FRw 1,500"},
+		{name: "ja-JP zero decimals", money: Money{1500, "JPY"}, locale: "ja-JP", want: "¥1,500"},
+		{name: "three decimals", money: Money{1250, "KWD"}, locale: "en-US", want: "KD1.250"},
+		{name: "millions", money: Money{123456789, "USD"}, locale: "en-US", want: "$1,234,567.89"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locale, ok := LookupLocale(tt.locale)
+			if !ok {
+				t.Fatalf("unknown locale %s", tt.locale)
+			}
+			if got := tt.money.Format(locale); got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatParseRoundTrip(t *testing.T) {
+	amounts := []Money{{123456, "USD"}, {-98765, "EUR"}, {1500, "RWF"}, {1250, "KWD"}, {7, "USD"}}
+	for _, tag := range []string{"en-US", "de-DE", "fr-FR", "de-CH", "rw-RW"} {
+		locale, _ := LookupLocale(tag)
+		for _, amount := range amounts {
+			text := amount.Format(locale)
+			got, err := Parse(text, amount.Currency, &locale)
+			if err != nil {
+				t.Errorf("%s: Parse(%q) unexpected error: %v", tag, text, err)
+				continue
+			}
+			if got != amount {
+				t.Errorf("%s: Parse(%q) = %+v, want %+v", tag, text, got, amount)
+			}
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "1.25", want: "5/4"},
+		{input: " 1300 ", want: "1300/1"},
+		{input: "0.000769", want: "769/1000000"},
+		{input: "0", wantErr: true},
+		{input: "-1.5", wantErr: true},
+		{input: "1/3", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "abc", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRate(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidRate) {
+					t.Fatalf("ParseRate(%q) error = %v, want %v", tt.input, err, ErrInvalidRate)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseRate(%q) unexpected error: %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseRate(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
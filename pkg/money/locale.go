@@ -0,0 +1,80 @@
+package money
+
+import "strings"
+
+// Locale describes how amounts are written in a region: the decimal and
+// grouping separators and where the currency symbol goes.
+type Locale struct {
+	Tag         string
+	Decimal     rune
+	Group       rune
+	SymbolFirst bool // "$1.00" rather than "1.00 $"
+	SymbolSpace bool // a space between the symbol and the number
+}
+
+var locales = map[string]Locale{
+	"en-US": {Tag: "en-US", Decimal: '.', Group: ',', SymbolFirst: true},
+	"en-GB": {Tag: "en-GB", Decimal: '.', Group: ',', SymbolFirst: true},
+	"en-KE": {Tag: "en-KE", Decimal: '.', Group: ',', SymbolFirst: true},
+	"en-RW": {Tag: "en-RW", Decimal: '.', Group: ',', SymbolFirst: true, SymbolSpace: true},
+	"en-UG": {Tag: "en-UG", Decimal: '.', Group: ',', SymbolFirst: true},
+	"sw-KE": {Tag: "sw-KE", Decimal: '.', Group: ',', SymbolFirst: true, SymbolSpace: true},
+	"sw-TZ": {Tag: "sw-TZ", Decimal: '.', Group: ',', SymbolFirst: true, SymbolSpace: true},
+	"rw-RW": {Tag: "rw-RW", Decimal: ',', Group: '.', SymbolFirst: true, SymbolSpace: true},
+	"fr-FR": {Tag: "fr-FR", Decimal: ',', Group: ' ', SymbolSpace: true},
+	"fr-BE": {Tag: "fr-BE", Decimal: ',', Group: ' ', SymbolSpace: true},
+	"fr-RW": {Tag: "fr-RW", Decimal: ',', Group: ' ', SymbolSpace: true},
+	"fr-CH": {Tag: "fr-CH", Decimal: ',', Group: ' ', SymbolSpace: true},
+	"de-DE": {Tag: "de-DE", Decimal: ',', Group: '.', SymbolSpace: true},
+	"de-AT": {Tag: "de-AT", Decimal: ',', Group: ' ', SymbolFirst: true, SymbolSpace: true},
+	"de-CH": {Tag: "de-CH", Decimal: '.', Group: '\'', SymbolFirst: true, SymbolSpace: true},
+	"es-ES": {Tag: "es-ES", Decimal: ',', Group: '.', SymbolSpace: true},
+	"es-MX": {Tag: "es-MX", Decimal: '.', Group: ',', SymbolFirst: true},
+	"it-IT": {Tag: "it-IT", Decimal: ',', Group: '.', SymbolSpace: true},
+	"nl-NL": {Tag: "nl-NL", Decimal: ',', Group: '.', SymbolFirst: true, SymbolSpace: true},
+	"pt-BR": {Tag: "pt-BR", Decimal: ',', Group: '.', SymbolFirst: true, SymbolSpace: true},
+	"pt-PT": {Tag: "pt-PT", Decimal: ',', Group: ' ', SymbolSpace: true},
+	"ja-JP": {Tag: "ja-JP", Decimal: '.', Group: ',', SymbolFirst: true},
+	"zh-CN": {Tag: "zh-CN", Decimal: '.', Group: ',', SymbolFirst: true},
+}
+
+// defaultRegions picks a locale when only a language is given.
+var defaultRegions = map[string]string{
+	"en": "en-US",
+	"sw": "sw-KE",
+	"rw": "rw-RW",
+	"fr": "fr-FR",
+	"de": "de-DE",
+	"es": "es-ES",
+	"it": "it-IT",
+	"nl": "nl-NL",
+	"pt": "pt-BR",
+	"ja": "ja-JP",
+	"zh": "zh-CN",
+}
+
+// DefaultLocale formats amounts in responses; main overrides it from the
+// configuration.
+var DefaultLocale = locales["en-US"]
+
+// LookupLocale finds a locale by tag such as "fr-FR", "fr_fr" or "fr". A
+// language alone, or with an unknown region, maps to its default region.
+func LookupLocale(tag string) (Locale, bool) {
+	tag = strings.ReplaceAll(strings.TrimSpace(tag), "_", "-")
+	if tag == "" {
+		return Locale{}, false
+	}
+
+	parts := strings.SplitN(tag, "-", 2)
+	language := strings.ToLower(parts[0])
+	if len(parts) == 2 {
+		if locale, ok := locales[language+"-"+strings.ToUpper(parts[1])]; ok {
+			return locale, true
+		}
+	}
+
+	if region, ok := defaultRegions[language]; ok {
+		return locales[region], true
+	}
+	return Locale{}, false
+}
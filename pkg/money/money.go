@@ -0,0 +1,156 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrUnknownCurrency  = errors.New("unknown currency")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrInvalidRate      = errors.New("invalid exchange rate")
+	ErrOverflow         = errors.New("amount out of range")
+)
+
+// Money is an amount in the minor units of its currency, e.g. 1050 USD is
+// $10.50 while 1050 RWF is 1,050 FRw.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func New(amount int64, currency string) (Money, error) {
+	c, ok := LookupCurrency(currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, currency)
+	}
+	return Money{Amount: amount, Currency: c.Code}, nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// Decimal renders the amount with the currency's number of decimal places
+// and no grouping, e.g. "10.50" for USD and "1050" for RWF.
+func (m Money) Decimal() string {
+	units := 2
+	if c, ok := LookupCurrency(m.Currency); ok {
+		units = c.MinorUnits
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+
+	digits := new(big.Int).Abs(big.NewInt(amount)).String()
+	if units == 0 {
+		return sign + digits
+	}
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-units] + "." + digits[len(digits)-units:]
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+// ParseRate parses a positive decimal exchange rate such as "1350.25".
+// Rates are kept as exact fractions so conversions do not pick up float error.
+func ParseRate(value string) (*big.Rat, error) {
+	rate, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || rate.Sign() <= 0 || strings.ContainsAny(value, "/eE") {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRate, value)
+	}
+	return rate, nil
+}
+
+// Convert turns m into the target currency, where rate is the price of one
+// major unit of m's currency in major units of the target. The result is
+// rounded half away from zero to the target's minor units.
+func Convert(m Money, to string, rate *big.Rat) (Money, error) {
+	from, ok := LookupCurrency(m.Currency)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, m.Currency)
+	}
+	target, ok := LookupCurrency(to)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %s", ErrUnknownCurrency, to)
+	}
+	if rate == nil || rate.Sign() <= 0 {
+		return Money{}, ErrInvalidRate
+	}
+
+	// amount / 10^from * rate * 10^to
+	value := new(big.Rat).SetInt64(m.Amount)
+	value.Mul(value, rate)
+	value.Mul(value, new(big.Rat).SetInt(pow10(target.MinorUnits)))
+	value.Quo(value, new(big.Rat).SetInt(pow10(from.MinorUnits)))
+
+	amount, err := roundRat(value)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: amount, Currency: target.Code}, nil
+}
+
+// RateBetween returns the rate that turns from into to, in the same major
+// unit terms Convert takes. Both amounts must be positive.
+func RateBetween(from, to Money) (*big.Rat, error) {
+	source, ok := LookupCurrency(from.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, from.Currency)
+	}
+	target, ok := LookupCurrency(to.Currency)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownCurrency, to.Currency)
+	}
+	if from.Amount <= 0 || to.Amount <= 0 {
+		return nil, ErrInvalidRate
+	}
+
+	// (to / 10^target) / (from / 10^source)
+	rate := new(big.Rat).SetFrac(big.NewInt(to.Amount), big.NewInt(from.Amount))
+	rate.Mul(rate, new(big.Rat).SetInt(pow10(source.MinorUnits)))
+	rate.Quo(rate, new(big.Rat).SetInt(pow10(target.MinorUnits)))
+	return rate, nil
+}
+
+// roundRat rounds half away from zero and checks the result fits in int64.
+func roundRat(value *big.Rat) (int64, error) {
+	num := new(big.Int).Abs(value.Num())
+	den := value.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if value.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	if !quo.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quo.Int64(), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
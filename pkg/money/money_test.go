@@ -0,0 +1,40 @@
+package money
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestRateBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		from Money
+		to   Money
+		want *big.Rat
+	}{
+		{"same minor units", Money{Amount: 1000, Currency: "EUR"}, Money{Amount: 1100, Currency: "USD"}, big.NewRat(11, 10)},
+		{"into zero minor units", Money{Amount: 1000, Currency: "USD"}, Money{Amount: 13500, Currency: "RWF"}, big.NewRat(1350, 1)},
+		{"from zero minor units", Money{Amount: 13500, Currency: "RWF"}, Money{Amount: 1000, Currency: "USD"}, big.NewRat(1, 1350)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RateBetween(tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("RateBetween() error = %v", err)
+			}
+			if got.Cmp(tt.want) != 0 {
+				t.Errorf("RateBetween() = %s, want %s", got.RatString(), tt.want.RatString())
+			}
+
+			back, err := Convert(tt.from, tt.to.Currency, got)
+			if err != nil || back != tt.to {
+				t.Errorf("Convert() with implied rate = %v, %v; want %v", back, err, tt.to)
+			}
+		})
+	}
+
+	if _, err := RateBetween(Money{Amount: 0, Currency: "USD"}, Money{Amount: 100, Currency: "EUR"}); err == nil {
+		t.Error("RateBetween() with a zero amount should fail")
+	}
+}
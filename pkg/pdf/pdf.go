@@ -0,0 +1,236 @@
+// Package pdf writes simple text documents as PDF without external tools.
+// It supports the standard Helvetica faces, text, lines and filled
+// rectangles, which is enough for statements and tables. Text is encoded as
+// WinAnsi, so characters outside Western European scripts print as "?".
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Page sizes in points (1/72 inch).
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+)
+
+var fontNames = []string{"Helvetica", "Helvetica-Bold"}
+
+// Document is an in-memory PDF. Coordinates start at the top-left corner of
+// the page and grow downwards, unlike raw PDF.
+type Document struct {
+	Width  float64
+	Height float64
+
+	pages    []*bytes.Buffer
+	font     Font
+	fontSize float64
+}
+
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height, font: Helvetica, fontSize: 10}
+}
+
+// AddPage starts a new page; drawing calls go to the last page.
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, new(bytes.Buffer))
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) SetFont(font Font, size float64) {
+	d.font = font
+	d.fontSize = size
+}
+
+// Text draws s with its baseline at y.
+func (d *Document) Text(x, y float64, s string) {
+	fmt.Fprintf(d.page(), "BT /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		d.font+1, num(d.fontSize), num(x), num(d.Height-y), escape(encode(s)))
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y float64, s string) {
+	d.Text(x-d.StringWidth(s), y, s)
+}
+
+// Line draws a line of the given width in points.
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.page(), "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(d.Height-y1), num(x2), num(d.Height-y2))
+}
+
+// FillRect fills a rectangle with a grey level from 0 (black) to 1 (white).
+func (d *Document) FillRect(x, y, width, height, grey float64) {
+	fmt.Fprintf(d.page(), "q %s g %s %s %s %s re f Q\n",
+		num(grey), num(x), num(d.Height-y-height), num(width), num(height))
+}
+
+// StringWidth measures s in the current font.
+func (d *Document) StringWidth(s string) float64 {
+	widths := helveticaWidths
+	if d.font == HelveticaBold {
+		widths = helveticaBoldWidths
+	}
+
+	encoded := encode(s)
+	total := 0
+	for i := 0; i < len(encoded); i++ {
+		if b := encoded[i]; b >= 32 && b < 127 {
+			total += widths[b-32]
+		} else {
+			// Accented letters and symbols are close to a digit's width
+			total += 556
+		}
+	}
+	return float64(total) * d.fontSize / 1000
+}
+
+// Truncate shortens s with an ellipsis so it fits in width.
+func (d *Document) Truncate(s string, width float64) string {
+	if d.StringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && d.StringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// Write serializes the document.
+func (d *Document) Write(w io.Writer) error {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3-4: fonts, then a page and its content
+	// stream per page
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+
+	for i, content := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			num(d.Width), num(d.Height), 6+2*i))
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(content.Bytes()); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", compressed.Len(), compressed.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+func (d *Document) page() *bytes.Buffer {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func num(value float64) string {
+	s := fmt.Sprintf("%.2f", value)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-" {
+		return "0"
+	}
+	return s
+}
+
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", `\r`, "\n", `\n`).Replace(s)
+}
+
+// winAnsiExtras maps the characters WinAnsi places in 0x80-0x9F.
+var winAnsiExtras = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+	'\u2009': ' ', '\u202f': ' ',
+}
+
+// encode converts s to WinAnsi bytes.
+func encode(s string) string {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 32:
+			continue
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiExtras[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return string(out)
+}
+
+// Advance widths of the printable ASCII characters, from the Adobe font
+// metrics of the standard fonts.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}